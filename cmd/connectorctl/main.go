@@ -0,0 +1,307 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"github.com/instill-ai/connector-backend/pkg/constant"
+	"github.com/instill-ai/connector-backend/pkg/manifest"
+
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+const usage = `connectorctl keeps the connector resources of a namespace as YAML manifests.
+
+Usage:
+  connectorctl export [flags]   write the manifests of the live connectors
+  connectorctl apply [flags]    create, update and delete connectors to converge to the manifests
+
+Flags:
+`
+
+// options are the flags shared by the commands
+type options struct {
+	address   string
+	namespace string
+	userUID   string
+	token     string
+	file      string
+	dryRun    bool
+	timeout   time.Duration
+}
+
+func parseOptions(command string, args []string) *options {
+	opts := &options{}
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	flags.StringVar(&opts.address, "address", "localhost:8082", "address of the connector public gRPC server")
+	flags.StringVar(&opts.namespace, "namespace", "", "namespace of the connectors, e.g. users/admin")
+	flags.StringVar(&opts.userUID, "user-uid", "", "uid of the user, sent in the jwt-sub header when the server is reached directly")
+	flags.StringVar(&opts.token, "token", "", "API token, sent as a bearer authorization header")
+	flags.StringVar(&opts.file, "f", "", "manifest file, or directory of .yaml files for apply; export writes to stdout if empty")
+	flags.BoolVar(&opts.dryRun, "dry-run", false, "print the plan of apply without changing the connectors")
+	flags.DurationVar(&opts.timeout, "timeout", time.Minute, "timeout of the command")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	if opts.namespace == "" {
+		log.Fatal("--namespace is required")
+	}
+	return opts
+}
+
+// outgoingContext carries the identity of the user to the server
+func outgoingContext(ctx context.Context, opts *options) context.Context {
+	md := metadata.MD{}
+	if opts.userUID != "" {
+		md.Set(constant.HeaderUserUIDKey, opts.userUID)
+	}
+	if opts.token != "" {
+		md.Set("authorization", "Bearer "+opts.token)
+	}
+	return metadata.NewOutgoingContext(ctx, md)
+}
+
+// liveManifests returns the manifests of the connectors owned by the namespace along with their state
+func liveManifests(ctx context.Context, client connectorPB.ConnectorPublicServiceClient, namespace string) ([]*manifest.Manifest, map[string]connectorPB.ConnectorResource_State, error) {
+
+	manifests := []*manifest.Manifest{}
+	states := map[string]connectorPB.ConnectorResource_State{}
+	view := connectorPB.View_VIEW_FULL
+	pageSize := int32(100)
+	pageToken := ""
+	for {
+		resp, err := client.ListUserConnectorResources(ctx, &connectorPB.ListUserConnectorResourcesRequest{
+			Parent:    namespace,
+			View:      &view,
+			PageSize:  &pageSize,
+			PageToken: &pageToken,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, connectorResource := range resp.GetConnectorResources() {
+			if connectorResource.GetUser() != namespace && connectorResource.GetOrg() != namespace {
+				continue
+			}
+			manifests = append(manifests, manifest.FromConnectorResource(connectorResource))
+			states[connectorResource.GetId()] = connectorResource.GetState()
+		}
+
+		pageToken = resp.GetNextPageToken()
+		if pageToken == "" {
+			return manifests, states, nil
+		}
+	}
+}
+
+// readManifests reads the manifests of a file or of the .yaml and .yml files of a directory
+func readManifests(path string) ([]*manifest.Manifest, error) {
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	paths := []string{path}
+	if info.IsDir() {
+		paths = []string{}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if ext := filepath.Ext(entry.Name()); !entry.IsDir() && (ext == ".yaml" || ext == ".yml") {
+				paths = append(paths, filepath.Join(path, entry.Name()))
+			}
+		}
+		sort.Strings(paths)
+	}
+
+	manifests := []*manifest.Manifest{}
+	for _, p := range paths {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		m, err := manifest.Unmarshal(b)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+		manifests = append(manifests, m...)
+	}
+	return manifests, nil
+}
+
+func export(ctx context.Context, client connectorPB.ConnectorPublicServiceClient, opts *options) error {
+
+	manifests, _, err := liveManifests(ctx, client, opts.namespace)
+	if err != nil {
+		return err
+	}
+	b, err := manifest.Marshal(manifests)
+	if err != nil {
+		return err
+	}
+	if opts.file == "" {
+		_, err = os.Stdout.Write(b)
+		return err
+	}
+	return os.WriteFile(opts.file, b, 0600)
+}
+
+func printPlan(changes []*manifest.Change) {
+	if len(changes) == 0 {
+		fmt.Println("No changes, the connectors match the manifests.")
+		return
+	}
+	for _, change := range changes {
+		switch change.Action {
+		case manifest.ActionCreate:
+			fmt.Printf("+ create %s (%s)\n", change.ID, change.Desired.ConnectorDefinitionName)
+		case manifest.ActionUpdate:
+			fmt.Printf("~ update %s (%s)\n", change.ID, strings.Join(change.Fields, ", "))
+		case manifest.ActionDelete:
+			fmt.Printf("- delete %s\n", change.ID)
+		}
+		if len(change.Unverified) > 0 {
+			fmt.Printf("? unverified %s (%s), the live credentials are hidden: use secret references to have them compared\n", change.ID, strings.Join(change.Unverified, ", "))
+		}
+	}
+}
+
+// applyChange makes the change on the server, a connected connector is disconnected to be updated and
+// connected again afterwards
+func applyChange(ctx context.Context, client connectorPB.ConnectorPublicServiceClient, namespace string, change *manifest.Change, state connectorPB.ConnectorResource_State) error {
+
+	name := fmt.Sprintf("%s/connector-resources/%s", namespace, change.ID)
+
+	switch change.Action {
+	case manifest.ActionCreate:
+		if change.Desired.HasCredentialPlaceholders() {
+			return fmt.Errorf("credential placeholders %s are left in the manifest", manifest.CredentialPlaceholder)
+		}
+		connectorResource, err := change.Desired.ToConnectorResource()
+		if err != nil {
+			return err
+		}
+		_, err = client.CreateUserConnectorResource(ctx, &connectorPB.CreateUserConnectorResourceRequest{
+			Parent:            namespace,
+			ConnectorResource: connectorResource,
+		})
+		return err
+
+	case manifest.ActionUpdate:
+		connectorResource, err := change.Desired.ToConnectorResource()
+		if err != nil {
+			return err
+		}
+		connectorResource.Name = name
+		connected := state == connectorPB.ConnectorResource_STATE_CONNECTED
+		if connected {
+			if _, err := client.DisconnectUserConnectorResource(ctx, &connectorPB.DisconnectUserConnectorResourceRequest{Name: name}); err != nil {
+				return err
+			}
+		}
+		if _, err := client.UpdateUserConnectorResource(ctx, &connectorPB.UpdateUserConnectorResourceRequest{
+			ConnectorResource: connectorResource,
+			UpdateMask:        &fieldmaskpb.FieldMask{Paths: change.Fields},
+		}); err != nil {
+			return err
+		}
+		if connected {
+			_, err = client.ConnectUserConnectorResource(ctx, &connectorPB.ConnectUserConnectorResourceRequest{Name: name})
+		}
+		return err
+
+	case manifest.ActionDelete:
+		_, err := client.DeleteUserConnectorResource(ctx, &connectorPB.DeleteUserConnectorResourceRequest{Name: name})
+		return err
+	}
+	return nil
+}
+
+func apply(ctx context.Context, client connectorPB.ConnectorPublicServiceClient, opts *options) error {
+
+	if opts.file == "" {
+		return fmt.Errorf("-f is required")
+	}
+	desired, err := readManifests(opts.file)
+	if err != nil {
+		return err
+	}
+	live, states, err := liveManifests(ctx, client, opts.namespace)
+	if err != nil {
+		return err
+	}
+	changes, err := manifest.Plan(desired, live)
+	if err != nil {
+		return err
+	}
+
+	printPlan(changes)
+	if opts.dryRun {
+		return nil
+	}
+
+	failed, applied := 0, 0
+	for _, change := range changes {
+		if change.Action == manifest.ActionUnverified {
+			continue
+		}
+		applied++
+		if err := applyChange(ctx, client, opts.namespace, change, states[change.ID]); err != nil {
+			fmt.Fprintf(os.Stderr, "%s %s failed: %s\n", change.Action, change.ID, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d changes failed", failed, applied)
+	}
+	fmt.Printf("%d changes applied.\n", applied)
+	return nil
+}
+
+func main() {
+
+	if len(os.Args) < 2 || (os.Args[1] != "export" && os.Args[1] != "apply") {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	command := os.Args[1]
+	opts := parseOptions(command, os.Args[2:])
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.timeout)
+	defer cancel()
+	ctx = outgoingContext(ctx, opts)
+
+	clientConn, err := grpc.Dial(opts.address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	defer clientConn.Close()
+	client := connectorPB.NewConnectorPublicServiceClient(clientConn)
+
+	switch command {
+	case "export":
+		err = export(ctx, client, opts)
+	case "apply":
+		err = apply(ctx, client, opts)
+	}
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+}
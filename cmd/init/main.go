@@ -10,11 +10,13 @@ import (
 	"github.com/gofrs/uuid"
 	"go.einride.tech/aip/filtering"
 	"go.opentelemetry.io/otel"
+	"google.golang.org/protobuf/types/known/structpb"
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
 
 	"github.com/instill-ai/connector-backend/config"
 	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/encryption"
 	"github.com/instill-ai/connector-backend/pkg/logger"
 	"github.com/instill-ai/connector-backend/pkg/repository"
 	"github.com/instill-ai/connector-backend/pkg/utils"
@@ -108,4 +110,45 @@ func main() {
 		}
 	}
 
+	// Encrypt the credential fields of connectors stored before envelope encryption was enabled
+	keyProvider, err := encryption.NewKeyProvider(config.Config.Encryption)
+	if err != nil {
+		panic(err)
+	}
+	envelope := encryption.NewEnvelope(keyProvider)
+
+	pageToken := ""
+	for {
+		conns, _, nextPageToken, err := repository.ListConnectorResourcesAdmin(ctx, 100, pageToken, false, filtering.Filter{}, true)
+		if err != nil {
+			panic(err)
+		}
+		for idx := range conns {
+			def, err := connectors.GetConnectorDefinitionByUID(conns[idx].ConnectorDefinitionUID)
+			if err != nil {
+				continue
+			}
+			configuration := &structpb.Struct{}
+			if err := configuration.UnmarshalJSON(conns[idx].Configuration); err != nil {
+				logger.Warn(err.Error())
+				continue
+			}
+			if err := utils.EncryptCredentialFields(connectors, def.GetId(), configuration, envelope); err != nil {
+				panic(err)
+			}
+			b, err := configuration.MarshalJSON()
+			if err != nil {
+				panic(err)
+			}
+			// A failed write would leave the credentials in cleartext
+			if result := db.Unscoped().Model(&datamodel.ConnectorResource{}).Where("uid = ?", conns[idx].UID).UpdateColumn("configuration", datatypes.JSON(b)); result.Error != nil {
+				panic(result.Error)
+			}
+		}
+		if nextPageToken == "" {
+			break
+		}
+		pageToken = nextPageToken
+	}
+
 }
@@ -31,6 +31,7 @@ import (
 
 	"github.com/instill-ai/connector-backend/config"
 	"github.com/instill-ai/connector-backend/pkg/constant"
+	"github.com/instill-ai/connector-backend/pkg/encryption"
 	"github.com/instill-ai/connector-backend/pkg/external"
 	"github.com/instill-ai/connector-backend/pkg/handler"
 	"github.com/instill-ai/connector-backend/pkg/logger"
@@ -79,6 +80,14 @@ func main() {
 		}()
 	}
 
+	if mp, err := custom_otel.SetupMetrics(ctx, "connector-backend"); err != nil {
+		panic(err)
+	} else {
+		defer func() {
+			err = mp.Shutdown(ctx)
+		}()
+	}
+
 	ctx, span := otel.Tracer("main-tracer").Start(ctx,
 		"main",
 	)
@@ -170,6 +179,11 @@ func main() {
 
 	repository := repository.NewRepository(db)
 
+	keyProvider, err := encryption.NewKeyProvider(config.Config.Encryption)
+	if err != nil {
+		logger.Fatal(err.Error())
+	}
+
 	grpcServerOpts = append(grpcServerOpts, grpc.MaxRecvMsgSize(constant.MaxPayloadSize))
 	grpcServerOpts = append(grpcServerOpts, grpc.MaxSendMsgSize(constant.MaxPayloadSize))
 
@@ -187,16 +201,25 @@ func main() {
 		controllerClient,
 		redisClient,
 		influxDBWriteClient,
+		encryption.NewEnvelope(keyProvider),
 	)
 	connectorPB.RegisterConnectorPrivateServiceServer(
 		privateGrpcS,
 		handler.NewPrivateHandler(ctx, service),
 	)
+	privateGrpcS.RegisterService(
+		&handler.ConnectorPermissionPrivateServiceDesc,
+		handler.NewPermissionHandler(ctx, service),
+	)
 
 	connectorPB.RegisterConnectorPublicServiceServer(
 		publicGrpcS,
 		handler.NewPublicHandler(ctx, service),
 	)
+	publicGrpcS.RegisterService(
+		&handler.ConnectorStreamServiceDesc,
+		handler.NewStreamHandler(ctx, service),
+	)
 
 	privateServeMux := runtime.NewServeMux(
 		runtime.WithForwardResponseOption(middleware.HttpResponseModifier),
@@ -268,6 +291,58 @@ func main() {
 		logger.Fatal(err.Error())
 	}
 
+	if count, err := service.FailInterruptedConnectorOperations(ctx); err != nil {
+		logger.Error(err.Error())
+	} else if count > 0 {
+		logger.Info(fmt.Sprintf("%d asynchronous executions interrupted by the restart have failed", count))
+	}
+
+	// Purge the execution history past its retention, the expired execution captures and dead letters, and the
+	// connectors deleted past their retention
+	go func() {
+		interval := config.Config.Execution.HistoryPurgeInterval
+		if interval <= 0 {
+			interval = time.Hour
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if count, err := service.PurgeConnectorExecutions(ctx); err != nil {
+				logger.Error(err.Error())
+			} else if count > 0 {
+				logger.Info(fmt.Sprintf("%d executions past the history retention have been purged", count))
+			}
+			if count, err := service.PurgeConnectorCaptures(ctx); err != nil {
+				logger.Error(err.Error())
+			} else if count > 0 {
+				logger.Info(fmt.Sprintf("%d expired execution captures have been purged", count))
+			}
+			if count, err := service.PurgeConnectorDeadLetters(ctx); err != nil {
+				logger.Error(err.Error())
+			} else if count > 0 {
+				logger.Info(fmt.Sprintf("%d expired failed execution inputs have been purged", count))
+			}
+			if count, err := service.PurgeDeletedConnectorResources(ctx); err != nil {
+				logger.Error(err.Error())
+			} else if count > 0 {
+				logger.Info(fmt.Sprintf("%d connectors deleted past the retention have been purged", count))
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	httpHandler := handler.NewHTTPHandler(ctx, service)
+	if err := httpHandler.RegisterPrivateRoutes(privateServeMux); err != nil {
+		logger.Fatal(err.Error())
+	}
+	if err := httpHandler.RegisterPublicRoutes(publicServeMux); err != nil {
+		logger.Fatal(err.Error())
+	}
+
 	privateHTTPServer := &http.Server{
 		Addr:    fmt.Sprintf(":%v", config.Config.Server.PrivatePort),
 		Handler: grpcHandlerFunc(privateGrpcS, privateServeMux),
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+
+	"github.com/instill-ai/connector-backend/config"
+	"github.com/instill-ai/connector-backend/pkg/encryption"
+	"github.com/instill-ai/connector-backend/pkg/external"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/connector-backend/pkg/repository"
+	"github.com/instill-ai/connector-backend/pkg/service"
+	"github.com/instill-ai/connector-backend/pkg/utils"
+
+	database "github.com/instill-ai/connector-backend/pkg/db"
+	connector "github.com/instill-ai/connector/pkg"
+)
+
+var (
+	definitionID = flag.String("definition", "", "only rotate connectors of this connector definition id")
+	namespace    = flag.String("namespace", "", "only rotate connectors owned by this namespace permalink, e.g. users/<uid>")
+	fingerprint  = flag.String("fingerprint", "", "only rotate credential values whose SHA-256 fingerprint starts with this prefix")
+	field        = flag.String("field", "", "only rotate this credential field, e.g. api_key")
+	newValueFile = flag.String("new-value-file", "", "file containing the new credential value, $ROTATE_CREDENTIALS_NEW_VALUE is used otherwise")
+	stateFile    = flag.String("state-file", "rotate-credentials.state.json", "file recording the progress so that an interrupted run can be resumed")
+	dryRun       = flag.Bool("dry-run", false, "report the connectors that would be rotated without updating them")
+)
+
+// revisionCreator is the creator of the revisions recording the rotations
+const revisionCreator = "rotate-credentials"
+
+// progress is persisted in the state file after every connector
+type progress struct {
+	PageToken string          `json:"page_token"`
+	Done      map[string]bool `json:"done"`
+	Rotated   []string        `json:"rotated"`
+	Skipped   []string        `json:"skipped"`
+	Failed    []string        `json:"failed"`
+}
+
+func loadProgress(path string) (*progress, error) {
+	p := &progress{Done: map[string]bool{}}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, p); err != nil {
+		return nil, err
+	}
+	if p.Done == nil {
+		p.Done = map[string]bool{}
+	}
+	return p, nil
+}
+
+func (p *progress) save(path string) error {
+	b, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0600)
+}
+
+func main() {
+
+	if err := config.Init(); err != nil {
+		log.Fatal(err.Error())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx, span := otel.Tracer("rotate-credentials-tracer").Start(ctx,
+		"main",
+	)
+	defer span.End()
+	defer cancel()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	if *definitionID == "" && *namespace == "" && *fingerprint == "" {
+		logger.Fatal("at least one of --definition, --namespace or --fingerprint is required")
+	}
+	// Without them every credential field of the matched connectors would get the same value
+	if *field == "" && *fingerprint == "" {
+		logger.Fatal("--field or --fingerprint is required to select the credential to rotate")
+	}
+
+	var newValue string
+	if *newValueFile != "" {
+		b, err := os.ReadFile(*newValueFile)
+		if err != nil {
+			logger.Fatal(err.Error())
+		}
+		newValue = strings.TrimSpace(string(b))
+	} else {
+		newValue = os.Getenv("ROTATE_CREDENTIALS_NEW_VALUE")
+	}
+	if newValue == "" && !*dryRun {
+		logger.Fatal("the new credential value is empty")
+	}
+
+	db := database.GetConnection()
+	defer database.Close(db)
+
+	keyProvider, err := encryption.NewKeyProvider(config.Config.Encryption)
+	if err != nil {
+		logger.Fatal(err.Error())
+	}
+	envelope := encryption.NewEnvelope(keyProvider)
+
+	mgmtPrivateServiceClient, mgmtPrivateServiceClientConn := external.InitMgmtPrivateServiceClient(ctx)
+	if mgmtPrivateServiceClientConn != nil {
+		defer mgmtPrivateServiceClientConn.Close()
+	}
+
+	pipelinePublicServiceClient, pipelinePublicServiceClientConn := external.InitPipelinePublicServiceClient(ctx)
+	if pipelinePublicServiceClientConn != nil {
+		defer pipelinePublicServiceClientConn.Close()
+	}
+
+	controllerClient, controllerClientConn := external.InitControllerPrivateServiceClient(ctx)
+	if controllerClientConn != nil {
+		defer controllerClientConn.Close()
+	}
+
+	redisClient := redis.NewClient(&config.Config.Cache.Redis.RedisOptions)
+	defer redisClient.Close()
+
+	influxDBClient, influxDBWriteClient := external.InitInfluxDBServiceClient(ctx)
+	defer influxDBClient.Close()
+
+	repository := repository.NewRepository(db)
+	connectors := connector.Init(logger, utils.GetConnectorOptions())
+
+	service := service.NewService(
+		ctx,
+		repository,
+		mgmtPrivateServiceClient,
+		pipelinePublicServiceClient,
+		controllerClient,
+		redisClient,
+		influxDBWriteClient,
+		envelope,
+	)
+
+	state, err := loadProgress(*stateFile)
+	if err != nil {
+		logger.Fatal(err.Error())
+	}
+
+	r := &rotator{
+		definitionID: *definitionID,
+		namespace:    *namespace,
+		fingerprint:  *fingerprint,
+		field:        *field,
+		newValue:     newValue,
+		stateFile:    *stateFile,
+		dryRun:       *dryRun,
+		repository:   repository,
+		service:      service,
+		connectors:   connectors,
+		envelope:     envelope,
+		logger:       logger,
+		state:        state,
+	}
+	if err := r.run(ctx); err != nil {
+		logger.Fatal(err.Error())
+	}
+
+	for _, summary := range []struct {
+		title string
+		items []string
+	}{
+		{"Rotated", state.Rotated},
+		{"Skipped", state.Skipped},
+		{"Failed", state.Failed},
+	} {
+		fmt.Printf("%s: %d\n", summary.title, len(summary.items))
+		for _, item := range summary.items {
+			fmt.Printf("  %s\n", item)
+		}
+	}
+
+	if !*dryRun {
+		// A completed run starts from scratch next time
+		if err := os.Remove(*stateFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+			logger.Warn(err.Error())
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.einride.tech/aip/filtering"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/encryption"
+	"github.com/instill-ai/connector-backend/pkg/repository"
+	"github.com/instill-ai/connector-backend/pkg/service"
+	"github.com/instill-ai/connector-backend/pkg/utils"
+
+	componentBase "github.com/instill-ai/component/pkg/base"
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+// rotator rotates the credentials of the connectors matching its filters, resuming from its state
+type rotator struct {
+	definitionID string
+	namespace    string
+	fingerprint  string
+	field        string
+	newValue     string
+	stateFile    string
+	dryRun       bool
+
+	repository repository.Repository
+	service    service.Service
+	connectors componentBase.IConnector
+	envelope   *encryption.Envelope
+	logger     *zap.Logger
+	state      *progress
+}
+
+func (r *rotator) run(ctx context.Context) error {
+	pageToken := r.state.PageToken
+	for {
+		conns, _, nextPageToken, err := r.repository.ListConnectorResourcesAdmin(ctx, 100, pageToken, false, filtering.Filter{}, false)
+		if err != nil {
+			return err
+		}
+
+		for _, conn := range conns {
+			if r.state.Done[conn.UID.String()] {
+				continue
+			}
+			if err := r.rotate(ctx, conn); err != nil {
+				return err
+			}
+		}
+
+		if nextPageToken == "" {
+			return nil
+		}
+		pageToken = nextPageToken
+		if !r.dryRun {
+			r.state.PageToken = pageToken
+			if err := r.state.save(r.stateFile); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// rotate rotates the matching credentials of a connector, only a failure to save the state is returned
+func (r *rotator) rotate(ctx context.Context, conn *datamodel.ConnectorResource) error {
+	if r.namespace != "" && conn.Owner != r.namespace {
+		return nil
+	}
+	def, err := r.connectors.GetConnectorDefinitionByUID(conn.ConnectorDefinitionUID)
+	if err != nil {
+		return nil
+	}
+	if r.definitionID != "" && def.GetId() != r.definitionID {
+		return nil
+	}
+
+	configuration := &structpb.Struct{}
+	if err := configuration.UnmarshalJSON(conn.Configuration); err != nil {
+		return r.record(&r.state.Failed, conn, err.Error())
+	}
+
+	credentialFields, _ := r.connectors.ListCredentialField(def.GetId())
+	matched, err := r.rotateFields(configuration, credentialFields)
+	if err != nil {
+		return r.record(&r.state.Failed, conn, err.Error())
+	}
+	if len(matched) == 0 {
+		return r.record(&r.state.Skipped, conn, "no matching credential field")
+	}
+
+	if r.dryRun {
+		return r.record(&r.state.Rotated, conn, fmt.Sprintf("would rotate %s", strings.Join(matched, ", ")))
+	}
+
+	b, err := configuration.MarshalJSON()
+	if err != nil {
+		return r.record(&r.state.Failed, conn, err.Error())
+	}
+	// The rotation is recorded in the revisions of the connector so that it can be rolled back
+	if err := r.service.UpdateConnectorResourceConfigurationAdmin(ctx, conn.UID, b, revisionCreator); err != nil {
+		return r.record(&r.state.Failed, conn, err.Error())
+	}
+
+	// A check sets the controller state to its result, a connector which is not connected gets its
+	// state back
+	connState, err := r.service.CheckConnectorResourceByUID(ctx, conn.UID)
+	if conn.State != datamodel.ConnectorResourceState(connectorPB.ConnectorResource_STATE_CONNECTED) {
+		if err := r.service.UpdateResourceState(conn.UID, connectorPB.ConnectorResource_State(conn.State), nil); err != nil {
+			r.logger.Warn(fmt.Sprintf("state of connector %s not restored: %s", conn.UID, err))
+		}
+	}
+	if err != nil || *connState != connectorPB.ConnectorResource_STATE_CONNECTED {
+		return r.record(&r.state.Failed, conn, fmt.Sprintf("rotated %s, but the connection check failed", strings.Join(matched, ", ")))
+	}
+	return r.record(&r.state.Rotated, conn, fmt.Sprintf("rotated %s", strings.Join(matched, ", ")))
+}
+
+// rotateFields sets the new value, encrypted, in the credential fields matching the field and
+// fingerprint filters and returns them
+func (r *rotator) rotateFields(configuration *structpb.Struct, credentialFields []string) ([]string, error) {
+	matched := []string{}
+	for _, credentialField := range credentialFields {
+		if r.field != "" && credentialField != r.field {
+			continue
+		}
+		parent, key, ok := utils.LookUpParentField(configuration, credentialField)
+		if !ok {
+			continue
+		}
+		value, ok := parent.GetFields()[key].GetKind().(*structpb.Value_StringValue)
+		if !ok {
+			continue
+		}
+		oldValue, err := r.envelope.Decrypt(value.StringValue)
+		if err != nil {
+			return nil, err
+		}
+		// Secret references are rotated through the secret store
+		if _, ok := utils.GetSecretReference(oldValue); ok {
+			continue
+		}
+		if oldValue == "" || oldValue == r.newValue || !strings.HasPrefix(encryption.Fingerprint(oldValue), r.fingerprint) {
+			continue
+		}
+		encrypted, err := r.envelope.Encrypt(r.newValue)
+		if err != nil {
+			return nil, err
+		}
+		parent.GetFields()[key] = structpb.NewStringValue(encrypted)
+		matched = append(matched, credentialField)
+	}
+	return matched, nil
+}
+
+// record adds the connector to the list and, unless in dry-run, marks it done in the state file
+func (r *rotator) record(list *[]string, conn *datamodel.ConnectorResource, reason string) error {
+	*list = append(*list, fmt.Sprintf("%s/connector-resources/%s (%s): %s", conn.Owner, conn.ID, conn.UID, reason))
+	if r.dryRun {
+		return nil
+	}
+	r.state.Done[conn.UID.String()] = true
+	return r.state.save(r.stateFile)
+}
@@ -0,0 +1,410 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"go.einride.tech/aip/filtering"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/encryption"
+	"github.com/instill-ai/connector-backend/pkg/repository"
+	"github.com/instill-ai/connector-backend/pkg/service"
+	"github.com/instill-ai/connector-backend/pkg/utils"
+
+	componentBase "github.com/instill-ai/component/pkg/base"
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+var (
+	openAIDefUID    = uuid.Must(uuid.NewV4())
+	stabilityDefUID = uuid.Must(uuid.NewV4())
+)
+
+// fakeConnectors knows two definitions, both with an api_key credential field
+type fakeConnectors struct {
+	componentBase.IConnector
+}
+
+func (c *fakeConnectors) GetConnectorDefinitionByUID(defUID uuid.UUID) (*connectorPB.ConnectorDefinition, error) {
+	switch defUID {
+	case openAIDefUID:
+		return &connectorPB.ConnectorDefinition{Uid: defUID.String(), Id: "ai-openai"}, nil
+	case stabilityDefUID:
+		return &connectorPB.ConnectorDefinition{Uid: defUID.String(), Id: "ai-stability-ai"}, nil
+	}
+	return nil, errors.New("definition not found")
+}
+
+func (c *fakeConnectors) ListCredentialField(defID string) ([]string, error) {
+	return []string{"api_key"}, nil
+}
+
+// pagedRepository lists its pages by page token, the page tokens being the page indexes, and fails
+// on the page given by failAt
+type pagedRepository struct {
+	repository.Repository
+	pages  [][]*datamodel.ConnectorResource
+	failAt string
+}
+
+func (r *pagedRepository) ListConnectorResourcesAdmin(ctx context.Context, pageSize int64, pageToken string, isBasicView bool, filter filtering.Filter, showDeleted bool) ([]*datamodel.ConnectorResource, int64, string, error) {
+	if pageToken != "" && pageToken == r.failAt {
+		return nil, 0, "", errors.New("connection refused")
+	}
+	index := 0
+	if pageToken != "" {
+		index = int(pageToken[0] - '0')
+	}
+	nextPageToken := ""
+	if index+1 < len(r.pages) {
+		nextPageToken = string(rune('0' + index + 1))
+	}
+	return r.pages[index], 0, nextPageToken, nil
+}
+
+// updatingService keeps the updated configurations, every check succeeds
+type updatingService struct {
+	service.Service
+	updated map[uuid.UUID][]byte
+}
+
+func (s *updatingService) UpdateConnectorResourceConfigurationAdmin(ctx context.Context, uid uuid.UUID, configuration []byte, creator string) error {
+	s.updated[uid] = configuration
+	return nil
+}
+
+func (s *updatingService) CheckConnectorResourceByUID(ctx context.Context, connUID uuid.UUID) (*connectorPB.ConnectorResource_State, error) {
+	state := connectorPB.ConnectorResource_STATE_CONNECTED
+	return &state, nil
+}
+
+func (s *updatingService) UpdateResourceState(uid uuid.UUID, state connectorPB.ConnectorResource_State, progress *int32) error {
+	return nil
+}
+
+func newTestEnvelope(t *testing.T) *encryption.Envelope {
+	t.Helper()
+	provider, err := encryption.NewLocalKeyProvider(filepath.Join(t.TempDir(), "kek"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return encryption.NewEnvelope(provider)
+}
+
+func newTestConnector(id string, owner string, defUID uuid.UUID, apiKey string) *datamodel.ConnectorResource {
+	conn := &datamodel.ConnectorResource{
+		ID:                     id,
+		Owner:                  owner,
+		ConnectorDefinitionUID: defUID,
+		Configuration:          []byte(`{"api_key": "` + apiKey + `"}`),
+		State:                  datamodel.ConnectorResourceState(connectorPB.ConnectorResource_STATE_CONNECTED),
+	}
+	conn.UID = uuid.Must(uuid.NewV4())
+	return conn
+}
+
+func TestRotateFields(t *testing.T) {
+	envelope := newTestEnvelope(t)
+	encrypt := func(plaintext string) string {
+		encrypted, err := envelope.Encrypt(plaintext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return encrypted
+	}
+
+	testCases := []struct {
+		name             string
+		field            string
+		fingerprint      string
+		credentialFields []string
+		configuration    map[string]interface{}
+		want             []string
+	}{
+		{
+			name:             "field",
+			field:            "api_key",
+			credentialFields: []string{"api_key", "organization"},
+			configuration:    map[string]interface{}{"api_key": encrypt("sk-old"), "organization": encrypt("org-old")},
+			want:             []string{"api_key"},
+		},
+		{
+			name:             "fingerprint prefix",
+			fingerprint:      encryption.Fingerprint("sk-old")[:8],
+			credentialFields: []string{"api_key", "organization"},
+			configuration:    map[string]interface{}{"api_key": encrypt("sk-old"), "organization": encrypt("org-old")},
+			want:             []string{"api_key"},
+		},
+		{
+			name:             "other fingerprint",
+			fingerprint:      encryption.Fingerprint("sk-other")[:8],
+			credentialFields: []string{"api_key"},
+			configuration:    map[string]interface{}{"api_key": encrypt("sk-old")},
+			want:             []string{},
+		},
+		{
+			name:             "nested field",
+			field:            "auth.token",
+			credentialFields: []string{"auth.token"},
+			configuration:    map[string]interface{}{"auth": map[string]interface{}{"token": encrypt("sk-old")}},
+			want:             []string{"auth.token"},
+		},
+		{
+			name:             "plaintext value",
+			field:            "api_key",
+			credentialFields: []string{"api_key"},
+			configuration:    map[string]interface{}{"api_key": "sk-old"},
+			want:             []string{"api_key"},
+		},
+		{
+			name:             "secret reference",
+			field:            "api_key",
+			credentialFields: []string{"api_key"},
+			configuration:    map[string]interface{}{"api_key": "${secrets/openai-key}"},
+			want:             []string{},
+		},
+		{
+			name:             "already rotated",
+			field:            "api_key",
+			credentialFields: []string{"api_key"},
+			configuration:    map[string]interface{}{"api_key": encrypt("sk-new")},
+			want:             []string{},
+		},
+		{
+			name:             "missing field",
+			field:            "api_key",
+			credentialFields: []string{"api_key"},
+			configuration:    map[string]interface{}{"model": "gpt-4"},
+			want:             []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			configuration, err := structpb.NewStruct(tc.configuration)
+			if err != nil {
+				t.Fatal(err)
+			}
+			original := proto.Clone(configuration).(*structpb.Struct)
+			r := &rotator{field: tc.field, fingerprint: tc.fingerprint, newValue: "sk-new", envelope: envelope}
+
+			got, err := r.rotateFields(configuration, tc.credentialFields)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got %v, want %v", got, tc.want)
+			}
+
+			rotated := map[string]bool{}
+			for _, field := range got {
+				rotated[field] = true
+			}
+			for _, credentialField := range tc.credentialFields {
+				previous, _ := utils.LookUpField(original, credentialField)
+				value, _ := utils.LookUpField(configuration, credentialField)
+				if !rotated[credentialField] {
+					if !proto.Equal(value, previous) {
+						t.Errorf("got %s changed to %v", credentialField, value)
+					}
+					continue
+				}
+				plaintext, err := envelope.Decrypt(value.GetStringValue())
+				if err != nil || plaintext != "sk-new" || !encryption.IsEncrypted(value.GetStringValue()) {
+					t.Errorf("got %s = %v, want the new value encrypted", credentialField, value)
+				}
+			}
+		})
+	}
+}
+
+func TestRunFilters(t *testing.T) {
+	conns := []*datamodel.ConnectorResource{
+		newTestConnector("openai-a", "users/a", openAIDefUID, "sk-old"),
+		newTestConnector("openai-b", "users/b", openAIDefUID, "sk-old"),
+		newTestConnector("stability-a", "users/a", stabilityDefUID, "sk-old"),
+		newTestConnector("unknown-a", "users/a", uuid.Must(uuid.NewV4()), "sk-old"),
+	}
+
+	testCases := []struct {
+		name         string
+		definitionID string
+		namespace    string
+		want         []string
+	}{
+		{name: "definition", definitionID: "ai-openai", want: []string{"openai-a", "openai-b"}},
+		{name: "namespace", namespace: "users/a", want: []string{"openai-a", "stability-a"}},
+		{name: "definition and namespace", definitionID: "ai-openai", namespace: "users/b", want: []string{"openai-b"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &updatingService{updated: map[uuid.UUID][]byte{}}
+			r := &rotator{
+				definitionID: tc.definitionID,
+				namespace:    tc.namespace,
+				field:        "api_key",
+				newValue:     "sk-new",
+				stateFile:    filepath.Join(t.TempDir(), "state.json"),
+				repository:   &pagedRepository{pages: [][]*datamodel.ConnectorResource{conns}},
+				service:      svc,
+				connectors:   &fakeConnectors{},
+				envelope:     newTestEnvelope(t),
+				logger:       zap.NewNop(),
+				state:        &progress{Done: map[string]bool{}},
+			}
+			if err := r.run(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			got := []string{}
+			for _, conn := range conns {
+				if _, ok := svc.updated[conn.UID]; ok {
+					got = append(got, conn.ID)
+				}
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v rotated, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRunResumes(t *testing.T) {
+	pages := [][]*datamodel.ConnectorResource{
+		{newTestConnector("first", "users/a", openAIDefUID, "sk-old")},
+		{newTestConnector("second", "users/a", openAIDefUID, "sk-old")},
+		{newTestConnector("third", "users/a", openAIDefUID, "sk-old")},
+	}
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	envelope := newTestEnvelope(t)
+
+	newRotator := func(repository repository.Repository, svc service.Service) *rotator {
+		state, err := loadProgress(stateFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &rotator{
+			field:      "api_key",
+			newValue:   "sk-new",
+			stateFile:  stateFile,
+			repository: repository,
+			service:    svc,
+			connectors: &fakeConnectors{},
+			envelope:   envelope,
+			logger:     zap.NewNop(),
+			state:      state,
+		}
+	}
+
+	// The run is interrupted when listing the third page
+	interrupted := &updatingService{updated: map[uuid.UUID][]byte{}}
+	if err := newRotator(&pagedRepository{pages: pages, failAt: "2"}, interrupted).run(context.Background()); err == nil {
+		t.Fatal("got no error from the interrupted run")
+	}
+	if len(interrupted.updated) != 2 {
+		t.Fatalf("got %d connectors rotated before the interruption, want 2", len(interrupted.updated))
+	}
+
+	state, err := loadProgress(stateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.PageToken != "2" || !state.Done[pages[0][0].UID.String()] || !state.Done[pages[1][0].UID.String()] || len(state.Rotated) != 2 {
+		t.Fatalf("got state %+v, want the first two pages done", state)
+	}
+
+	// The resumed run starts from the saved page
+	resumed := &updatingService{updated: map[uuid.UUID][]byte{}}
+	r := newRotator(&pagedRepository{pages: pages}, resumed)
+	if err := r.run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := resumed.updated[pages[2][0].UID]; !ok || len(resumed.updated) != 1 {
+		t.Errorf("got %d connectors rotated by the resumed run, want the third one only", len(resumed.updated))
+	}
+	if len(r.state.Rotated) != 3 {
+		t.Errorf("got %d rotated in the summary, want the 3 of both runs", len(r.state.Rotated))
+	}
+
+	// The connectors done are skipped when their page is listed again, e.g. after an interruption
+	// before the next page token is saved
+	relisted := &updatingService{updated: map[uuid.UUID][]byte{}}
+	r = newRotator(&pagedRepository{pages: pages}, relisted)
+	r.state.PageToken = ""
+	if err := r.run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(relisted.updated) != 0 {
+		t.Errorf("got %d connectors rotated again, want none", len(relisted.updated))
+	}
+}
+
+func TestRunDryRun(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "state.json")
+	svc := &updatingService{updated: map[uuid.UUID][]byte{}}
+	r := &rotator{
+		field:      "api_key",
+		newValue:   "sk-new",
+		stateFile:  stateFile,
+		dryRun:     true,
+		repository: &pagedRepository{pages: [][]*datamodel.ConnectorResource{{newTestConnector("first", "users/a", openAIDefUID, "sk-old")}, {}}},
+		service:    svc,
+		connectors: &fakeConnectors{},
+		envelope:   newTestEnvelope(t),
+		logger:     zap.NewNop(),
+		state:      &progress{Done: map[string]bool{}},
+	}
+	if err := r.run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(svc.updated) != 0 || len(r.state.Rotated) != 1 {
+		t.Errorf("got %d updated and %d reported, want 0 and 1", len(svc.updated), len(r.state.Rotated))
+	}
+	if _, err := os.Stat(stateFile); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got the state file written in dry-run: %v", err)
+	}
+}
+
+func TestLoadProgress(t *testing.T) {
+	dir := t.TempDir()
+
+	state, err := loadProgress(filepath.Join(dir, "missing.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.PageToken != "" || state.Done == nil || len(state.Done) != 0 {
+		t.Errorf("got %+v, want an empty progress", state)
+	}
+
+	nullDone := filepath.Join(dir, "null.json")
+	if err := os.WriteFile(nullDone, []byte(`{"page_token": "2", "done": null}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	state, err = loadProgress(nullDone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.PageToken != "2" || state.Done == nil {
+		t.Errorf("got %+v, want the page token and a done map", state)
+	}
+
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(invalid, []byte(`{`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadProgress(invalid); err == nil {
+		t.Error("an invalid state file is loaded")
+	}
+}
@@ -19,15 +19,21 @@ var Config AppConfig
 
 // AppConfig defines
 type AppConfig struct {
-	Server          ServerConfig          `koanf:"server"`
-	Connector       ConnectorConfig       `koanf:"connector"`
-	Database        DatabaseConfig        `koanf:"database"`
-	PipelineBackend PipelineBackendConfig `koanf:"pipelinebackend"`
-	MgmtBackend     MgmtBackendConfig     `koanf:"mgmtbackend"`
-	Controller      ControllerConfig      `koanf:"controller"`
-	Log             LogConfig             `koanf:"log"`
-	InfluxDB        InfluxDBConfig        `koanf:"influxdb"`
-	Cache           CacheConfig           `koanf:"cache"`
+	Server           ServerConfig           `koanf:"server"`
+	Connector        ConnectorConfig        `koanf:"connector"`
+	Database         DatabaseConfig         `koanf:"database"`
+	PipelineBackend  PipelineBackendConfig  `koanf:"pipelinebackend"`
+	MgmtBackend      MgmtBackendConfig      `koanf:"mgmtbackend"`
+	Controller       ControllerConfig       `koanf:"controller"`
+	Log              LogConfig              `koanf:"log"`
+	InfluxDB         InfluxDBConfig         `koanf:"influxdb"`
+	Cache            CacheConfig            `koanf:"cache"`
+	Encryption       EncryptionConfig       `koanf:"encryption"`
+	Execution        ExecutionConfig        `koanf:"execution"`
+	Idempotency      IdempotencyConfig      `koanf:"idempotency"`
+	DebugCapture     DebugCaptureConfig     `koanf:"debugcapture"`
+	DeadLetter       DeadLetterConfig       `koanf:"deadletter"`
+	DeletedConnector DeletedConnectorConfig `koanf:"deletedconnector"`
 }
 
 // ServerConfig defines HTTP server configurations
@@ -64,6 +70,17 @@ type ConnectorConfig struct {
 		}
 		ExcludeLocalConnector bool `koanf:"excludelocalconnector"`
 	}
+	Retry RetryConfig `koanf:"retry"`
+}
+
+// RetryConfig is the default retry policy of the connector executions and connection checks
+type RetryConfig struct {
+	MaxAttempts     int           `koanf:"maxattempts"`
+	InitialBackoff  time.Duration `koanf:"initialbackoff"`
+	MaxBackoff      time.Duration `koanf:"maxbackoff"`
+	Multiplier      float64       `koanf:"multiplier"`
+	Jitter          float64       `koanf:"jitter"`
+	RetryableErrors []string      `koanf:"retryableerrors"`
 }
 
 // DatabaseConfig related to database
@@ -141,6 +158,51 @@ type CacheConfig struct {
 	}
 }
 
+// EncryptionConfig related to connector credential encryption
+type EncryptionConfig struct {
+	Provider string `koanf:"provider"`
+	Local    struct {
+		KeyFile string `koanf:"keyfile"`
+	}
+}
+
+// ExecutionConfig related to the asynchronous and streaming connector executions
+type ExecutionConfig struct {
+	Workers      int `koanf:"workers"`
+	QueueSize    int `koanf:"queuesize"`
+	BatchSize    int `koanf:"batchsize"`
+	MaxBatchSize int `koanf:"maxbatchsize"`
+	// Executions of a connector with a max concurrency queue for a slot shared across the replicas
+	SlotLease         time.Duration `koanf:"slotlease"`
+	QueueTimeout      time.Duration `koanf:"queuetimeout"`
+	QueuePollInterval time.Duration `koanf:"queuepollinterval"`
+	// Executions are recorded in the history until the retention is over, the purge runs at the interval
+	HistoryRetention     time.Duration `koanf:"historyretention"`
+	HistoryPurgeInterval time.Duration `koanf:"historypurgeinterval"`
+}
+
+// IdempotencyConfig related to the idempotent requests
+type IdempotencyConfig struct {
+	Window time.Duration `koanf:"window"`
+}
+
+// DebugCaptureConfig related to the execution payloads captured for debugging
+type DebugCaptureConfig struct {
+	TTL     time.Duration `koanf:"ttl"`
+	MaxSize int           `koanf:"maxsize"`
+}
+
+// DeadLetterConfig related to the inputs of the failed executions kept to be replayed
+type DeadLetterConfig struct {
+	TTL     time.Duration `koanf:"ttl"`
+	MaxSize int           `koanf:"maxsize"`
+}
+
+// DeletedConnectorConfig related to the deleted connectors kept to be restored
+type DeletedConnectorConfig struct {
+	RetentionDays int `koanf:"retentiondays"`
+}
+
 // Init - Assign global config to decoded config struct
 func Init() error {
 
@@ -5,3 +5,9 @@ const MaxPayloadSize = 1024 * 1024 * 32
 // Constants for resource owner
 const DefaultUserID string = "admin"
 const HeaderUserUIDKey = "jwt-sub"
+
+// HeaderIdempotencyKey is the header, or gRPC metadata, under which a client makes its request idempotent
+const HeaderIdempotencyKey = "idempotency-key"
+
+// HeaderValidateOnly is the header, or gRPC metadata, set to true to only validate the inputs of an execution
+const HeaderValidateOnly = "validate-only"
@@ -51,12 +51,209 @@ type ConnectorResource struct {
 	ConnectorType          ConnectorResourceType       `sql:"type:valid_connector_type"`
 	State                  ConnectorResourceState      `sql:"type:valid_state_type"`
 	Visibility             ConnectorResourceVisibility `sql:"type:valid_visibility"`
+	RetryPolicy            datatypes.JSON              `gorm:"type:jsonb"`
+	MaxConcurrency         int32
+	CacheTTL               int32          // seconds, the execution results are not cached if it is 0
+	DebugCapture           bool           // the payloads of the executions are captured for debugging
+	DefaultedFields        datatypes.JSON `gorm:"type:jsonb"` // JSON pointers of the configuration fields filled from the definition defaults
+	Labels                 datatypes.JSON `gorm:"type:jsonb"` // label keys to values, e.g. the team or the environment of the connector
 }
 
 func (ConnectorResource) TableName() string {
 	return "connector"
 }
 
+// RetryErrorClass classifies the errors of a connector execution to decide whether it is retried
+type RetryErrorClass string
+
+// Retry error classes
+const (
+	RetryErrorTimeout     RetryErrorClass = "timeout"
+	RetryErrorNetwork     RetryErrorClass = "network"
+	RetryErrorRateLimited RetryErrorClass = "rate_limited"
+	RetryErrorUnavailable RetryErrorClass = "unavailable"
+)
+
+// IsValid reports whether the class is one of the retry error classes
+func (c RetryErrorClass) IsValid() bool {
+	switch c {
+	case RetryErrorTimeout, RetryErrorNetwork, RetryErrorRateLimited, RetryErrorUnavailable:
+		return true
+	}
+	return false
+}
+
+// RetryPolicy is the retry policy of a connector resource, stored in the retry_policy column. The unset fields
+// fall back to the global policy, the durations are Go duration strings, e.g. "200ms".
+type RetryPolicy struct {
+	MaxAttempts     int               `json:"max_attempts,omitempty"`
+	InitialBackoff  string            `json:"initial_backoff,omitempty"`
+	MaxBackoff      string            `json:"max_backoff,omitempty"`
+	Multiplier      float64           `json:"multiplier,omitempty"`
+	Jitter          *float64          `json:"jitter,omitempty"`
+	RetryableErrors []RetryErrorClass `json:"retryable_errors"`
+}
+
+// Secret is the data model of the secret table, Value holds the encrypted secret
+type Secret struct {
+	BaseDynamic
+	ID          string
+	Owner       string
+	Description sql.NullString
+	Value       string
+}
+
+func (Secret) TableName() string {
+	return "secret"
+}
+
+// ConnectorRevision is the data model of the connector_revision table, revisions are immutable.
+// Configuration is a snapshot with the credential fields masked and Diff holds the []FieldChange
+// from the previous revision.
+type ConnectorRevision struct {
+	UID           uuid.UUID `gorm:"type:uuid;primary_key;<-:create"` // allow read and create
+	ConnectorUID  uuid.UUID
+	Revision      int32
+	Configuration datatypes.JSON `gorm:"type:jsonb"`
+	Diff          datatypes.JSON `gorm:"type:jsonb"`
+	Description   sql.NullString
+	Visibility    ConnectorResourceVisibility `sql:"type:valid_visibility"`
+	Creator       string
+	CreateTime    time.Time `gorm:"autoCreateTime:nano"`
+}
+
+func (ConnectorRevision) TableName() string {
+	return "connector_revision"
+}
+
+// BeforeCreate will set a UUID rather than numeric ID.
+func (r *ConnectorRevision) BeforeCreate(db *gorm.DB) error {
+	uuid, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	db.Statement.SetColumn("UID", uuid)
+	return nil
+}
+
+// FieldChange describes a field that differs between two connector revisions
+type FieldChange struct {
+	Field    string      `json:"field"`
+	Op       string      `json:"op"`
+	OldValue interface{} `json:"old_value,omitempty"`
+	NewValue interface{} `json:"new_value,omitempty"`
+}
+
+// FieldChange operations
+const (
+	FieldChangeAdd     = "add"
+	FieldChangeRemove  = "remove"
+	FieldChangeReplace = "replace"
+)
+
+// ConnectorRole is a role granted on a connector resource, each role includes the permissions of the lower ones
+type ConnectorRole string
+
+// Connector roles from the lowest to the highest
+const (
+	ConnectorRoleViewer   ConnectorRole = "viewer"
+	ConnectorRoleExecutor ConnectorRole = "executor"
+	ConnectorRoleEditor   ConnectorRole = "editor"
+	ConnectorRoleAdmin    ConnectorRole = "admin"
+)
+
+var connectorRoleRank = map[ConnectorRole]int{
+	ConnectorRoleViewer:   1,
+	ConnectorRoleExecutor: 2,
+	ConnectorRoleEditor:   3,
+	ConnectorRoleAdmin:    4,
+}
+
+// IsValid reports whether the role is one of the connector roles
+func (r ConnectorRole) IsValid() bool {
+	_, ok := connectorRoleRank[r]
+	return ok
+}
+
+// Includes reports whether the role grants the permissions of the other role
+func (r ConnectorRole) Includes(other ConnectorRole) bool {
+	return r.IsValid() && connectorRoleRank[r] >= connectorRoleRank[other]
+}
+
+// ConnectorRoleBinding is the data model of the connector_role_binding table, it grants a role on a
+// connector resource to a subject permalink
+type ConnectorRoleBinding struct {
+	UID          uuid.UUID `gorm:"type:uuid;primary_key;<-:create"` // allow read and create
+	ConnectorUID uuid.UUID
+	Subject      string
+	Role         ConnectorRole `sql:"type:valid_connector_role"`
+	CreateTime   time.Time     `gorm:"autoCreateTime:nano"`
+	UpdateTime   time.Time     `gorm:"autoUpdateTime:nano"`
+}
+
+func (ConnectorRoleBinding) TableName() string {
+	return "connector_role_binding"
+}
+
+// BeforeCreate will set a UUID rather than numeric ID.
+func (b *ConnectorRoleBinding) BeforeCreate(db *gorm.DB) error {
+	uuid, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	db.Statement.SetColumn("UID", uuid)
+	return nil
+}
+
+// ConnectorOperationState is the state of an asynchronous connector execution
+type ConnectorOperationState string
+
+// Connector operation states, an operation is done once it has succeeded, failed or has been cancelled
+const (
+	OperationStatePending   ConnectorOperationState = "pending"
+	OperationStateRunning   ConnectorOperationState = "running"
+	OperationStateSucceeded ConnectorOperationState = "succeeded"
+	OperationStateFailed    ConnectorOperationState = "failed"
+	OperationStateCancelled ConnectorOperationState = "cancelled"
+)
+
+// IsDone reports whether the operation has reached a final state
+func (s ConnectorOperationState) IsDone() bool {
+	return s == OperationStateSucceeded || s == OperationStateFailed || s == OperationStateCancelled
+}
+
+// ConnectorOperation is the data model of the connector_operation table, an asynchronous execution of a
+// connector. Outputs holds the execution outputs once it has succeeded and Error the google.rpc.Status
+// once it has failed or has been cancelled. Worker is the host whose execution pool runs the operation.
+type ConnectorOperation struct {
+	UID          uuid.UUID `gorm:"type:uuid;primary_key;<-:create"` // allow read and create
+	Owner        string
+	ConnectorUID uuid.UUID
+	Task         string
+	State        ConnectorOperationState `sql:"type:valid_operation_state"`
+	Outputs      datatypes.JSON          `gorm:"type:jsonb"`
+	Error        datatypes.JSON          `gorm:"type:jsonb"`
+	Worker       string
+	CreateTime   time.Time `gorm:"autoCreateTime:nano"`
+	UpdateTime   time.Time `gorm:"autoUpdateTime:nano"`
+	StartTime    sql.NullTime
+	EndTime      sql.NullTime
+}
+
+func (ConnectorOperation) TableName() string {
+	return "connector_operation"
+}
+
+// BeforeCreate will set a UUID rather than numeric ID.
+func (o *ConnectorOperation) BeforeCreate(db *gorm.DB) error {
+	uuid, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	db.Statement.SetColumn("UID", uuid)
+	return nil
+}
+
 // ConnectorResourceType is an alias type for Protobuf enum ConnectorType
 type ConnectorResourceVisibility connectorPB.ConnectorResource_Visibility
 
@@ -112,3 +309,105 @@ func (r *Task) Scan(value interface{}) error {
 func (r Task) Value() (driver.Value, error) {
 	return taskPB.Task(r).String(), nil
 }
+
+// RateLimitScope is the scope of the executions a rate limit applies to
+type RateLimitScope string
+
+// Rate limit scopes, the subject of a rate limit is respectively the connector uid, the namespace permalink
+// and the connector definition uid
+const (
+	RateLimitScopeConnector           RateLimitScope = "connector"
+	RateLimitScopeNamespace           RateLimitScope = "namespace"
+	RateLimitScopeConnectorDefinition RateLimitScope = "connector_definition"
+)
+
+// RateLimit is the data model of the rate_limit table, a token bucket refilled with Rate tokens per second
+// up to Burst tokens. Each execution of the subject takes a token.
+type RateLimit struct {
+	UID        uuid.UUID      `gorm:"type:uuid;primary_key;<-:create"` // allow read and create
+	Scope      RateLimitScope `sql:"type:valid_rate_limit_scope"`
+	Subject    string
+	Rate       float64
+	Burst      int32
+	CreateTime time.Time `gorm:"autoCreateTime:nano"`
+	UpdateTime time.Time `gorm:"autoUpdateTime:nano"`
+}
+
+func (RateLimit) TableName() string {
+	return "rate_limit"
+}
+
+// BeforeCreate will set a UUID rather than numeric ID.
+func (l *RateLimit) BeforeCreate(db *gorm.DB) error {
+	uuid, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+	db.Statement.SetColumn("UID", uuid)
+	return nil
+}
+
+// ConnectorExecutionStatus is the status of a recorded connector execution
+type ConnectorExecutionStatus string
+
+// Connector execution statuses, an execution has errored if any of its inputs has failed
+const (
+	ExecutionStatusCompleted ConnectorExecutionStatus = "completed"
+	ExecutionStatusErrored   ConnectorExecutionStatus = "errored"
+)
+
+// ConnectorExecution is the data model of the connector_execution table, the record of an execution of a
+// connector. UID is the execute uid of the usage data point, Pipeline the metadata of the pipeline that has
+// triggered the execution if any. Duration is in seconds and the sizes are the bytes of the inputs and outputs.
+type ConnectorExecution struct {
+	UID          uuid.UUID `gorm:"type:uuid;primary_key;<-:create"` // allow read and create
+	ConnectorUID uuid.UUID
+	UserUID      uuid.UUID
+	Task         string
+	Pipeline     datatypes.JSON           `gorm:"type:jsonb"`
+	Status       ConnectorExecutionStatus `sql:"type:valid_execution_status"`
+	Duration     float64
+	ErrorMessage string
+	InputSize    int64
+	OutputSize   int64
+	CreateTime   time.Time `gorm:"autoCreateTime:nano"`
+}
+
+func (ConnectorExecution) TableName() string {
+	return "connector_execution"
+}
+
+// ConnectorCapture is the data model of the connector_capture table, the payloads of an execution captured
+// while the debug capture of its connector is enabled. UID is the execute uid of the execution. The payloads
+// are dropped and Truncated is set if they are over the size limit.
+type ConnectorCapture struct {
+	UID          uuid.UUID `gorm:"type:uuid;primary_key;<-:create"` // allow read and create
+	ConnectorUID uuid.UUID
+	Inputs       datatypes.JSON `gorm:"type:jsonb"`
+	Outputs      datatypes.JSON `gorm:"type:jsonb"`
+	ErrorMessage string
+	Truncated    bool
+	CreateTime   time.Time `gorm:"autoCreateTime:nano"`
+	ExpireTime   time.Time
+}
+
+func (ConnectorCapture) TableName() string {
+	return "connector_capture"
+}
+
+// ConnectorDeadLetter is the data model of the connector_dead_letter table, the inputs of a failed execution
+// kept to be replayed. UID is the execute uid of the failed execution.
+type ConnectorDeadLetter struct {
+	UID          uuid.UUID `gorm:"type:uuid;primary_key;<-:create"` // allow read and create
+	ConnectorUID uuid.UUID
+	UserUID      uuid.UUID
+	Task         string
+	Inputs       datatypes.JSON `gorm:"type:jsonb"`
+	ErrorMessage string
+	CreateTime   time.Time `gorm:"autoCreateTime:nano"`
+	ExpireTime   time.Time
+}
+
+func (ConnectorDeadLetter) TableName() string {
+	return "connector_dead_letter"
+}
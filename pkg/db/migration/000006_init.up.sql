@@ -0,0 +1,18 @@
+BEGIN;
+
+-- secret
+CREATE TABLE IF NOT EXISTS public.secret(
+  "uid" UUID NOT NULL,
+  "id" VARCHAR(255) NOT NULL,
+  "owner" VARCHAR(255) NOT NULL,
+  "description" VARCHAR(1023) NULL,
+  "value" TEXT NOT NULL,
+  "create_time" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  "update_time" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  "delete_time" TIMESTAMPTZ NULL,
+  CONSTRAINT secret_pkey PRIMARY KEY (uid)
+);
+CREATE UNIQUE INDEX unique_secret_owner_id_deleted_at ON public.secret (owner, id) WHERE delete_time IS NULL;
+CREATE INDEX secret_uid_create_time_pagination ON public.secret (uid, create_time);
+
+COMMIT;
@@ -0,0 +1,18 @@
+BEGIN;
+
+-- connector_revision
+CREATE TABLE IF NOT EXISTS public.connector_revision(
+  "uid" UUID NOT NULL,
+  "connector_uid" UUID NOT NULL,
+  "revision" INTEGER NOT NULL,
+  "configuration" JSONB NOT NULL,
+  "diff" JSONB NOT NULL,
+  "description" VARCHAR(1023) NULL,
+  "visibility" VALID_VISIBILITY DEFAULT 'VISIBILITY_PRIVATE' NOT NULL,
+  "creator" VARCHAR(255) NOT NULL,
+  "create_time" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  CONSTRAINT connector_revision_pkey PRIMARY KEY (uid)
+);
+CREATE UNIQUE INDEX unique_connector_revision_connector_uid_revision ON public.connector_revision (connector_uid, revision);
+
+COMMIT;
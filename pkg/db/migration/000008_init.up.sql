@@ -0,0 +1,23 @@
+BEGIN;
+
+CREATE TYPE valid_connector_role AS ENUM (
+  'viewer',
+  'executor',
+  'editor',
+  'admin'
+);
+
+-- connector_role_binding
+CREATE TABLE IF NOT EXISTS public.connector_role_binding(
+  "uid" UUID NOT NULL,
+  "connector_uid" UUID NOT NULL,
+  "subject" VARCHAR(255) NOT NULL,
+  "role" VALID_CONNECTOR_ROLE NOT NULL,
+  "create_time" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  "update_time" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  CONSTRAINT connector_role_binding_pkey PRIMARY KEY (uid)
+);
+CREATE UNIQUE INDEX unique_connector_role_binding_connector_uid_subject ON public.connector_role_binding (connector_uid, subject);
+CREATE INDEX connector_role_binding_subject_idx ON public.connector_role_binding (subject);
+
+COMMIT;
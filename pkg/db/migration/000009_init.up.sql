@@ -0,0 +1,30 @@
+BEGIN;
+
+CREATE TYPE valid_operation_state AS ENUM (
+  'pending',
+  'running',
+  'succeeded',
+  'failed',
+  'cancelled'
+);
+
+-- connector_operation
+CREATE TABLE IF NOT EXISTS public.connector_operation(
+  "uid" UUID NOT NULL,
+  "owner" VARCHAR(255) NOT NULL,
+  "connector_uid" UUID NOT NULL,
+  "task" VARCHAR(255) NOT NULL,
+  "state" VALID_OPERATION_STATE DEFAULT 'pending' NOT NULL,
+  "outputs" JSONB NULL,
+  "error" JSONB NULL,
+  "worker" VARCHAR(255) NOT NULL,
+  "create_time" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  "update_time" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  "start_time" TIMESTAMPTZ NULL,
+  "end_time" TIMESTAMPTZ NULL,
+  CONSTRAINT connector_operation_pkey PRIMARY KEY (uid)
+);
+CREATE INDEX connector_operation_owner_create_time_pagination ON public.connector_operation (owner, create_time, uid);
+CREATE INDEX connector_operation_worker_state_idx ON public.connector_operation (worker, state);
+
+COMMIT;
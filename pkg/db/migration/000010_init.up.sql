@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE public.connector ADD COLUMN IF NOT EXISTS "retry_policy" JSONB NULL;
+
+COMMIT;
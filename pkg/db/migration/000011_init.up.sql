@@ -0,0 +1,22 @@
+BEGIN;
+
+CREATE TYPE valid_rate_limit_scope AS ENUM (
+  'connector',
+  'namespace',
+  'connector_definition'
+);
+
+-- rate_limit
+CREATE TABLE IF NOT EXISTS public.rate_limit(
+  "uid" UUID NOT NULL,
+  "scope" VALID_RATE_LIMIT_SCOPE NOT NULL,
+  "subject" VARCHAR(255) NOT NULL,
+  "rate" DOUBLE PRECISION NOT NULL,
+  "burst" INTEGER NOT NULL,
+  "create_time" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  "update_time" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  CONSTRAINT rate_limit_pkey PRIMARY KEY (uid)
+);
+CREATE UNIQUE INDEX unique_rate_limit_scope_subject ON public.rate_limit (scope, subject);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE public.connector ADD COLUMN IF NOT EXISTS "max_concurrency" INTEGER DEFAULT 0 NOT NULL;
+
+COMMIT;
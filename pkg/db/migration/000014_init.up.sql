@@ -0,0 +1,26 @@
+BEGIN;
+
+CREATE TYPE valid_execution_status AS ENUM (
+  'completed',
+  'errored'
+);
+
+-- connector_execution
+CREATE TABLE IF NOT EXISTS public.connector_execution(
+  "uid" UUID NOT NULL,
+  "connector_uid" UUID NOT NULL,
+  "user_uid" UUID NOT NULL,
+  "task" VARCHAR(255) NOT NULL,
+  "pipeline" JSONB NULL,
+  "status" VALID_EXECUTION_STATUS NOT NULL,
+  "duration" DOUBLE PRECISION NOT NULL,
+  "error_message" TEXT NULL,
+  "input_size" BIGINT DEFAULT 0 NOT NULL,
+  "output_size" BIGINT DEFAULT 0 NOT NULL,
+  "create_time" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  CONSTRAINT connector_execution_pkey PRIMARY KEY (uid)
+);
+CREATE INDEX connector_execution_connector_uid_create_time_pagination ON public.connector_execution (connector_uid, create_time, uid);
+CREATE INDEX connector_execution_create_time_idx ON public.connector_execution (create_time);
+
+COMMIT;
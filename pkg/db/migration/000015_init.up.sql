@@ -0,0 +1,19 @@
+BEGIN;
+
+ALTER TABLE public.connector ADD COLUMN IF NOT EXISTS "debug_capture" BOOLEAN DEFAULT FALSE NOT NULL;
+
+-- connector_capture
+CREATE TABLE IF NOT EXISTS public.connector_capture(
+  "uid" UUID NOT NULL,
+  "connector_uid" UUID NOT NULL,
+  "inputs" JSONB NULL,
+  "outputs" JSONB NULL,
+  "error_message" TEXT NULL,
+  "truncated" BOOLEAN DEFAULT FALSE NOT NULL,
+  "create_time" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  "expire_time" TIMESTAMPTZ NOT NULL,
+  CONSTRAINT connector_capture_pkey PRIMARY KEY (uid)
+);
+CREATE INDEX connector_capture_expire_time_idx ON public.connector_capture (expire_time);
+
+COMMIT;
@@ -0,0 +1,18 @@
+BEGIN;
+
+-- connector_dead_letter
+CREATE TABLE IF NOT EXISTS public.connector_dead_letter(
+  "uid" UUID NOT NULL,
+  "connector_uid" UUID NOT NULL,
+  "user_uid" UUID NOT NULL,
+  "task" VARCHAR(255) NOT NULL,
+  "inputs" JSONB NOT NULL,
+  "error_message" TEXT NULL,
+  "create_time" TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  "expire_time" TIMESTAMPTZ NOT NULL,
+  CONSTRAINT connector_dead_letter_pkey PRIMARY KEY (uid)
+);
+CREATE INDEX connector_dead_letter_connector_uid_create_time_idx ON public.connector_dead_letter (connector_uid, create_time);
+CREATE INDEX connector_dead_letter_expire_time_idx ON public.connector_dead_letter (expire_time);
+
+COMMIT;
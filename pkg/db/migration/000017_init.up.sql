@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE public.connector ADD COLUMN IF NOT EXISTS "defaulted_fields" JSONB DEFAULT '[]' NOT NULL;
+
+COMMIT;
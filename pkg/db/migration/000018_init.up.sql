@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE public.connector ADD COLUMN IF NOT EXISTS "labels" JSONB DEFAULT '{}' NOT NULL;
+CREATE INDEX IF NOT EXISTS connector_labels_idx ON public.connector USING GIN (labels);
+
+COMMIT;
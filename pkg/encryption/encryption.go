@@ -0,0 +1,157 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/instill-ai/connector-backend/config"
+)
+
+// encryptedPrefix marks a value produced by Envelope.Encrypt
+const encryptedPrefix = "instill-enc:v1:"
+
+// KeyProvider wraps and unwraps the per-value data keys with a key encryption key
+type KeyProvider interface {
+	// KeyID identifies the key encryption key used to wrap new data keys
+	KeyID() string
+	// WrapKey encrypts a data key with the current key encryption key
+	WrapKey(dataKey []byte) ([]byte, error)
+	// UnwrapKey decrypts a data key wrapped by the key encryption key keyID
+	UnwrapKey(keyID string, wrappedKey []byte) ([]byte, error)
+}
+
+// NewKeyProvider initiates the key provider selected in the configuration
+func NewKeyProvider(cfg config.EncryptionConfig) (KeyProvider, error) {
+	switch cfg.Provider {
+	case "local":
+		return NewLocalKeyProvider(cfg.Local.KeyFile)
+	default:
+		return nil, fmt.Errorf("unsupported encryption key provider %q", cfg.Provider)
+	}
+}
+
+// Envelope encrypts values with a fresh AES-256-GCM data key that is wrapped by a KeyProvider
+type Envelope struct {
+	provider KeyProvider
+}
+
+// NewEnvelope initiates an Envelope instance
+func NewEnvelope(p KeyProvider) *Envelope {
+	return &Envelope{
+		provider: p,
+	}
+}
+
+// IsEncrypted reports whether the value was produced by Encrypt
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, encryptedPrefix)
+}
+
+// Encrypt encrypts the plaintext value, values that are already encrypted are returned as is. The callers must
+// reject the encrypted values that were not read from the store.
+func (e *Envelope) Encrypt(plaintext string) (string, error) {
+	if IsEncrypted(plaintext) {
+		return plaintext, nil
+	}
+
+	dataKey := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return "", err
+	}
+
+	ciphertext, err := seal(dataKey, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+
+	wrappedKey, err := e.provider.WrapKey(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s%s:%s:%s",
+		encryptedPrefix,
+		e.provider.KeyID(),
+		base64.RawStdEncoding.EncodeToString(wrappedKey),
+		base64.RawStdEncoding.EncodeToString(ciphertext),
+	), nil
+}
+
+// Decrypt decrypts a value produced by Encrypt, plaintext values are returned as is
+func (e *Envelope) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	parts := strings.Split(strings.TrimPrefix(value, encryptedPrefix), ":")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("malformed encrypted value")
+	}
+
+	wrappedKey, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("malformed encrypted value: %w", err)
+	}
+	ciphertext, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", fmt.Errorf("malformed encrypted value: %w", err)
+	}
+
+	dataKey, err := e.provider.UnwrapKey(parts[0], wrappedKey)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := open(dataKey, ciphertext)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+// Fingerprint returns a digest of a plaintext value that identifies it without revealing it
+func Fingerprint(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
+
+// seal encrypts the plaintext with AES-GCM and prepends the nonce to the ciphertext
+func seal(key []byte, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open decrypts a ciphertext produced by seal
+func open(key []byte, ciphertext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, fmt.Errorf("malformed ciphertext")
+	}
+	return gcm.Open(nil, ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():], nil)
+}
@@ -0,0 +1,186 @@
+package encryption
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestEnvelope(t *testing.T) (*Envelope, *LocalKeyProvider) {
+	t.Helper()
+	provider, err := NewLocalKeyProvider(filepath.Join(t.TempDir(), "kek"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewEnvelope(provider), provider
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	envelope, _ := newTestEnvelope(t)
+
+	for _, plaintext := range []string{"", "sk-123", "ünïcödé", strings.Repeat("a", 4096)} {
+		encrypted, err := envelope.Encrypt(plaintext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !IsEncrypted(encrypted) {
+			t.Errorf("%q is not marked as encrypted", encrypted)
+		}
+		if plaintext != "" && strings.Contains(encrypted, plaintext) {
+			t.Errorf("%q holds the plaintext", encrypted)
+		}
+
+		decrypted, err := envelope.Decrypt(encrypted)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if decrypted != plaintext {
+			t.Errorf("got %q, want %q", decrypted, plaintext)
+		}
+	}
+}
+
+func TestEncryptUsesFreshDataKeys(t *testing.T) {
+	envelope, _ := newTestEnvelope(t)
+
+	first, err := envelope.Encrypt("sk-123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := envelope.Encrypt("sk-123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == second {
+		t.Error("the same plaintext is encrypted to the same value")
+	}
+}
+
+func TestDecryptPlaintext(t *testing.T) {
+	envelope, _ := newTestEnvelope(t)
+
+	decrypted, err := envelope.Decrypt("sk-123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decrypted != "sk-123" {
+		t.Errorf("got %q, want the plaintext as is", decrypted)
+	}
+}
+
+func TestDecryptErrors(t *testing.T) {
+	envelope, provider := newTestEnvelope(t)
+
+	encrypted, err := envelope.Encrypt("sk-123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(strings.TrimPrefix(encrypted, encryptedPrefix), ":")
+
+	// Flips a bit of the base64 decoded part
+	tamper := func(part string) string {
+		b, err := base64.RawStdEncoding.DecodeString(part)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b[len(b)-1] ^= 1
+		return base64.RawStdEncoding.EncodeToString(b)
+	}
+
+	otherEnvelope, _ := newTestEnvelope(t)
+	otherEncrypted, err := otherEnvelope.Encrypt("sk-123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name  string
+		value string
+	}{
+		{
+			name:  "tampered ciphertext",
+			value: encryptedPrefix + strings.Join([]string{parts[0], parts[1], tamper(parts[2])}, ":"),
+		},
+		{
+			name:  "tampered data key",
+			value: encryptedPrefix + strings.Join([]string{parts[0], tamper(parts[1]), parts[2]}, ":"),
+		},
+		{
+			name:  "unknown key id",
+			value: encryptedPrefix + strings.Join([]string{"local-0000000000000000", parts[1], parts[2]}, ":"),
+		},
+		{
+			name:  "other key encryption key",
+			value: otherEncrypted,
+		},
+		{
+			name:  "missing part",
+			value: encryptedPrefix + strings.Join([]string{provider.KeyID(), parts[1]}, ":"),
+		},
+		{
+			name:  "invalid base64",
+			value: encryptedPrefix + strings.Join([]string{provider.KeyID(), "!!!", parts[2]}, ":"),
+		},
+		{
+			name:  "truncated ciphertext",
+			value: encryptedPrefix + strings.Join([]string{provider.KeyID(), parts[1], "AAAA"}, ":"),
+		},
+		{
+			name:  "prefix only",
+			value: encryptedPrefix,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if decrypted, err := envelope.Decrypt(tc.value); err == nil {
+				t.Errorf("got %q, want an error", decrypted)
+			}
+		})
+	}
+}
+
+func TestLocalKeyProvider(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "keys", "kek")
+
+	first, err := NewLocalKeyProvider(keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("got key file mode %v, want 0600", info.Mode().Perm())
+	}
+
+	// The generated key is loaded again
+	second, err := NewLocalKeyProvider(keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.KeyID() != second.KeyID() {
+		t.Errorf("got key id %s, want %s", second.KeyID(), first.KeyID())
+	}
+	encrypted, err := NewEnvelope(first).Encrypt("sk-123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decrypted, err := NewEnvelope(second).Decrypt(encrypted); err != nil || decrypted != "sk-123" {
+		t.Errorf("got %q, %v, want the plaintext", decrypted, err)
+	}
+
+	invalidKeyFile := filepath.Join(t.TempDir(), "invalid")
+	if err := os.WriteFile(invalidKeyFile, []byte(base64.StdEncoding.EncodeToString([]byte("short"))), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewLocalKeyProvider(invalidKeyFile); err == nil {
+		t.Error("a short key is accepted")
+	}
+	if _, err := NewLocalKeyProvider(""); err == nil {
+		t.Error("an empty key file is accepted")
+	}
+}
@@ -0,0 +1,86 @@
+package encryption
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalKeyProvider keeps the key encryption key in a local file. It is meant
+// for development only: the key file is generated on first use and every
+// replica must share the same file to read each other's values.
+type LocalKeyProvider struct {
+	keyID string
+	key   []byte
+}
+
+// NewLocalKeyProvider loads the key encryption key from keyFile, generating it if it does not exist
+func NewLocalKeyProvider(keyFile string) (*LocalKeyProvider, error) {
+
+	if keyFile == "" {
+		return nil, fmt.Errorf("local key provider requires a key file")
+	}
+
+	b, err := os.ReadFile(keyFile)
+	if errors.Is(err, os.ErrNotExist) {
+		b, err = generateKeyFile(keyFile)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(b)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid key file %s: %w", keyFile, err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("invalid key file %s: key must be 32 bytes", keyFile)
+	}
+
+	sum := sha256.Sum256(key)
+
+	return &LocalKeyProvider{
+		keyID: fmt.Sprintf("local-%s", hex.EncodeToString(sum[:8])),
+		key:   key,
+	}, nil
+}
+
+func generateKeyFile(keyFile string) ([]byte, error) {
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(keyFile), 0700); err != nil {
+		return nil, err
+	}
+	b := []byte(base64.StdEncoding.EncodeToString(key))
+	if err := os.WriteFile(keyFile, b, 0600); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// KeyID returns the identifier of the local key
+func (p *LocalKeyProvider) KeyID() string {
+	return p.keyID
+}
+
+// WrapKey encrypts the data key with the local key
+func (p *LocalKeyProvider) WrapKey(dataKey []byte) ([]byte, error) {
+	return seal(p.key, dataKey)
+}
+
+// UnwrapKey decrypts the data key with the local key
+func (p *LocalKeyProvider) UnwrapKey(keyID string, wrappedKey []byte) ([]byte, error) {
+	if keyID != p.keyID {
+		return nil, fmt.Errorf("unknown key id %s", keyID)
+	}
+	return open(p.key, wrappedKey)
+}
@@ -0,0 +1,264 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+
+	"github.com/instill-ai/connector-backend/internal/resource"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/x/sterr"
+
+	custom_otel "github.com/instill-ai/connector-backend/pkg/logger/otel"
+)
+
+// roleBindingResource is the API representation of a role granted on a connector resource
+type roleBindingResource struct {
+	Name       string                  `json:"name"`
+	Subject    string                  `json:"subject"`
+	Role       datamodel.ConnectorRole `json:"role"`
+	CreateTime time.Time               `json:"create_time"`
+	UpdateTime time.Time               `json:"update_time"`
+}
+
+type bindRoleRequest struct {
+	Subject string                  `json:"subject"`
+	Role    datamodel.ConnectorRole `json:"role"`
+}
+
+type listRoleBindingsResponse struct {
+	RoleBindings []*roleBindingResource `json:"role_bindings"`
+}
+
+type checkPermissionResponse struct {
+	Allowed bool                    `json:"allowed"`
+	Role    datamodel.ConnectorRole `json:"role"`
+}
+
+func (h *HTTPHandler) convertRoleBinding(connName string, binding *datamodel.ConnectorRoleBinding) *roleBindingResource {
+	// The subject may not exist anymore
+	subject, err := h.service.ConvertOwnerPermalinkToName(binding.Subject)
+	if err != nil {
+		subject = binding.Subject
+	}
+	return &roleBindingResource{
+		Name:       fmt.Sprintf("%s/roleBindings/%s", connName, subject),
+		Subject:    subject,
+		Role:       binding.Role,
+		CreateTime: binding.CreateTime,
+		UpdateTime: binding.UpdateTime,
+	}
+}
+
+func (h *HTTPHandler) ListUserConnectorRoleBindings(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "ListUserConnectorRoleBindings"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	ns, connID, err := h.service.GetRscNamespaceAndNameID(pathParams["name"])
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	bindings, err := h.service.ListUserConnectorRoleBindings(ctx, ns, userUid, connID)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	resp := &listRoleBindingsResponse{
+		RoleBindings: []*roleBindingResource{},
+	}
+	for _, binding := range bindings {
+		resp.RoleBindings = append(resp.RoleBindings, h.convertRoleBinding(pathParams["name"], binding))
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+	)))
+
+	return resp, http.StatusOK, nil
+}
+
+func (h *HTTPHandler) BindUserConnectorRole(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "UpdateUserConnectorRoleBinding"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	req := &bindRoleRequest{}
+	if err := decodeHTTPBody(r, req); err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	ns, connID, err := h.service.GetRscNamespaceAndNameID(pathParams["name"])
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	binding, err := h.service.BindUserConnectorRole(ctx, ns, userUid, connID, req.Subject, req.Role)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	resp := h.convertRoleBinding(pathParams["name"], binding)
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+		custom_otel.SetEventResource(resp),
+	)))
+
+	return map[string]interface{}{"role_binding": resp}, http.StatusOK, nil
+}
+
+func (h *HTTPHandler) UnbindUserConnectorRole(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "DeleteUserConnectorRoleBinding"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	name := pathParams["name"]
+	connName := name[:strings.LastIndex(name, "/roleBindings/")]
+	subject := name[strings.LastIndex(name, "/roleBindings/")+len("/roleBindings/"):]
+
+	ns, connID, err := h.service.GetRscNamespaceAndNameID(connName)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	if err := h.service.UnbindUserConnectorRole(ctx, ns, userUid, connID, subject); err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+		custom_otel.SetEventMessage(fmt.Sprintf("role binding of %s on %s deleted", subject, connName)),
+	)))
+
+	return nil, http.StatusNoContent, nil
+}
+
+// parseCheckPermissionRequest parses the arguments of a permission check: the connector uid, the user
+// permalink users/{uid} and the role to check
+func parseCheckPermissionRequest(uid string, user string, role string) (uuid.UUID, uuid.UUID, datamodel.ConnectorRole, error) {
+
+	connUID, err := uuid.FromString(uid)
+	if err != nil {
+		st, _ := sterr.CreateErrorBadRequest(
+			"[handler] check permission error",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "uid",
+					Description: err.Error(),
+				},
+			},
+		)
+		return uuid.Nil, uuid.Nil, "", st.Err()
+	}
+
+	userUid := uuid.Nil
+	if strings.HasPrefix(user, string(resource.User)+"/") {
+		userUid = uuid.FromStringOrNil(strings.TrimPrefix(user, string(resource.User)+"/"))
+	}
+	if userUid == uuid.Nil {
+		st, _ := sterr.CreateErrorBadRequest(
+			"[handler] check permission error",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "user",
+					Description: "the user must be a user permalink users/{uid}",
+				},
+			},
+		)
+		return uuid.Nil, uuid.Nil, "", st.Err()
+	}
+
+	connectorRole := datamodel.ConnectorRole(role)
+	if !connectorRole.IsValid() {
+		st, _ := sterr.CreateErrorBadRequest(
+			"[handler] check permission error",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "role",
+					Description: fmt.Sprintf("unknown role %q", role),
+				},
+			},
+		)
+		return uuid.Nil, uuid.Nil, "", st.Err()
+	}
+
+	return connUID, userUid, connectorRole, nil
+}
+
+// CheckConnectorPermissionAdmin lets the other backends check the role of a user on a connector resource, the
+// gRPC backends call the ConnectorPermissionPrivateService instead
+func (h *HTTPHandler) CheckConnectorPermissionAdmin(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	connUID, userUid, role, err := parseCheckPermissionRequest(pathParams["uid"], r.URL.Query().Get("user"), r.URL.Query().Get("role"))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	userRole, allowed, err := h.service.CheckConnectorPermissionAdmin(ctx, connUID, userUid, role)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return &checkPermissionResponse{Allowed: allowed, Role: userRole}, http.StatusOK, nil
+}
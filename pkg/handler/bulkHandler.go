@@ -0,0 +1,417 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gofrs/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/instill-ai/connector-backend/internal/resource"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/connector-backend/pkg/service"
+	"github.com/instill-ai/x/sterr"
+
+	custom_otel "github.com/instill-ai/connector-backend/pkg/logger/otel"
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+	codepb "google.golang.org/genproto/googleapis/rpc/code"
+)
+
+type bulkCreateRequest struct {
+	ConnectorResources []json.RawMessage `json:"connector_resources"`
+	AllOrNothing       bool              `json:"all_or_nothing"`
+}
+
+// bulkUpdateRequest holds UpdateUserConnectorResourceRequest messages
+type bulkUpdateRequest struct {
+	Requests     []json.RawMessage `json:"requests"`
+	AllOrNothing bool              `json:"all_or_nothing"`
+}
+
+type bulkDeleteRequest struct {
+	Names        []string `json:"names"`
+	AllOrNothing bool     `json:"all_or_nothing"`
+}
+
+// decodeBulkItems unmarshals the protobuf messages of a bulk request body
+func decodeBulkItems[T proto.Message](field string, items []json.RawMessage, newItem func() T) ([]T, error) {
+	messages := make([]T, len(items))
+	for idx, item := range items {
+		messages[idx] = newItem()
+		if err := protojson.Unmarshal(item, messages[idx]); err != nil {
+			st, _ := sterr.CreateErrorBadRequest(
+				"[handler] invalid request body",
+				[]*errdetails.BadRequest_FieldViolation{
+					{
+						Field:       fmt.Sprintf("%s[%d]", field, idx),
+						Description: err.Error(),
+					},
+				},
+			)
+			return nil, st.Err()
+		}
+	}
+	return messages, nil
+}
+
+// runBulkItems runs the items which have no result yet through the service and places their results next to
+// the ones of the items rejected by the handler. With allOrNothing, a rejected item aborts the whole request.
+func runBulkItems(results []*service.BulkItemResult, allOrNothing bool, run func(indices []int) ([]*service.BulkItemResult, error)) ([]*service.BulkItemResult, error) {
+
+	indices := []int{}
+	rejected := false
+	for idx, result := range results {
+		if result == nil {
+			indices = append(indices, idx)
+		} else if result.Status.Code() != codes.OK {
+			rejected = true
+		}
+	}
+
+	if rejected && allOrNothing {
+		for idx, result := range results {
+			if result == nil || result.Status.Code() == codes.OK {
+				results[idx] = &service.BulkItemResult{
+					Status: status.New(codes.Aborted, "[handler] not run, another item of the bulk request is invalid"),
+				}
+			}
+		}
+		return results, nil
+	}
+
+	if len(indices) == 0 {
+		return results, nil
+	}
+	runResults, err := run(indices)
+	if err != nil {
+		return nil, err
+	}
+	for i, idx := range indices {
+		results[idx] = runResults[i]
+	}
+	return results, nil
+}
+
+// bulkItemResultResource is the result of an item in a bulk response. The connector resource is marshaled on its
+// own, the gateway marshaler only handles the protobuf messages at the top level of a response or in a map.
+type bulkItemResultResource struct {
+	Code              string          `json:"code"`
+	Message           string          `json:"message,omitempty"`
+	ConnectorResource json.RawMessage `json:"connector_resource,omitempty"`
+}
+
+type bulkResponse struct {
+	Results []*bulkItemResultResource `json:"results"`
+}
+
+// bulkMarshalOptions are the options of the gateway marshaler
+var bulkMarshalOptions = protojson.MarshalOptions{
+	UseProtoNames:   true,
+	EmitUnpopulated: true,
+}
+
+func convertBulkResults(results []*service.BulkItemResult) (*bulkResponse, error) {
+	resp := &bulkResponse{Results: make([]*bulkItemResultResource, len(results))}
+	for idx, result := range results {
+		item := &bulkItemResultResource{
+			Code:    codepb.Code(result.Status.Code()).String(),
+			Message: result.Status.Message(),
+		}
+		if result.ConnectorResource != nil {
+			b, err := bulkMarshalOptions.Marshal(result.ConnectorResource)
+			if err != nil {
+				return nil, err
+			}
+			item.ConnectorResource = b
+		}
+		resp.Results[idx] = item
+	}
+	return resp, nil
+}
+
+// countFailedBulkItems returns the number of items which are not OK
+func countFailedBulkItems(results []*service.BulkItemResult) int {
+	failed := 0
+	for _, result := range results {
+		if result.Status.Code() != codes.OK {
+			failed++
+		}
+	}
+	return failed
+}
+
+// BulkCreateUserConnectorResources creates up to service.MaxBulkConnectorResources connectors in one transaction
+func (h *HTTPHandler) BulkCreateUserConnectorResources(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "CreateUserConnectorResources"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	req := &bulkCreateRequest{}
+	if err := decodeHTTPBody(r, req); err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	if err := service.CheckBulkSize(len(req.ConnectorResources)); err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	connectorResources, err := decodeBulkItems("connector_resources", req.ConnectorResources, func() *connectorPB.ConnectorResource {
+		return &connectorPB.ConnectorResource{}
+	})
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	ns, _, err := h.service.GetRscNamespaceAndNameID(pathParams["parent"])
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	userId, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	// Roles are granted per connector, only the owner of a namespace can create connectors in it
+	if ns.String() != resource.UserUidToUserPermalink(userUid) {
+		st, _ := sterr.CreateErrorBadRequest(
+			"[handler] create connector error",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Description: "can not create in other user's namespace",
+				},
+			},
+		)
+		span.SetStatus(1, st.Err().Error())
+		return nil, 0, st.Err()
+	}
+
+	results := make([]*service.BulkItemResult, len(connectorResources))
+	for idx, connectorResource := range connectorResources {
+		if err := checkConnectorResourceToCreate(ctx, connectorResource); err != nil {
+			results[idx] = &service.BulkItemResult{Status: status.Convert(err)}
+			continue
+		}
+		connectorResource.Owner = &connectorPB.ConnectorResource_User{User: fmt.Sprintf("users/%s", userId)}
+	}
+
+	results, err = runBulkItems(results, req.AllOrNothing, func(indices []int) ([]*service.BulkItemResult, error) {
+		toCreate := make([]*connectorPB.ConnectorResource, len(indices))
+		for i, idx := range indices {
+			toCreate[i] = connectorResources[idx]
+		}
+		return h.service.BulkCreateUserConnectorResources(ctx, ns, userUid, toCreate, req.AllOrNothing)
+	})
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+		custom_otel.SetEventMessage(fmt.Sprintf("%d connectors to create, %d failed", len(results), countFailedBulkItems(results))),
+	)))
+
+	resp, err := convertBulkResults(results)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	return resp, http.StatusOK, nil
+}
+
+// BulkUpdateUserConnectorResources updates up to service.MaxBulkConnectorResources connectors in one transaction
+func (h *HTTPHandler) BulkUpdateUserConnectorResources(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "UpdateUserConnectorResources"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	req := &bulkUpdateRequest{}
+	if err := decodeHTTPBody(r, req); err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	if err := service.CheckBulkSize(len(req.Requests)); err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	updateRequests, err := decodeBulkItems("requests", req.Requests, func() *connectorPB.UpdateUserConnectorResourceRequest {
+		return &connectorPB.UpdateUserConnectorResourceRequest{}
+	})
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	ns, _, err := h.service.GetRscNamespaceAndNameID(pathParams["parent"])
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	results := make([]*service.BulkItemResult, len(updateRequests))
+	connectorResources := make([]*connectorPB.ConnectorResource, len(updateRequests))
+	for idx, updateRequest := range updateRequests {
+		itemNs, connID, err := h.service.GetRscNamespaceAndNameID(updateRequest.GetConnectorResource().GetName())
+		if err == nil && itemNs.String() != ns.String() {
+			err = status.Errorf(codes.InvalidArgument, "[handler] %s is not under %s", updateRequest.GetConnectorResource().GetName(), pathParams["parent"])
+		}
+		if err != nil {
+			results[idx] = &service.BulkItemResult{Status: status.Convert(err)}
+			continue
+		}
+
+		connectorResources[idx], err = prepareConnectorResourceUpdate(ctx, h.service, ns, userUid, connID, updateRequest)
+		if err != nil {
+			results[idx] = &service.BulkItemResult{Status: status.Convert(err)}
+			continue
+		}
+
+		// Nothing to update, the existing connector is returned
+		if connectorResources[idx] == nil {
+			existedConnectorResource, err := h.service.GetUserConnectorResourceByID(ctx, ns, userUid, connID, connectorPB.View_VIEW_FULL, true)
+			if err != nil {
+				results[idx] = &service.BulkItemResult{Status: status.Convert(err)}
+				continue
+			}
+			results[idx] = &service.BulkItemResult{
+				ConnectorResource: existedConnectorResource,
+				Status:            status.New(codes.OK, ""),
+			}
+		}
+	}
+
+	results, err = runBulkItems(results, req.AllOrNothing, func(indices []int) ([]*service.BulkItemResult, error) {
+		toUpdate := make([]*connectorPB.ConnectorResource, len(indices))
+		for i, idx := range indices {
+			toUpdate[i] = connectorResources[idx]
+		}
+		return h.service.BulkUpdateUserConnectorResources(ctx, ns, userUid, toUpdate, req.AllOrNothing)
+	})
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+		custom_otel.SetEventMessage(fmt.Sprintf("%d connectors to update, %d failed", len(results), countFailedBulkItems(results))),
+	)))
+
+	resp, err := convertBulkResults(results)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	return resp, http.StatusOK, nil
+}
+
+// BulkDeleteUserConnectorResources deletes up to service.MaxBulkConnectorResources connectors in one transaction
+func (h *HTTPHandler) BulkDeleteUserConnectorResources(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "DeleteUserConnectorResources"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	req := &bulkDeleteRequest{}
+	if err := decodeHTTPBody(r, req); err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	if err := service.CheckBulkSize(len(req.Names)); err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	ns, _, err := h.service.GetRscNamespaceAndNameID(pathParams["parent"])
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	results := make([]*service.BulkItemResult, len(req.Names))
+	connIDs := make([]string, len(req.Names))
+	for idx, name := range req.Names {
+		itemNs, connID, err := h.service.GetRscNamespaceAndNameID(name)
+		if err == nil && itemNs.String() != ns.String() {
+			err = status.Errorf(codes.InvalidArgument, "[handler] %s is not under %s", name, pathParams["parent"])
+		}
+		if err != nil {
+			results[idx] = &service.BulkItemResult{Status: status.Convert(err)}
+			continue
+		}
+		connIDs[idx] = connID
+	}
+
+	results, err = runBulkItems(results, req.AllOrNothing, func(indices []int) ([]*service.BulkItemResult, error) {
+		toDelete := make([]string, len(indices))
+		for i, idx := range indices {
+			toDelete[i] = connIDs[idx]
+		}
+		return h.service.BulkDeleteUserConnectorResources(ctx, ns, userUid, toDelete, req.AllOrNothing)
+	})
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+		custom_otel.SetEventMessage(fmt.Sprintf("%d connectors to delete, %d failed", len(results), countFailedBulkItems(results))),
+	)))
+
+	resp, err := convertBulkResults(results)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	return resp, http.StatusOK, nil
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/instill-ai/connector-backend/pkg/service"
+
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+func TestConvertBulkResults(t *testing.T) {
+	results := []*service.BulkItemResult{
+		{
+			ConnectorResource: &connectorPB.ConnectorResource{
+				Id:    "openai",
+				State: connectorPB.ConnectorResource_STATE_CONNECTED,
+				Owner: &connectorPB.ConnectorResource_User{User: "users/admin"},
+			},
+			Status: status.New(codes.OK, ""),
+		},
+		{
+			Status: status.New(codes.NotFound, "not found"),
+		},
+	}
+
+	resp, err := convertBulkResults(results)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The marshaler of the gateway in cmd/main
+	marshaler := &runtime.JSONPb{
+		MarshalOptions: protojson.MarshalOptions{
+			UseProtoNames:   true,
+			EmitUnpopulated: true,
+		},
+		UnmarshalOptions: protojson.UnmarshalOptions{
+			DiscardUnknown: true,
+		},
+	}
+	b, err := marshaler.Marshal(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := struct {
+		Results []map[string]interface{} `json:"results"`
+	}{}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Results) != 2 {
+		t.Fatalf("got %d results, want 2: %s", len(got.Results), b)
+	}
+
+	connectorResource, ok := got.Results[0]["connector_resource"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("no connector resource: %s", b)
+	}
+	for field, want := range map[string]interface{}{
+		"id":    "openai",
+		"state": "STATE_CONNECTED",
+		"user":  "users/admin",
+	} {
+		if !reflect.DeepEqual(connectorResource[field], want) {
+			t.Errorf("got %s %v, want %v", field, connectorResource[field], want)
+		}
+	}
+	if _, ok := connectorResource["Owner"]; ok {
+		t.Errorf("the owner is marshaled with the Go field name: %s", b)
+	}
+	if got.Results[0]["code"] != "OK" {
+		t.Errorf("got code %v, want OK", got.Results[0]["code"])
+	}
+
+	want := map[string]interface{}{"code": "NOT_FOUND", "message": "not found"}
+	if !reflect.DeepEqual(got.Results[1], want) {
+		t.Errorf("got %v, want %v", got.Results[1], want)
+	}
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/x/sterr"
+
+	custom_otel "github.com/instill-ai/connector-backend/pkg/logger/otel"
+)
+
+// cacheResource is how long the execution results of a connector are cached as a Go duration string, e.g.
+// "1h", "0s" disables the cache
+type cacheResource struct {
+	TTL string `json:"ttl"`
+}
+
+func (h *HTTPHandler) GetUserConnectorCache(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "GetUserConnectorCache"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	ns, connID, err := h.service.GetRscNamespaceAndNameID(pathParams["name"])
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	ttl, err := h.service.GetUserConnectorCacheTTL(ctx, ns, userUid, connID)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+	)))
+
+	return &cacheResource{TTL: ttl.String()}, http.StatusOK, nil
+}
+
+func (h *HTTPHandler) UpdateUserConnectorCache(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "UpdateUserConnectorCache"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	req := &cacheResource{}
+	if err := decodeHTTPBody(r, req); err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	ttl, err := time.ParseDuration(req.TTL)
+	if err != nil {
+		st, _ := sterr.CreateErrorBadRequest(
+			"[handler] invalid cache ttl",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "ttl",
+					Description: err.Error(),
+				},
+			},
+		)
+		span.SetStatus(1, st.Err().Error())
+		return nil, 0, st.Err()
+	}
+
+	ns, connID, err := h.service.GetRscNamespaceAndNameID(pathParams["name"])
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	ttl, err = h.service.UpdateUserConnectorCacheTTL(ctx, ns, userUid, connID, ttl)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+	)))
+
+	return &cacheResource{TTL: ttl.String()}, http.StatusOK, nil
+}
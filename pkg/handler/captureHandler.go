@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/x/sterr"
+
+	custom_otel "github.com/instill-ai/connector-backend/pkg/logger/otel"
+)
+
+// debugCaptureResource tells whether the payloads of the connector executions are captured
+type debugCaptureResource struct {
+	Enabled bool `json:"enabled"`
+}
+
+// captureResource is the API representation of the captured payloads of an execution, the payloads are null
+// if they were over the size limit
+type captureResource struct {
+	Name         string          `json:"name"`
+	Inputs       json.RawMessage `json:"inputs"`
+	Outputs      json.RawMessage `json:"outputs"`
+	ErrorMessage string          `json:"error_message,omitempty"`
+	Truncated    bool            `json:"truncated"`
+	CreateTime   time.Time       `json:"create_time"`
+	ExpireTime   time.Time       `json:"expire_time"`
+}
+
+// rawJSON returns the stored JSON, null if there is none
+func rawJSON(b []byte) json.RawMessage {
+	if len(b) == 0 {
+		return json.RawMessage("null")
+	}
+	return json.RawMessage(b)
+}
+
+func (h *HTTPHandler) GetUserConnectorDebugCapture(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "GetUserConnectorDebugCapture"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	ns, connID, err := h.service.GetRscNamespaceAndNameID(pathParams["name"])
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	enabled, err := h.service.GetUserConnectorDebugCapture(ctx, ns, userUid, connID)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+	)))
+
+	return &debugCaptureResource{Enabled: enabled}, http.StatusOK, nil
+}
+
+// UpdateUserConnectorDebugCapture enables or disables the debug capture, the change is logged as an audit event
+func (h *HTTPHandler) UpdateUserConnectorDebugCapture(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "UpdateUserConnectorDebugCapture"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	req := &debugCaptureResource{}
+	if err := decodeHTTPBody(r, req); err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	ns, connID, err := h.service.GetRscNamespaceAndNameID(pathParams["name"])
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	enabled, err := h.service.UpdateUserConnectorDebugCapture(ctx, ns, userUid, connID, req.Enabled)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	message := fmt.Sprintf("debug capture of %s disabled", pathParams["name"])
+	if enabled {
+		message = fmt.Sprintf("debug capture of %s enabled, the execution payloads are stored", pathParams["name"])
+	}
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+		custom_otel.SetEventMessage(message),
+	)))
+
+	return &debugCaptureResource{Enabled: enabled}, http.StatusOK, nil
+}
+
+func (h *HTTPHandler) GetUserConnectorCapture(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "GetUserConnectorCapture"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	name := pathParams["name"]
+	connName := name[:strings.LastIndex(name, "/executions/")]
+
+	uid, err := uuid.FromString(name[strings.LastIndex(name, "/")+1:])
+	if err != nil {
+		st, _ := sterr.CreateErrorBadRequest(
+			"[handler] invalid execution name",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "name",
+					Description: err.Error(),
+				},
+			},
+		)
+		span.SetStatus(1, st.Err().Error())
+		return nil, 0, st.Err()
+	}
+
+	ns, connID, err := h.service.GetRscNamespaceAndNameID(connName)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	capture, err := h.service.GetUserConnectorCapture(ctx, ns, userUid, connID, uid)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	resp := &captureResource{
+		Name:         fmt.Sprintf("%s/capture", name),
+		Inputs:       rawJSON(capture.Inputs),
+		Outputs:      rawJSON(capture.Outputs),
+		ErrorMessage: capture.ErrorMessage,
+		Truncated:    capture.Truncated,
+		CreateTime:   capture.CreateTime,
+		ExpireTime:   capture.ExpireTime,
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+	)))
+
+	return map[string]interface{}{"capture": resp}, http.StatusOK, nil
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gofrs/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/x/sterr"
+
+	custom_otel "github.com/instill-ai/connector-backend/pkg/logger/otel"
+)
+
+// cloneRequest names the clone, Parent is the namespace of the clone, the namespace of the connector by default.
+// Configuration is merged into the configuration of the connector.
+type cloneRequest struct {
+	ID            string          `json:"id"`
+	Parent        string          `json:"parent"`
+	Description   *string         `json:"description"`
+	Configuration json.RawMessage `json:"configuration"`
+}
+
+// CloneUserConnectorResource copies a connector, including its credentials, under a new id
+func (h *HTTPHandler) CloneUserConnectorResource(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "CreateUserConnectorResourceClone"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	req := &cloneRequest{}
+	if err := decodeHTTPBody(r, req); err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	if err := checkConnectorResourceID(ctx, req.ID); err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	var override *structpb.Struct
+	if len(req.Configuration) > 0 && string(req.Configuration) != "null" {
+		override = &structpb.Struct{}
+		if err := protojson.Unmarshal(req.Configuration, override); err != nil {
+			st, _ := sterr.CreateErrorBadRequest(
+				"[handler] invalid request body",
+				[]*errdetails.BadRequest_FieldViolation{
+					{
+						Field:       "configuration",
+						Description: err.Error(),
+					},
+				},
+			)
+			span.SetStatus(1, st.Err().Error())
+			return nil, 0, st.Err()
+		}
+	}
+
+	ns, connID, err := h.service.GetRscNamespaceAndNameID(pathParams["name"])
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	targetNs := ns
+	if req.Parent != "" {
+		if targetNs, _, err = h.service.GetRscNamespaceAndNameID(req.Parent); err != nil {
+			span.SetStatus(1, err.Error())
+			return nil, 0, err
+		}
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	connectorResource, err := h.service.CloneUserConnectorResource(ctx, ns, userUid, connID, targetNs, req.ID, req.Description, override)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+		custom_otel.SetEventResource(connectorResource),
+		custom_otel.SetEventMessage(fmt.Sprintf("cloned from %s", pathParams["name"])),
+	)))
+
+	return map[string]interface{}{"connector_resource": connectorResource}, http.StatusCreated, nil
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gofrs/uuid"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/instill-ai/connector-backend/pkg/logger"
+
+	custom_otel "github.com/instill-ai/connector-backend/pkg/logger/otel"
+)
+
+// concurrencyResource is the number of executions of a connector that can run at once, 0 means unlimited
+type concurrencyResource struct {
+	MaxConcurrency int32 `json:"max_concurrency"`
+}
+
+func (h *HTTPHandler) GetUserConnectorMaxConcurrency(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "GetUserConnectorMaxConcurrency"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	ns, connID, err := h.service.GetRscNamespaceAndNameID(pathParams["name"])
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	maxConcurrency, err := h.service.GetUserConnectorMaxConcurrency(ctx, ns, userUid, connID)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+	)))
+
+	return &concurrencyResource{MaxConcurrency: maxConcurrency}, http.StatusOK, nil
+}
+
+func (h *HTTPHandler) UpdateUserConnectorMaxConcurrency(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "UpdateUserConnectorMaxConcurrency"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	req := &concurrencyResource{}
+	if err := decodeHTTPBody(r, req); err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	ns, connID, err := h.service.GetRscNamespaceAndNameID(pathParams["name"])
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	maxConcurrency, err := h.service.UpdateUserConnectorMaxConcurrency(ctx, ns, userUid, connID, req.MaxConcurrency)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+	)))
+
+	return &concurrencyResource{MaxConcurrency: maxConcurrency}, http.StatusOK, nil
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/connector-backend/pkg/utils"
+	"github.com/instill-ai/x/sterr"
+
+	custom_otel "github.com/instill-ai/connector-backend/pkg/logger/otel"
+	mgmtPB "github.com/instill-ai/protogen-go/core/mgmt/v1alpha"
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+	codepb "google.golang.org/genproto/googleapis/rpc/code"
+)
+
+// itemStatus is the status of one input of an execution
+type itemStatus struct {
+	Code    string `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+// executeEachResponse holds the outputs and statuses aligned with the inputs, the output of a failed input is null
+type executeEachResponse struct {
+	Outputs  []*structpb.Struct `json:"outputs"`
+	Statuses []*itemStatus      `json:"statuses"`
+}
+
+// ExecuteUserConnectorResourceEach executes every input on its own, a failing input does not fail the others
+func (h *HTTPHandler) ExecuteUserConnectorResourceEach(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	startTime := time.Now()
+	eventName := "ExecuteUserConnectorResourceEach"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	req := &connectorPB.ExecuteUserConnectorResourceRequest{}
+	if err := decodeHTTPProtoBody(r, req); err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	ns, connID, err := h.service.GetRscNamespaceAndNameID(pathParams["name"])
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	execution, err := h.service.CreateBatchExecution(ctx, ns, userUid, connID, req.GetTask())
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	if execution.ConnectorResource.Tombstone {
+		st, _ := sterr.CreateErrorPreconditionFailure(
+			"ExecuteConnector",
+			[]*errdetails.PreconditionFailure_Violation{
+				{
+					Type:        "STATE",
+					Subject:     fmt.Sprintf("id %s", connID),
+					Description: "the connector definition is deprecated, you can not use it anymore",
+				},
+			})
+		span.SetStatus(1, st.Err().Error())
+		return nil, 0, st.Err()
+	}
+
+	outputs, itemErrors, err := execution.ExecuteEach(ctx, logUUID, req.GetInputs())
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	resp := &executeEachResponse{
+		Outputs:  outputs,
+		Statuses: make([]*itemStatus, len(outputs)),
+	}
+	for i := range resp.Statuses {
+		resp.Statuses[i] = &itemStatus{Code: codepb.Code(codes.OK).String()}
+	}
+	for _, itemError := range itemErrors {
+		resp.Statuses[itemError.Index] = &itemStatus{
+			Code:    codepb.Code(itemError.Status.Code()).String(),
+			Message: itemError.Status.Message(),
+		}
+	}
+
+	dataPoint := utils.UsageMetricData{
+		OwnerUID:               userUid.String(),
+		ConnectorID:            execution.ConnectorResource.ID,
+		ConnectorUID:           execution.ConnectorResource.UID.String(),
+		ConnectorExecuteUID:    logUUID.String(),
+		ConnectorDefinitionUid: execution.ConnectorResource.ConnectorDefinitionUID.String(),
+		ExecuteTime:            startTime.Format(time.RFC3339Nano),
+		ComputeTimeDuration:    time.Since(startTime).Seconds(),
+		Status:                 mgmtPB.Status_STATUS_COMPLETED,
+		SucceededCount:         len(outputs) - len(itemErrors),
+		FailedCount:            len(itemErrors),
+		Task:                   req.GetTask(),
+		InputSize:              utils.PayloadSize(req.GetInputs()),
+		OutputSize:             utils.PayloadSize(outputs),
+	}
+	if len(itemErrors) > 0 {
+		dataPoint.Status = mgmtPB.Status_STATUS_ERRORED
+		dataPoint.ErrorMessage = itemErrors[0].Status.Message()
+	}
+	if err := h.service.WriteNewDataPoint(ctx, dataPoint, pipelineMetadataFromContext(ctx)); err != nil {
+		logger.Warn("usage and metric data write fail")
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+		custom_otel.SetEventMessage(fmt.Sprintf("%d of %d inputs failed", len(itemErrors), len(outputs))),
+	)))
+
+	return resp, http.StatusOK, nil
+}
@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"go.einride.tech/aip/filtering"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+
+	"github.com/instill-ai/connector-backend/internal/resource"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/x/sterr"
+
+	custom_otel "github.com/instill-ai/connector-backend/pkg/logger/otel"
+)
+
+// executionResource is the API representation of a recorded connector execution
+type executionResource struct {
+	Name         string          `json:"name"`
+	UID          string          `json:"uid"`
+	User         string          `json:"user"`
+	Task         string          `json:"task"`
+	Pipeline     json.RawMessage `json:"pipeline,omitempty"`
+	Status       string          `json:"status"`
+	Duration     float64         `json:"duration"`
+	ErrorMessage string          `json:"error_message,omitempty"`
+	InputSize    int64           `json:"input_size"`
+	OutputSize   int64           `json:"output_size"`
+	CreateTime   time.Time       `json:"create_time"`
+}
+
+type listExecutionsResponse struct {
+	Executions    []*executionResource `json:"executions"`
+	NextPageToken string               `json:"next_page_token"`
+	TotalSize     int32                `json:"total_size"`
+}
+
+// filterQuery is the AIP filter given in the filter query parameter
+type filterQuery string
+
+func (f filterQuery) GetFilter() string {
+	return string(f)
+}
+
+// executionFilterDeclarations are the fields an execution list can be filtered on, e.g.
+// `status = "errored" AND create_time > timestamp("2023-01-01T00:00:00Z")` or `pipeline.id = "my-pipeline"`
+func executionFilterDeclarations() (*filtering.Declarations, error) {
+	return filtering.NewDeclarations([]filtering.DeclarationOption{
+		filtering.DeclareStandardFunctions(),
+		filtering.DeclareIdent("task", filtering.TypeString),
+		filtering.DeclareIdent("status", filtering.TypeString),
+		filtering.DeclareIdent("duration", filtering.TypeFloat),
+		filtering.DeclareIdent("error_message", filtering.TypeString),
+		filtering.DeclareIdent("input_size", filtering.TypeInt),
+		filtering.DeclareIdent("output_size", filtering.TypeInt),
+		filtering.DeclareIdent("create_time", filtering.TypeTimestamp),
+		filtering.DeclareIdent("pipeline", filtering.TypeMap(filtering.TypeString, filtering.TypeString)),
+	}...)
+}
+
+func (h *HTTPHandler) convertExecution(connName string, execution *datamodel.ConnectorExecution) *executionResource {
+	// The acting user may not exist anymore
+	userPermalink := resource.UserUidToUserPermalink(execution.UserUID)
+	user, err := h.service.ConvertOwnerPermalinkToName(userPermalink)
+	if err != nil {
+		user = userPermalink
+	}
+	return &executionResource{
+		Name:         fmt.Sprintf("%s/executions/%s", connName, execution.UID),
+		UID:          execution.UID.String(),
+		User:         user,
+		Task:         execution.Task,
+		Pipeline:     json.RawMessage(execution.Pipeline),
+		Status:       string(execution.Status),
+		Duration:     execution.Duration,
+		ErrorMessage: execution.ErrorMessage,
+		InputSize:    execution.InputSize,
+		OutputSize:   execution.OutputSize,
+		CreateTime:   execution.CreateTime,
+	}
+}
+
+func (h *HTTPHandler) ListUserConnectorExecutions(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "ListUserConnectorExecutions"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	pageSize, pageToken, err := parsePageQuery(r)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	declarations, err := executionFilterDeclarations()
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	filter, err := filtering.ParseFilter(filterQuery(r.URL.Query().Get("filter")), declarations)
+	if err != nil {
+		st, _ := sterr.CreateErrorBadRequest(
+			"[handler] invalid filter",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "filter",
+					Description: err.Error(),
+				},
+			},
+		)
+		span.SetStatus(1, st.Err().Error())
+		return nil, 0, st.Err()
+	}
+
+	ns, connID, err := h.service.GetRscNamespaceAndNameID(pathParams["name"])
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	executions, totalSize, nextPageToken, err := h.service.ListUserConnectorExecutions(ctx, ns, userUid, connID, pageSize, pageToken, filter)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	resp := &listExecutionsResponse{
+		Executions:    []*executionResource{},
+		NextPageToken: nextPageToken,
+		TotalSize:     int32(totalSize),
+	}
+	for _, execution := range executions {
+		resp.Executions = append(resp.Executions, h.convertExecution(pathParams["name"], execution))
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+	)))
+
+	return resp, http.StatusOK, nil
+}
+
+func (h *HTTPHandler) GetUserConnectorExecution(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "GetUserConnectorExecution"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	name := pathParams["name"]
+	connName := name[:strings.LastIndex(name, "/executions/")]
+
+	uid, err := uuid.FromString(name[strings.LastIndex(name, "/")+1:])
+	if err != nil {
+		st, _ := sterr.CreateErrorBadRequest(
+			"[handler] invalid execution name",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "name",
+					Description: err.Error(),
+				},
+			},
+		)
+		span.SetStatus(1, st.Err().Error())
+		return nil, 0, st.Err()
+	}
+
+	ns, connID, err := h.service.GetRscNamespaceAndNameID(connName)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	execution, err := h.service.GetUserConnectorExecution(ctx, ns, userUid, connID, uid)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	resp := h.convertExecution(connName, execution)
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+		custom_otel.SetEventResource(resp),
+	)))
+
+	return map[string]interface{}{"execution": resp}, http.StatusOK, nil
+}
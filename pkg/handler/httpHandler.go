@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/instill-ai/connector-backend/pkg/constant"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/connector-backend/pkg/service"
+	"github.com/instill-ai/x/sterr"
+)
+
+// HTTPHandler serves the REST endpoints that have no counterpart in the connector protobuf API.
+// They are mounted on the gateway muxes so that they share their header matcher, marshaler and error
+// handler with the generated endpoints.
+type HTTPHandler struct {
+	service service.Service
+}
+
+// httpHandlerFunc handles a request whose context carries the incoming metadata like a gRPC handler does.
+// The returned body is marshaled with the gateway marshaler and written with the status code, a zero
+// status code means the handler has already written the response.
+type httpHandlerFunc func(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (body interface{}, code int, err error)
+
+// httpRoute binds an endpoint to its handler, the event name identifies it in the incoming metadata
+type httpRoute struct {
+	method    string
+	pattern   string
+	eventName string
+	handle    httpHandlerFunc
+}
+
+// NewHTTPHandler initiates a handler instance
+func NewHTTPHandler(ctx context.Context, s service.Service) *HTTPHandler {
+	return &HTTPHandler{
+		service: s,
+	}
+}
+
+// RegisterPublicRoutes mounts the public endpoints on the public gateway mux
+func (h *HTTPHandler) RegisterPublicRoutes(mux *runtime.ServeMux) error {
+	return h.register(mux, h.publicRoutes())
+}
+
+// RegisterPrivateRoutes mounts the private endpoints on the private gateway mux
+func (h *HTTPHandler) RegisterPrivateRoutes(mux *runtime.ServeMux) error {
+	return h.register(mux, h.privateRoutes())
+}
+
+func (h *HTTPHandler) register(mux *runtime.ServeMux, routes []httpRoute) error {
+	for _, route := range routes {
+		if err := mux.HandlePath(route.method, route.pattern, h.serve(mux, route)); err != nil {
+			return fmt.Errorf("register %s %s: %w", route.method, route.pattern, err)
+		}
+	}
+	return nil
+}
+
+func (h *HTTPHandler) publicRoutes() []httpRoute {
+	return []httpRoute{
+		{http.MethodPost, "/v1alpha/{parent=users/*}/secrets", "CreateUserSecret", h.CreateUserSecret},
+		{http.MethodGet, "/v1alpha/{parent=users/*}/secrets", "ListUserSecrets", h.ListUserSecrets},
+		{http.MethodGet, "/v1alpha/{name=users/*/secrets/*}", "GetUserSecret", h.GetUserSecret},
+		{http.MethodPatch, "/v1alpha/{name=users/*/secrets/*}", "UpdateUserSecret", h.UpdateUserSecret},
+		{http.MethodDelete, "/v1alpha/{name=users/*/secrets/*}", "DeleteUserSecret", h.DeleteUserSecret},
+		{http.MethodPost, "/v1alpha/{parent=users/*}/connector-resources:bulkCreate", "CreateUserConnectorResources", h.BulkCreateUserConnectorResources},
+		{http.MethodPost, "/v1alpha/{parent=users/*}/connector-resources:bulkUpdate", "UpdateUserConnectorResources", h.BulkUpdateUserConnectorResources},
+		{http.MethodPost, "/v1alpha/{parent=users/*}/connector-resources:bulkDelete", "DeleteUserConnectorResources", h.BulkDeleteUserConnectorResources},
+		{http.MethodGet, "/v1alpha/{parent=users/*}/connector-resources:export", "ExportUserConnectorResources", h.ExportUserConnectorResources},
+		{http.MethodGet, "/v1alpha/{name=users/*/connector-resources/*}/revisions", "ListUserConnectorRevisions", h.ListUserConnectorRevisions},
+		{http.MethodGet, "/v1alpha/{name=users/*/connector-resources/*/revisions/*}", "GetUserConnectorRevision", h.GetUserConnectorRevision},
+		{http.MethodGet, "/v1alpha/{name=users/*/connector-resources/*}/diffRevisions", "DiffUserConnectorRevisions", h.DiffUserConnectorRevisions},
+		{http.MethodPost, "/v1alpha/{name=users/*/connector-resources/*}/clone", "CloneUserConnectorResource", h.CloneUserConnectorResource},
+		{http.MethodPost, "/v1alpha/{name=users/*/connector-resources/*}/undelete", "UndeleteUserConnectorResource", h.UndeleteUserConnectorResource},
+		{http.MethodPost, "/v1alpha/{name=users/*/connector-resources/*}/rollback", "RollbackUserConnectorResource", h.RollbackUserConnectorResource},
+		{http.MethodGet, "/v1alpha/{name=users/*}/rateLimit", "GetNamespaceRateLimit", h.GetNamespaceRateLimit},
+		{http.MethodPut, "/v1alpha/{name=users/*}/rateLimit", "UpdateNamespaceRateLimit", h.UpdateNamespaceRateLimit},
+		{http.MethodDelete, "/v1alpha/{name=users/*}/rateLimit", "DeleteNamespaceRateLimit", h.DeleteNamespaceRateLimit},
+		{http.MethodGet, "/v1alpha/{name=users/*/connector-resources/*}/rateLimit", "GetUserConnectorRateLimit", h.GetUserConnectorRateLimit},
+		{http.MethodPut, "/v1alpha/{name=users/*/connector-resources/*}/rateLimit", "UpdateUserConnectorRateLimit", h.UpdateUserConnectorRateLimit},
+		{http.MethodDelete, "/v1alpha/{name=users/*/connector-resources/*}/rateLimit", "DeleteUserConnectorRateLimit", h.DeleteUserConnectorRateLimit},
+		{http.MethodGet, "/v1alpha/{name=users/*/connector-resources/*}/cache", "GetUserConnectorCache", h.GetUserConnectorCache},
+		{http.MethodPut, "/v1alpha/{name=users/*/connector-resources/*}/cache", "UpdateUserConnectorCache", h.UpdateUserConnectorCache},
+		{http.MethodGet, "/v1alpha/{name=users/*/connector-resources/*}/concurrency", "GetUserConnectorMaxConcurrency", h.GetUserConnectorMaxConcurrency},
+		{http.MethodPut, "/v1alpha/{name=users/*/connector-resources/*}/concurrency", "UpdateUserConnectorMaxConcurrency", h.UpdateUserConnectorMaxConcurrency},
+		{http.MethodGet, "/v1alpha/{name=users/*/connector-resources/*}/retryPolicy", "GetUserConnectorRetryPolicy", h.GetUserConnectorRetryPolicy},
+		{http.MethodPut, "/v1alpha/{name=users/*/connector-resources/*}/retryPolicy", "UpdateUserConnectorRetryPolicy", h.UpdateUserConnectorRetryPolicy},
+		{http.MethodPost, "/v1alpha/{name=users/*/connector-resources/*}/executeAsync", "ExecuteUserConnectorResourceAsync", h.ExecuteUserConnectorResourceAsync},
+		{http.MethodPost, "/v1alpha/{name=users/*/connector-resources/*}/executeEach", "ExecuteUserConnectorResourceEach", h.ExecuteUserConnectorResourceEach},
+		{http.MethodGet, "/v1alpha/{name=users/*/connector-resources/*}/executions", "ListUserConnectorExecutions", h.ListUserConnectorExecutions},
+		{http.MethodGet, "/v1alpha/{name=users/*/connector-resources/*/executions/*}", "GetUserConnectorExecution", h.GetUserConnectorExecution},
+		{http.MethodGet, "/v1alpha/{name=users/*/connector-resources/*/executions/*}/capture", "GetUserConnectorCapture", h.GetUserConnectorCapture},
+		{http.MethodGet, "/v1alpha/{name=users/*/connector-resources/*}/debugCapture", "GetUserConnectorDebugCapture", h.GetUserConnectorDebugCapture},
+		{http.MethodPut, "/v1alpha/{name=users/*/connector-resources/*}/debugCapture", "UpdateUserConnectorDebugCapture", h.UpdateUserConnectorDebugCapture},
+		{http.MethodGet, "/v1alpha/{name=users/*/connector-resources/*}/labels", "GetUserConnectorLabels", h.GetUserConnectorLabels},
+		{http.MethodPatch, "/v1alpha/{name=users/*/connector-resources/*}/labels", "UpdateUserConnectorLabels", h.UpdateUserConnectorLabels},
+		{http.MethodPost, "/v1alpha/{name=users/*/connector-resources/*/executions/*}/replay", "ReplayUserConnectorExecution", h.ReplayUserConnectorExecution},
+		{http.MethodPost, "/v1alpha/{name=users/*/connector-resources/*}/replayFailed", "ReplayUserConnectorFailedExecutions", h.ReplayUserConnectorFailedExecutions},
+		{http.MethodGet, "/v1alpha/operations", "ListOperations", h.ListOperations},
+		{http.MethodGet, "/v1alpha/{name=operations/*}", "GetOperation", h.GetOperation},
+		{http.MethodPost, "/v1alpha/{name=operations/*}/cancel", "CancelOperation", h.CancelOperation},
+		{http.MethodGet, "/v1alpha/{name=users/*/connector-resources/*}/roleBindings", "ListUserConnectorRoleBindings", h.ListUserConnectorRoleBindings},
+		{http.MethodPost, "/v1alpha/{name=users/*/connector-resources/*}/roleBindings", "BindUserConnectorRole", h.BindUserConnectorRole},
+		{http.MethodDelete, "/v1alpha/{name=users/*/connector-resources/*/roleBindings/*/*}", "UnbindUserConnectorRole", h.UnbindUserConnectorRole},
+	}
+}
+
+func (h *HTTPHandler) privateRoutes() []httpRoute {
+	return []httpRoute{
+		{http.MethodGet, "/v1alpha/admin/connector-resources/{uid}/checkPermission", "CheckConnectorPermissionAdmin", h.CheckConnectorPermissionAdmin},
+		{http.MethodGet, "/v1alpha/admin/connector-definitions/{uid}/rateLimit", "GetConnectorDefinitionRateLimitAdmin", h.GetConnectorDefinitionRateLimitAdmin},
+		{http.MethodPut, "/v1alpha/admin/connector-definitions/{uid}/rateLimit", "UpdateConnectorDefinitionRateLimitAdmin", h.UpdateConnectorDefinitionRateLimitAdmin},
+		{http.MethodDelete, "/v1alpha/admin/connector-definitions/{uid}/rateLimit", "DeleteConnectorDefinitionRateLimitAdmin", h.DeleteConnectorDefinitionRateLimitAdmin},
+	}
+}
+
+func (h *HTTPHandler) serve(mux *runtime.ServeMux, route httpRoute) runtime.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+
+		_, outbound := runtime.MarshalerForRequest(mux, r)
+
+		ctx := runtime.NewServerMetadataContext(r.Context(), runtime.ServerMetadata{
+			HeaderMD:  metadata.MD{},
+			TrailerMD: metadata.MD{},
+		})
+		annotatedCtx, err := runtime.AnnotateIncomingContext(ctx, mux, r, route.eventName, runtime.WithHTTPPathPattern(route.pattern))
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, err)
+			return
+		}
+		ctx = annotatedCtx
+
+		r.Body = http.MaxBytesReader(w, r.Body, constant.MaxPayloadSize)
+
+		body, code, err := route.handle(ctx, w, r, pathParams)
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, err)
+			return
+		}
+		if code == 0 {
+			return
+		}
+
+		if body == nil {
+			w.WriteHeader(code)
+			return
+		}
+
+		buf, err := outbound.Marshal(body)
+		if err != nil {
+			runtime.HTTPError(ctx, mux, outbound, w, r, err)
+			return
+		}
+		w.Header().Set("Content-Type", outbound.ContentType(body))
+		w.WriteHeader(code)
+		if _, err := w.Write(buf); err != nil {
+			logger, _ := logger.GetZapLogger(ctx)
+			logger.Error(fmt.Sprintf("Failed to write response: %v", err))
+		}
+	}
+}
+
+// decodeHTTPBody unmarshals the JSON request body
+func decodeHTTPBody(r *http.Request, req interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		st, _ := sterr.CreateErrorBadRequest(
+			"[handler] invalid request body",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "body",
+					Description: err.Error(),
+				},
+			},
+		)
+		return st.Err()
+	}
+	return nil
+}
+
+// decodeHTTPProtoBody unmarshals the JSON request body into a protobuf message
+func decodeHTTPProtoBody(r *http.Request, req proto.Message) error {
+	b, err := io.ReadAll(r.Body)
+	if err == nil {
+		err = protojson.Unmarshal(b, req)
+	}
+	if err != nil {
+		st, _ := sterr.CreateErrorBadRequest(
+			"[handler] invalid request body",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "body",
+					Description: err.Error(),
+				},
+			},
+		)
+		return st.Err()
+	}
+	return nil
+}
+
+// parsePageQuery returns the page_size and page_token query parameters
+func parsePageQuery(r *http.Request) (int64, string, error) {
+	var pageSize int64
+	if v := r.URL.Query().Get("page_size"); v != "" {
+		size, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || size < 0 {
+			st, _ := sterr.CreateErrorBadRequest(
+				"[handler] invalid query parameter",
+				[]*errdetails.BadRequest_FieldViolation{
+					{
+						Field:       "page_size",
+						Description: "page_size must be a non-negative integer",
+					},
+				},
+			)
+			return 0, "", st.Err()
+		}
+		pageSize = size
+	}
+	return pageSize, r.URL.Query().Get("page_token"), nil
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/instill-ai/connector-backend/internal/resource"
+	"github.com/instill-ai/connector-backend/pkg/constant"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/connector-backend/pkg/service"
+)
+
+// idempotent processes the request once per idempotency key of the namespace of {name}, the requests without an
+// idempotency key are always processed. The replays get the response of the first request, which is only
+// stored if it has succeeded so that a failed request can be retried with the same key.
+func (h *PublicHandler) idempotent(ctx context.Context, name string, method string, req proto.Message, resp proto.Message, process func() error) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	key := resource.GetRequestSingleHeader(ctx, constant.HeaderIdempotencyKey)
+	if key == "" {
+		return process()
+	}
+
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return err
+	}
+	requestHash := sha256.Sum256(b)
+
+	splits := strings.SplitN(name, "/", 3)
+	if len(splits) < 2 {
+		return fmt.Errorf("namespace error")
+	}
+
+	idempotentReq := &service.IdempotentRequest{
+		Namespace:   strings.Join(splits[:2], "/"),
+		Key:         key,
+		Method:      method,
+		Caller:      resource.GetRequestSingleHeader(ctx, constant.HeaderUserUIDKey),
+		RequestHash: hex.EncodeToString(requestHash[:]),
+	}
+
+	stored, err := h.service.BeginIdempotentRequest(ctx, idempotentReq)
+	if err != nil {
+		return err
+	}
+	if stored != nil {
+		if err := grpc.SetHeader(ctx, metadata.Pairs("x-idempotent-replay", "true")); err != nil {
+			logger.Error(err.Error())
+		}
+		return protojson.Unmarshal(stored, resp)
+	}
+
+	if err := process(); err != nil {
+		if err := h.service.AbandonIdempotentRequest(ctx, idempotentReq); err != nil {
+			logger.Error(err.Error())
+		}
+		return err
+	}
+
+	response, err := protojson.Marshal(resp)
+	if err != nil {
+		return err
+	}
+	if err := h.service.CompleteIdempotentRequest(ctx, idempotentReq, response); err != nil {
+		logger.Error(err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/instill-ai/connector-backend/pkg/logger"
+
+	custom_otel "github.com/instill-ai/connector-backend/pkg/logger/otel"
+)
+
+// labelsResource is the API representation of the labels of a connector
+type labelsResource struct {
+	Labels map[string]string `json:"labels"`
+}
+
+// updateLabelsRequest updates the labels of a connector, UpdateMask is a comma-separated field mask such as
+// `labels.env,labels.team`. The labels missing from the mask are left untouched, an empty mask replaces them all.
+type updateLabelsRequest struct {
+	Labels     map[string]string `json:"labels"`
+	UpdateMask string            `json:"update_mask"`
+}
+
+func (h *HTTPHandler) GetUserConnectorLabels(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "GetUserConnectorLabels"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	ns, connID, err := h.service.GetRscNamespaceAndNameID(pathParams["name"])
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	labels, err := h.service.GetUserConnectorLabels(ctx, ns, userUid, connID)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+	)))
+
+	return &labelsResource{Labels: labels}, http.StatusOK, nil
+}
+
+// UpdateUserConnectorLabels sets and removes the labels of the connector following the update mask
+func (h *HTTPHandler) UpdateUserConnectorLabels(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "UpdateUserConnectorLabels"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	req := &updateLabelsRequest{}
+	if err := decodeHTTPBody(r, req); err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	paths := []string{}
+	for _, path := range strings.Split(req.UpdateMask, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			paths = append(paths, path)
+		}
+	}
+
+	ns, connID, err := h.service.GetRscNamespaceAndNameID(pathParams["name"])
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	labels, err := h.service.UpdateUserConnectorLabels(ctx, ns, userUid, connID, req.Labels, paths)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+		custom_otel.SetEventMessage(fmt.Sprintf("labels of %s updated", pathParams["name"])),
+	)))
+
+	return &labelsResource{Labels: labels}, http.StatusOK, nil
+}
@@ -0,0 +1,321 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/longrunning/autogen/longrunningpb"
+	"github.com/gofrs/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/x/sterr"
+
+	custom_otel "github.com/instill-ai/connector-backend/pkg/logger/otel"
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+	statuspb "google.golang.org/genproto/googleapis/rpc/status"
+)
+
+// operationNamePrefix prefixes the operation uid in the operation name
+const operationNamePrefix = "operations/"
+
+// convertOperation returns the google.longrunning.Operation representation of an asynchronous execution, the
+// response of a succeeded operation is an ExecuteUserConnectorResourceResponse
+func convertOperation(operation *datamodel.ConnectorOperation) (*longrunningpb.Operation, error) {
+
+	fields := map[string]interface{}{
+		"connector_resource": fmt.Sprintf("connector-resources/%s", operation.ConnectorUID),
+		"task":               operation.Task,
+		"state":              string(operation.State),
+		"create_time":        operation.CreateTime.Format(time.RFC3339Nano),
+	}
+	if operation.StartTime.Valid {
+		fields["start_time"] = operation.StartTime.Time.Format(time.RFC3339Nano)
+	}
+	if operation.EndTime.Valid {
+		fields["end_time"] = operation.EndTime.Time.Format(time.RFC3339Nano)
+	}
+	metadata, err := structpb.NewStruct(fields)
+	if err != nil {
+		return nil, err
+	}
+	anyMetadata, err := anypb.New(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	lro := &longrunningpb.Operation{
+		Name:     operationNamePrefix + operation.UID.String(),
+		Metadata: anyMetadata,
+		Done:     operation.State.IsDone(),
+	}
+
+	switch operation.State {
+	case datamodel.OperationStateSucceeded:
+		outputs := &structpb.ListValue{}
+		if err := protojson.Unmarshal(operation.Outputs, outputs); err != nil {
+			return nil, err
+		}
+		resp := &connectorPB.ExecuteUserConnectorResourceResponse{}
+		for _, output := range outputs.GetValues() {
+			resp.Outputs = append(resp.Outputs, output.GetStructValue())
+		}
+		anyResp, err := anypb.New(resp)
+		if err != nil {
+			return nil, err
+		}
+		lro.Result = &longrunningpb.Operation_Response{Response: anyResp}
+	case datamodel.OperationStateFailed, datamodel.OperationStateCancelled:
+		st := &statuspb.Status{}
+		if err := protojson.Unmarshal(operation.Error, st); err != nil {
+			return nil, err
+		}
+		lro.Result = &longrunningpb.Operation_Error{Error: st}
+	}
+
+	return lro, nil
+}
+
+// parseOperationName returns the uid of an operation name
+func parseOperationName(name string) (uuid.UUID, error) {
+	uid, err := uuid.FromString(strings.TrimPrefix(name, operationNamePrefix))
+	if err != nil {
+		st, _ := sterr.CreateErrorBadRequest(
+			"[handler] invalid operation name",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "name",
+					Description: err.Error(),
+				},
+			},
+		)
+		return uuid.Nil, st.Err()
+	}
+	return uid, nil
+}
+
+func (h *HTTPHandler) ExecuteUserConnectorResourceAsync(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "ExecuteUserConnectorResourceAsync"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	req := &connectorPB.ExecuteUserConnectorResourceRequest{}
+	if err := decodeHTTPProtoBody(r, req); err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	ns, connID, err := h.service.GetRscNamespaceAndNameID(pathParams["name"])
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	connectorResource, err := h.service.GetUserConnectorResourceByID(ctx, ns, userUid, connID, connectorPB.View_VIEW_BASIC, true)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	if connectorResource.Tombstone {
+		st, _ := sterr.CreateErrorPreconditionFailure(
+			"ExecuteConnector",
+			[]*errdetails.PreconditionFailure_Violation{
+				{
+					Type:        "STATE",
+					Subject:     fmt.Sprintf("id %s", connID),
+					Description: "the connector definition is deprecated, you can not use it anymore",
+				},
+			})
+		span.SetStatus(1, st.Err().Error())
+		return nil, 0, st.Err()
+	}
+
+	operation, err := h.service.ExecuteAsync(ctx, ns, userUid, connID, req.GetTask(), req.GetInputs(), pipelineMetadataFromContext(ctx))
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	resp, err := convertOperation(operation)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+		custom_otel.SetEventResource(connectorResource),
+		custom_otel.SetEventMessage(fmt.Sprintf("%s queued", resp.GetName())),
+	)))
+
+	return resp, http.StatusOK, nil
+}
+
+func (h *HTTPHandler) ListOperations(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "ListOperations"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	pageSize, pageToken, err := parsePageQuery(r)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	operations, _, nextPageToken, err := h.service.ListUserConnectorOperations(ctx, userUid, pageSize, pageToken)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	resp := &longrunningpb.ListOperationsResponse{
+		Operations:    []*longrunningpb.Operation{},
+		NextPageToken: nextPageToken,
+	}
+	for _, operation := range operations {
+		lro, err := convertOperation(operation)
+		if err != nil {
+			span.SetStatus(1, err.Error())
+			return nil, 0, err
+		}
+		resp.Operations = append(resp.Operations, lro)
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+	)))
+
+	return resp, http.StatusOK, nil
+}
+
+func (h *HTTPHandler) GetOperation(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "GetOperation"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	uid, err := parseOperationName(pathParams["name"])
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	operation, err := h.service.GetUserConnectorOperation(ctx, userUid, uid)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	resp, err := convertOperation(operation)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+	)))
+
+	return resp, http.StatusOK, nil
+}
+
+func (h *HTTPHandler) CancelOperation(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "CancelOperation"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	uid, err := parseOperationName(pathParams["name"])
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	operation, err := h.service.CancelUserConnectorOperation(ctx, userUid, uid)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	resp, err := convertOperation(operation)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+		custom_otel.SetEventMessage(fmt.Sprintf("%s cancelled", resp.GetName())),
+	)))
+
+	return resp, http.StatusOK, nil
+}
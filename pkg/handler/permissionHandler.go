@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/instill-ai/connector-backend/pkg/service"
+)
+
+// CheckConnectorPermissionAdminMethod is the full name of the permission check, to be invoked by the other
+// backends on the private gRPC server
+const CheckConnectorPermissionAdminMethod = "/vdp.connector.v1alpha.ConnectorPermissionPrivateService/CheckConnectorPermissionAdmin"
+
+// ConnectorPermissionPrivateServiceServer is the server API of the permission check, its messages are
+// google.protobuf.Struct until the connector protobuf API defines them
+type ConnectorPermissionPrivateServiceServer interface {
+	CheckConnectorPermissionAdmin(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error)
+}
+
+// ConnectorPermissionPrivateServiceDesc describes the vdp.connector.v1alpha.ConnectorPermissionPrivateService
+// gRPC service
+var ConnectorPermissionPrivateServiceDesc = grpc.ServiceDesc{
+	ServiceName: "vdp.connector.v1alpha.ConnectorPermissionPrivateService",
+	HandlerType: (*ConnectorPermissionPrivateServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CheckConnectorPermissionAdmin",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				req := &structpb.Struct{}
+				if err := dec(req); err != nil {
+					return nil, err
+				}
+				if interceptor == nil {
+					return srv.(ConnectorPermissionPrivateServiceServer).CheckConnectorPermissionAdmin(ctx, req)
+				}
+				info := &grpc.UnaryServerInfo{
+					Server:     srv,
+					FullMethod: CheckConnectorPermissionAdminMethod,
+				}
+				return interceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+					return srv.(ConnectorPermissionPrivateServiceServer).CheckConnectorPermissionAdmin(ctx, req.(*structpb.Struct))
+				})
+			},
+		},
+	},
+	Streams: []grpc.StreamDesc{},
+}
+
+// PermissionHandler handles the permission check of the other backends
+type PermissionHandler struct {
+	service service.Service
+}
+
+// NewPermissionHandler initiates a handler instance
+func NewPermissionHandler(ctx context.Context, s service.Service) ConnectorPermissionPrivateServiceServer {
+	return &PermissionHandler{
+		service: s,
+	}
+}
+
+// CheckConnectorPermissionAdmin reports the role of a user on a connector resource and whether it includes the
+// given role:
+//
+//	{"uid": "{connector uid}", "user": "users/{uid}", "role": "executor"} -> {"allowed": true, "role": "editor"}
+func (h *PermissionHandler) CheckConnectorPermissionAdmin(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+
+	connUID, userUid, role, err := parseCheckPermissionRequest(
+		req.GetFields()["uid"].GetStringValue(),
+		req.GetFields()["user"].GetStringValue(),
+		req.GetFields()["role"].GetStringValue(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	userRole, allowed, err := h.service.CheckConnectorPermissionAdmin(ctx, connUID, userUid, role)
+	if err != nil {
+		return nil, err
+	}
+
+	return &structpb.Struct{
+		Fields: map[string]*structpb.Value{
+			"allowed": structpb.NewBoolValue(allowed),
+			"role":    structpb.NewStringValue(string(userRole)),
+		},
+	}, nil
+}
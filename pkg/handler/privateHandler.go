@@ -37,11 +37,7 @@ func (h *PrivateHandler) ListConnectorResourcesAdmin(ctx context.Context, req *c
 	pageSize = int64(req.GetPageSize())
 	pageToken = req.GetPageToken()
 
-	var connType connectorPB.ConnectorType
-	declarations, err := filtering.NewDeclarations([]filtering.DeclarationOption{
-		filtering.DeclareStandardFunctions(),
-		filtering.DeclareEnumIdent("connector_type", connType.Type()),
-	}...)
+	declarations, err := connectorResourceFilterDeclarations()
 	if err != nil {
 		return nil, err
 	}
@@ -22,6 +22,8 @@ import (
 	proto "google.golang.org/protobuf/proto"
 
 	"github.com/instill-ai/connector-backend/internal/resource"
+	"github.com/instill-ai/connector-backend/pkg/constant"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
 	"github.com/instill-ai/connector-backend/pkg/logger"
 	"github.com/instill-ai/connector-backend/pkg/service"
 	"github.com/instill-ai/connector-backend/pkg/utils"
@@ -151,11 +153,7 @@ func (h *PublicHandler) ListConnectorResources(ctx context.Context, req *connect
 	pageSize = int64(req.GetPageSize())
 	pageToken = req.GetPageToken()
 
-	var connType connectorPB.ConnectorType
-	declarations, err := filtering.NewDeclarations([]filtering.DeclarationOption{
-		filtering.DeclareStandardFunctions(),
-		filtering.DeclareEnumIdent("connector_type", connType.Type()),
-	}...)
+	declarations, err := connectorResourceFilterDeclarations()
 	if err != nil {
 		span.SetStatus(1, err.Error())
 		return resp, err
@@ -255,52 +253,26 @@ func (h *PublicHandler) LookUpConnectorResource(ctx context.Context, req *connec
 	return resp, nil
 }
 
-func (h *PublicHandler) CreateUserConnectorResource(ctx context.Context, req *connectorPB.CreateUserConnectorResourceRequest) (resp *connectorPB.CreateUserConnectorResourceResponse, err error) {
-
-	eventName := "CreateUserConnectorResource"
-
-	ctx, span := tracer.Start(ctx, eventName,
-		trace.WithSpanKind(trace.SpanKindServer))
-	defer span.End()
+func (h *PublicHandler) CreateUserConnectorResource(ctx context.Context, req *connectorPB.CreateUserConnectorResourceRequest) (*connectorPB.CreateUserConnectorResourceResponse, error) {
+	resp := &connectorPB.CreateUserConnectorResourceResponse{}
+	err := h.idempotent(ctx, req.GetParent(), "CreateUserConnectorResource", req, resp, func() error {
+		created, err := h.createUserConnectorResource(ctx, req)
+		if err != nil {
+			return err
+		}
+		proto.Merge(resp, created)
+		return nil
+	})
+	return resp, err
+}
 
-	logUUID, _ := uuid.NewV4()
+// checkConnectorResourceToCreate checks the fields of a connector resource to create
+func checkConnectorResourceToCreate(ctx context.Context, connectorResource *connectorPB.ConnectorResource) error {
 
 	logger, _ := logger.GetZapLogger(ctx)
 
-	var connID string
-
-	resp = &connectorPB.CreateUserConnectorResourceResponse{}
-
-	ns, _, err := h.service.GetRscNamespaceAndNameID(req.Parent)
-	if err != nil {
-		span.SetStatus(1, err.Error())
-		return resp, err
-	}
-	userId, userUid, err := h.service.GetUser(ctx)
-	if err != nil {
-		span.SetStatus(1, err.Error())
-		return resp, err
-	}
-
-	// TODO: ACL
-	if ns.String() != resource.UserUidToUserPermalink(userUid) {
-		st, err := sterr.CreateErrorBadRequest(
-			"[handler] create connector error",
-			[]*errdetails.BadRequest_FieldViolation{
-				{
-					Description: "can not create in other user's namespace",
-				},
-			},
-		)
-		if err != nil {
-			logger.Error(err.Error())
-		}
-		span.SetStatus(1, st.Err().Error())
-		return resp, st.Err()
-	}
-
 	// Set all OUTPUT_ONLY fields to zero value on the requested payload
-	if err := checkfield.CheckCreateOutputOnlyFields(req.GetConnectorResource(), outputOnlyFields); err != nil {
+	if err := checkfield.CheckCreateOutputOnlyFields(connectorResource, outputOnlyFields); err != nil {
 		st, err := sterr.CreateErrorBadRequest(
 			"[handler] create connector error",
 			[]*errdetails.BadRequest_FieldViolation{
@@ -313,12 +285,11 @@ func (h *PublicHandler) CreateUserConnectorResource(ctx context.Context, req *co
 		if err != nil {
 			logger.Error(err.Error())
 		}
-		span.SetStatus(1, st.Err().Error())
-		return resp, st.Err()
+		return st.Err()
 	}
 
 	// Return error if REQUIRED fields are not provided in the requested payload
-	if err := checkfield.CheckRequiredFields(req.GetConnectorResource(), append(createRequiredFields, immutableFields...)); err != nil {
+	if err := checkfield.CheckRequiredFields(connectorResource, append(createRequiredFields, immutableFields...)); err != nil {
 		st, err := sterr.CreateErrorBadRequest(
 			"[handler] create connector error",
 			[]*errdetails.BadRequest_FieldViolation{
@@ -331,11 +302,17 @@ func (h *PublicHandler) CreateUserConnectorResource(ctx context.Context, req *co
 		if err != nil {
 			logger.Error(err.Error())
 		}
-		span.SetStatus(1, st.Err().Error())
-		return resp, st.Err()
+		return st.Err()
 	}
 
-	connID = req.GetConnectorResource().GetId()
+	return checkConnectorResourceID(ctx, connectorResource.GetId())
+}
+
+// checkConnectorResourceID checks the id of a connector resource to create
+func checkConnectorResourceID(ctx context.Context, connID string) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
 	if len(connID) > 8 && connID[:8] == "instill-" {
 		st, err := sterr.CreateErrorBadRequest(
 			"[handler] create connector error",
@@ -349,8 +326,7 @@ func (h *PublicHandler) CreateUserConnectorResource(ctx context.Context, req *co
 		if err != nil {
 			logger.Error(err.Error())
 		}
-		span.SetStatus(1, st.Err().Error())
-		return resp, st.Err()
+		return st.Err()
 	}
 
 	// Return error if resource ID does not follow RFC-1034
@@ -367,10 +343,62 @@ func (h *PublicHandler) CreateUserConnectorResource(ctx context.Context, req *co
 		if err != nil {
 			logger.Error(err.Error())
 		}
+		return st.Err()
+	}
+
+	return nil
+}
+
+func (h *PublicHandler) createUserConnectorResource(ctx context.Context, req *connectorPB.CreateUserConnectorResourceRequest) (resp *connectorPB.CreateUserConnectorResourceResponse, err error) {
+
+	eventName := "CreateUserConnectorResource"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	var connID string
+
+	resp = &connectorPB.CreateUserConnectorResourceResponse{}
+
+	ns, _, err := h.service.GetRscNamespaceAndNameID(req.Parent)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+	userId, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+
+	// Roles are granted per connector, only the owner of a namespace can create connectors in it
+	if ns.String() != resource.UserUidToUserPermalink(userUid) {
+		st, err := sterr.CreateErrorBadRequest(
+			"[handler] create connector error",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Description: "can not create in other user's namespace",
+				},
+			},
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
 		span.SetStatus(1, st.Err().Error())
 		return resp, st.Err()
 	}
 
+	if err := checkConnectorResourceToCreate(ctx, req.GetConnectorResource()); err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+	connID = req.GetConnectorResource().GetId()
+
 	req.ConnectorResource.Owner = &connectorPB.ConnectorResource_User{User: fmt.Sprintf("users/%s", userId)}
 
 	connectorResource, err := h.service.CreateUserConnectorResource(ctx, ns, userUid, req.ConnectorResource)
@@ -379,6 +407,12 @@ func (h *PublicHandler) CreateUserConnectorResource(ctx context.Context, req *co
 		return resp, err
 	}
 
+	// The created connector is returned with the full view
+	if err := h.setDefaultedFieldsHeader(ctx, ns, userUid, connID); err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+
 	logger.Info(string(custom_otel.NewLogMessage(
 		span,
 		logUUID.String(),
@@ -419,11 +453,7 @@ func (h *PublicHandler) ListUserConnectorResources(ctx context.Context, req *con
 	pageSize = int64(req.GetPageSize())
 	pageToken = req.GetPageToken()
 
-	var connType connectorPB.ConnectorType
-	declarations, err := filtering.NewDeclarations([]filtering.DeclarationOption{
-		filtering.DeclareStandardFunctions(),
-		filtering.DeclareEnumIdent("connector_type", connType.Type()),
-	}...)
+	declarations, err := connectorResourceFilterDeclarations()
 	if err != nil {
 		span.SetStatus(1, err.Error())
 		return resp, err
@@ -466,6 +496,19 @@ func (h *PublicHandler) ListUserConnectorResources(ctx context.Context, req *con
 
 }
 
+// setDefaultedFieldsHeader sets the JSON pointers of the configuration fields filled from the definition defaults
+// in the x-defaulted-fields header, comma separated, for the full view of a connector
+func (h *PublicHandler) setDefaultedFieldsHeader(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, connID string) error {
+	defaultedFields, err := h.service.GetUserConnectorDefaultedFields(ctx, ns, userUid, connID)
+	if err != nil {
+		return err
+	}
+	if len(defaultedFields) == 0 {
+		return nil
+	}
+	return grpc.SetHeader(ctx, metadata.Pairs("x-defaulted-fields", strings.Join(defaultedFields, ",")))
+}
+
 func (h *PublicHandler) GetUserConnectorResource(ctx context.Context, req *connectorPB.GetUserConnectorResourceRequest) (resp *connectorPB.GetUserConnectorResourceResponse, err error) {
 	eventName := "GetUserConnectorResource"
 
@@ -496,6 +539,13 @@ func (h *PublicHandler) GetUserConnectorResource(ctx context.Context, req *conne
 		return resp, err
 	}
 
+	if parseView(req.GetView()) == connectorPB.View_VIEW_FULL {
+		if err := h.setDefaultedFieldsHeader(ctx, ns, userUid, connID); err != nil {
+			span.SetStatus(1, err.Error())
+			return resp, err
+		}
+	}
+
 	resp.ConnectorResource = connectorResource
 
 	logger.Info(string(custom_otel.NewLogMessage(
@@ -509,33 +559,14 @@ func (h *PublicHandler) GetUserConnectorResource(ctx context.Context, req *conne
 	return resp, nil
 }
 
-func (h *PublicHandler) UpdateUserConnectorResource(ctx context.Context, req *connectorPB.UpdateUserConnectorResourceRequest) (resp *connectorPB.UpdateUserConnectorResourceResponse, err error) {
-
-	eventName := "UpdateUserConnectorResource"
-
-	ctx, span := tracer.Start(ctx, eventName,
-		trace.WithSpanKind(trace.SpanKindServer))
-	defer span.End()
-
-	logUUID, _ := uuid.NewV4()
+// prepareConnectorResourceUpdate merges the fields of the update mask into the existing connector resource, it
+// returns nil if the mask is empty
+func prepareConnectorResourceUpdate(ctx context.Context, s service.Service, ns resource.Namespace, userUid uuid.UUID, connID string, req *connectorPB.UpdateUserConnectorResourceRequest) (*connectorPB.ConnectorResource, error) {
 
 	logger, _ := logger.GetZapLogger(ctx)
 
 	var mask fieldmask_utils.Mask
 
-	ns, connID, err := h.service.GetRscNamespaceAndNameID(req.ConnectorResource.Name)
-	if err != nil {
-		span.SetStatus(1, err.Error())
-		return resp, err
-	}
-	_, userUid, err := h.service.GetUser(ctx)
-	if err != nil {
-		span.SetStatus(1, err.Error())
-		return resp, err
-	}
-
-	resp = &connectorPB.UpdateUserConnectorResourceResponse{}
-
 	pbConnectorReq := req.GetConnectorResource()
 	pbUpdateMask := req.GetUpdateMask()
 
@@ -552,19 +583,18 @@ func (h *PublicHandler) UpdateUserConnectorResource(ctx context.Context, req *co
 			[]*errdetails.BadRequest_FieldViolation{
 				{
 					Field:       "update_mask",
-					Description: err.Error(),
+					Description: fmt.Sprintf("invalid paths %v", pbUpdateMask.GetPaths()),
 				},
 			},
 		)
 		if err != nil {
 			logger.Error(err.Error())
 		}
-		span.SetStatus(1, st.Err().Error())
-		return resp, st.Err()
+		return nil, st.Err()
 	}
 
 	// Remove all OUTPUT_ONLY fields in the requested payload
-	pbUpdateMask, err = checkfield.CheckUpdateOutputOnlyFields(pbUpdateMask, outputOnlyFields)
+	pbUpdateMask, err := checkfield.CheckUpdateOutputOnlyFields(pbUpdateMask, outputOnlyFields)
 	if err != nil {
 		st, err := sterr.CreateErrorBadRequest(
 			"[handler] update connector error",
@@ -578,14 +608,12 @@ func (h *PublicHandler) UpdateUserConnectorResource(ctx context.Context, req *co
 		if err != nil {
 			logger.Error(err.Error())
 		}
-		span.SetStatus(1, st.Err().Error())
-		return resp, st.Err()
+		return nil, st.Err()
 	}
 
-	existedConnectorResource, err := h.service.GetUserConnectorResourceByID(ctx, ns, userUid, connID, connectorPB.View_VIEW_FULL, false)
+	existedConnectorResource, err := s.GetUserConnectorResourceByID(ctx, ns, userUid, connID, connectorPB.View_VIEW_FULL, false)
 	if err != nil {
-		span.SetStatus(1, err.Error())
-		return resp, err
+		return nil, err
 	}
 
 	// Return error if IMMUTABLE fields are intentionally changed
@@ -602,8 +630,7 @@ func (h *PublicHandler) UpdateUserConnectorResource(ctx context.Context, req *co
 		if err != nil {
 			logger.Error(err.Error())
 		}
-		span.SetStatus(1, st.Err().Error())
-		return resp, st.Err()
+		return nil, st.Err()
 	}
 
 	mask, err = fieldmask_utils.MaskFromProtoFieldMask(pbUpdateMask, strcase.ToCamel)
@@ -620,19 +647,12 @@ func (h *PublicHandler) UpdateUserConnectorResource(ctx context.Context, req *co
 		if err != nil {
 			logger.Error(err.Error())
 		}
-		span.SetStatus(1, st.Err().Error())
-		return resp, st.Err()
+		return nil, st.Err()
 	}
 
+	// Nothing to update
 	if mask.IsEmpty() {
-		existedConnectorResource, err := h.service.GetUserConnectorResourceByID(ctx, ns, userUid, connID, connectorPB.View_VIEW_FULL, true)
-		if err != nil {
-			span.SetStatus(1, err.Error())
-			return resp, err
-		}
-		return &connectorPB.UpdateUserConnectorResourceResponse{
-			ConnectorResource: existedConnectorResource,
-		}, nil
+		return nil, nil
 	}
 
 	pbConnectorToUpdate := existedConnectorResource
@@ -649,30 +669,71 @@ func (h *PublicHandler) UpdateUserConnectorResource(ctx context.Context, req *co
 		if err != nil {
 			logger.Error(err.Error())
 		}
-		span.SetStatus(1, st.Err().Error())
 		return nil, st.Err()
 	}
 
 	dbConnDefID, err := resource.GetRscNameID(existedConnectorResource.GetConnectorDefinitionName())
 	if err != nil {
-		span.SetStatus(1, err.Error())
-		return resp, err
+		return nil, err
 	}
 	configuration := &structpb.Struct{}
-	h.service.KeepCredentialFieldsWithMaskString(dbConnDefID, pbConnectorToUpdate.Configuration)
+	s.KeepCredentialFieldsWithMaskString(dbConnDefID, pbConnectorToUpdate.Configuration)
 	proto.Merge(configuration, pbConnectorToUpdate.Configuration)
 
 	// Only the fields mentioned in the field mask will be copied to `pbPipelineToUpdate`, other fields are left intact
 	err = fieldmask_utils.StructToStruct(mask, pbConnectorReq, pbConnectorToUpdate)
 	if err != nil {
-		span.SetStatus(1, err.Error())
-		return resp, err
+		return nil, err
 	}
 
-	h.service.RemoveCredentialFieldsWithMaskString(dbConnDefID, req.ConnectorResource.Configuration)
+	s.RemoveCredentialFieldsWithMaskString(dbConnDefID, req.ConnectorResource.Configuration)
 	proto.Merge(configuration, req.ConnectorResource.Configuration)
 	pbConnectorToUpdate.Configuration = configuration
 
+	return pbConnectorToUpdate, nil
+}
+
+func (h *PublicHandler) UpdateUserConnectorResource(ctx context.Context, req *connectorPB.UpdateUserConnectorResourceRequest) (resp *connectorPB.UpdateUserConnectorResourceResponse, err error) {
+
+	eventName := "UpdateUserConnectorResource"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	ns, connID, err := h.service.GetRscNamespaceAndNameID(req.ConnectorResource.Name)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+
+	resp = &connectorPB.UpdateUserConnectorResourceResponse{}
+
+	pbConnectorToUpdate, err := prepareConnectorResourceUpdate(ctx, h.service, ns, userUid, connID, req)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+	if pbConnectorToUpdate == nil {
+		existedConnectorResource, err := h.service.GetUserConnectorResourceByID(ctx, ns, userUid, connID, connectorPB.View_VIEW_FULL, true)
+		if err != nil {
+			span.SetStatus(1, err.Error())
+			return resp, err
+		}
+		return &connectorPB.UpdateUserConnectorResourceResponse{
+			ConnectorResource: existedConnectorResource,
+		}, nil
+	}
+
 	connectorResource, err := h.service.UpdateUserConnectorResourceByID(ctx, ns, userUid, connID, pbConnectorToUpdate)
 	if err != nil {
 		span.SetStatus(1, err.Error())
@@ -790,6 +851,12 @@ func (h *PublicHandler) ConnectUserConnectorResource(ctx context.Context, req *c
 		return resp, err
 	}
 
+	// The check calls the third-party service with the credentials and updates the state, it requires the editor role
+	if err := h.service.CheckUserConnectorPermission(ctx, ns, userUid, connID, datamodel.ConnectorRoleEditor); err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+
 	connectorResource, err := h.service.GetUserConnectorResourceByID(ctx, ns, userUid, connID, connectorPB.View_VIEW_BASIC, true)
 	if err != nil {
 		span.SetStatus(1, err.Error())
@@ -1082,6 +1149,12 @@ func (h *PublicHandler) TestUserConnectorResource(ctx context.Context, req *conn
 		return resp, err
 	}
 
+	// The check calls the third-party service with the credentials and updates the state, it requires the editor role
+	if err := h.service.CheckUserConnectorPermission(ctx, ns, userUid, connID, datamodel.ConnectorRoleEditor); err != nil {
+		span.SetStatus(1, err.Error())
+		return resp, err
+	}
+
 	connectorResource, err := h.service.GetUserConnectorResourceByID(ctx, ns, userUid, connID, connectorPB.View_VIEW_BASIC, true)
 	if err != nil {
 		span.SetStatus(1, err.Error())
@@ -1108,7 +1181,23 @@ func (h *PublicHandler) TestUserConnectorResource(ctx context.Context, req *conn
 	return resp, nil
 }
 
-func (h *PublicHandler) ExecuteUserConnectorResource(ctx context.Context, req *connectorPB.ExecuteUserConnectorResourceRequest) (resp *connectorPB.ExecuteUserConnectorResourceResponse, err error) {
+func (h *PublicHandler) ExecuteUserConnectorResource(ctx context.Context, req *connectorPB.ExecuteUserConnectorResourceRequest) (*connectorPB.ExecuteUserConnectorResourceResponse, error) {
+	if resource.GetRequestSingleHeader(ctx, constant.HeaderValidateOnly) == "true" {
+		return h.validateUserConnectorResourceInputs(ctx, req)
+	}
+	resp := &connectorPB.ExecuteUserConnectorResourceResponse{}
+	err := h.idempotent(ctx, req.GetName(), "ExecuteUserConnectorResource", req, resp, func() error {
+		executed, err := h.executeUserConnectorResource(ctx, req)
+		if err != nil {
+			return err
+		}
+		proto.Merge(resp, executed)
+		return nil
+	})
+	return resp, err
+}
+
+func (h *PublicHandler) executeUserConnectorResource(ctx context.Context, req *connectorPB.ExecuteUserConnectorResourceRequest) (resp *connectorPB.ExecuteUserConnectorResourceResponse, err error) {
 
 	startTime := time.Now()
 	eventName := "ExecuteUserConnectorResource"
@@ -1157,35 +1246,31 @@ func (h *PublicHandler) ExecuteUserConnectorResource(ctx context.Context, req *c
 		ConnectorExecuteUID:    logUUID.String(),
 		ConnectorDefinitionUid: connectorResource.ConnectorDefinition.Uid,
 		ExecuteTime:            startTime.Format(time.RFC3339Nano),
+		Task:                   req.GetTask(),
+		InputSize:              utils.PayloadSize(req.GetInputs()),
 	}
 
-	md, _ := metadata.FromIncomingContext(ctx)
-
-	pipelineVal := &structpb.Value{}
-	if len(md.Get("id")) > 0 &&
-		len(md.Get("uid")) > 0 &&
-		len(md.Get("release_id")) > 0 &&
-		len(md.Get("release_uid")) > 0 &&
-		len(md.Get("owner")) > 0 &&
-		len(md.Get("trigger_id")) > 0 {
-		pipelineVal, _ = structpb.NewValue(map[string]interface{}{
-			"id":          md.Get("id")[0],
-			"uid":         md.Get("uid")[0],
-			"release_id":  md.Get("release_id")[0],
-			"release_uid": md.Get("release_uid")[0],
-			"owner":       md.Get("owner")[0],
-			"trigger_id":  md.Get("trigger_id")[0],
-		})
-	}
+	pipelineVal := pipelineMetadataFromContext(ctx)
 
-	if outputs, err := h.service.Execute(ctx, ns, userUid, connID, req.GetTask(), req.GetInputs()); err != nil {
+	result, err := h.service.Execute(ctx, ns, userUid, connID, logUUID, req.GetTask(), req.GetInputs())
+	dataPoint.Attempts = result.Attempts
+	dataPoint.CacheHit = result.CacheHit
+	if err != nil {
 		span.SetStatus(1, err.Error())
 		dataPoint.ComputeTimeDuration = time.Since(startTime).Seconds()
 		dataPoint.Status = mgmtPB.Status_STATUS_ERRORED
+		dataPoint.FailedCount = len(req.GetInputs())
+		dataPoint.ErrorMessage = err.Error()
 		_ = h.service.WriteNewDataPoint(ctx, dataPoint, pipelineVal)
 		return nil, err
 	} else {
-		resp.Outputs = outputs
+		resp.Outputs = result.Outputs
+		// The outputs of a cache hit have not been computed by this execution
+		if result.CacheHit {
+			if err := grpc.SetHeader(ctx, metadata.Pairs("x-connector-cache", "hit")); err != nil {
+				logger.Error(err.Error())
+			}
+		}
 		logger.Info(string(custom_otel.NewLogMessage(
 			span,
 			logUUID.String(),
@@ -1194,6 +1279,8 @@ func (h *PublicHandler) ExecuteUserConnectorResource(ctx context.Context, req *c
 		)))
 		dataPoint.ComputeTimeDuration = time.Since(startTime).Seconds()
 		dataPoint.Status = mgmtPB.Status_STATUS_COMPLETED
+		dataPoint.SucceededCount = len(req.GetInputs())
+		dataPoint.OutputSize = utils.PayloadSize(result.Outputs)
 		if err := h.service.WriteNewDataPoint(ctx, dataPoint, pipelineVal); err != nil {
 			logger.Warn("usage and metric data write fail")
 		}
@@ -1201,3 +1288,69 @@ func (h *PublicHandler) ExecuteUserConnectorResource(ctx context.Context, req *c
 	return resp, nil
 
 }
+
+// validateUserConnectorResourceInputs only validates the inputs against the input schema of the task, the
+// connector is not executed and no usage data is written. The response has no outputs if the inputs are valid.
+func (h *PublicHandler) validateUserConnectorResourceInputs(ctx context.Context, req *connectorPB.ExecuteUserConnectorResourceRequest) (*connectorPB.ExecuteUserConnectorResourceResponse, error) {
+
+	eventName := "ValidateUserConnectorResourceInputs"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	ns, connID, err := h.service.GetRscNamespaceAndNameID(req.GetName())
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, err
+	}
+
+	if err := h.service.ValidateInputs(ctx, ns, userUid, connID, req.GetTask(), req.GetInputs()); err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, err
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+		custom_otel.SetEventMessage(fmt.Sprintf("%d inputs valid", len(req.GetInputs()))),
+	)))
+
+	return &connectorPB.ExecuteUserConnectorResourceResponse{}, nil
+}
+
+// pipelineMetadataFromContext returns the metadata of the pipeline triggering the execution, it is empty if the
+// connector is not executed by a pipeline
+func pipelineMetadataFromContext(ctx context.Context) *structpb.Value {
+
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	pipelineVal := &structpb.Value{}
+	if len(md.Get("id")) > 0 &&
+		len(md.Get("uid")) > 0 &&
+		len(md.Get("release_id")) > 0 &&
+		len(md.Get("release_uid")) > 0 &&
+		len(md.Get("owner")) > 0 &&
+		len(md.Get("trigger_id")) > 0 {
+		pipelineVal, _ = structpb.NewValue(map[string]interface{}{
+			"id":          md.Get("id")[0],
+			"uid":         md.Get("uid")[0],
+			"release_id":  md.Get("release_id")[0],
+			"release_uid": md.Get("release_uid")[0],
+			"owner":       md.Get("owner")[0],
+			"trigger_id":  md.Get("trigger_id")[0],
+		})
+	}
+	return pipelineVal
+}
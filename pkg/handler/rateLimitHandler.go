@@ -0,0 +1,352 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/x/sterr"
+
+	custom_otel "github.com/instill-ai/connector-backend/pkg/logger/otel"
+)
+
+// rateLimitResource is the API representation of a rate limit, a token bucket refilled with {rate} tokens per
+// second up to {burst} tokens
+type rateLimitResource struct {
+	Name       string    `json:"name"`
+	Rate       float64   `json:"rate"`
+	Burst      int32     `json:"burst"`
+	UpdateTime time.Time `json:"update_time"`
+}
+
+type updateRateLimitRequest struct {
+	Rate  float64 `json:"rate"`
+	Burst int32   `json:"burst"`
+}
+
+func convertRateLimit(name string, limit *datamodel.RateLimit) *rateLimitResource {
+	return &rateLimitResource{
+		Name:       fmt.Sprintf("%s/rateLimit", name),
+		Rate:       limit.Rate,
+		Burst:      limit.Burst,
+		UpdateTime: limit.UpdateTime,
+	}
+}
+
+// parseConnectorDefinitionUID returns the connector definition uid path parameter
+func parseConnectorDefinitionUID(pathParams map[string]string) (uuid.UUID, error) {
+	defUID, err := uuid.FromString(pathParams["uid"])
+	if err != nil {
+		st, _ := sterr.CreateErrorBadRequest(
+			"[handler] invalid connector definition uid",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "uid",
+					Description: err.Error(),
+				},
+			},
+		)
+		return uuid.Nil, st.Err()
+	}
+	return defUID, nil
+}
+
+func (h *HTTPHandler) GetUserConnectorRateLimit(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "GetUserConnectorRateLimit"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	ns, connID, err := h.service.GetRscNamespaceAndNameID(pathParams["name"])
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	limit, err := h.service.GetUserConnectorRateLimit(ctx, ns, userUid, connID)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+	)))
+
+	return map[string]interface{}{"rate_limit": convertRateLimit(pathParams["name"], limit)}, http.StatusOK, nil
+}
+
+func (h *HTTPHandler) UpdateUserConnectorRateLimit(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "UpdateUserConnectorRateLimit"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	req := &updateRateLimitRequest{}
+	if err := decodeHTTPBody(r, req); err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	ns, connID, err := h.service.GetRscNamespaceAndNameID(pathParams["name"])
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	limit, err := h.service.UpdateUserConnectorRateLimit(ctx, ns, userUid, connID, req.Rate, req.Burst)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+	)))
+
+	return map[string]interface{}{"rate_limit": convertRateLimit(pathParams["name"], limit)}, http.StatusOK, nil
+}
+
+func (h *HTTPHandler) DeleteUserConnectorRateLimit(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "DeleteUserConnectorRateLimit"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	ns, connID, err := h.service.GetRscNamespaceAndNameID(pathParams["name"])
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	if err := h.service.DeleteUserConnectorRateLimit(ctx, ns, userUid, connID); err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+	)))
+
+	return nil, http.StatusNoContent, nil
+}
+
+func (h *HTTPHandler) GetNamespaceRateLimit(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "GetNamespaceRateLimit"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	ns, _, err := h.service.GetRscNamespaceAndNameID(pathParams["name"])
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	limit, err := h.service.GetNamespaceRateLimit(ctx, ns, userUid)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+	)))
+
+	return map[string]interface{}{"rate_limit": convertRateLimit(pathParams["name"], limit)}, http.StatusOK, nil
+}
+
+func (h *HTTPHandler) UpdateNamespaceRateLimit(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "UpdateNamespaceRateLimit"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	req := &updateRateLimitRequest{}
+	if err := decodeHTTPBody(r, req); err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	ns, _, err := h.service.GetRscNamespaceAndNameID(pathParams["name"])
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	limit, err := h.service.UpdateNamespaceRateLimit(ctx, ns, userUid, req.Rate, req.Burst)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+	)))
+
+	return map[string]interface{}{"rate_limit": convertRateLimit(pathParams["name"], limit)}, http.StatusOK, nil
+}
+
+func (h *HTTPHandler) DeleteNamespaceRateLimit(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "DeleteNamespaceRateLimit"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	ns, _, err := h.service.GetRscNamespaceAndNameID(pathParams["name"])
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	if err := h.service.DeleteNamespaceRateLimit(ctx, ns, userUid); err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+	)))
+
+	return nil, http.StatusNoContent, nil
+}
+
+func (h *HTTPHandler) GetConnectorDefinitionRateLimitAdmin(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	defUID, err := parseConnectorDefinitionUID(pathParams)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	limit, err := h.service.GetConnectorDefinitionRateLimitAdmin(ctx, defUID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return map[string]interface{}{"rate_limit": convertRateLimit(fmt.Sprintf("connector-definitions/%s", defUID), limit)}, http.StatusOK, nil
+}
+
+func (h *HTTPHandler) UpdateConnectorDefinitionRateLimitAdmin(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	req := &updateRateLimitRequest{}
+	if err := decodeHTTPBody(r, req); err != nil {
+		return nil, 0, err
+	}
+
+	defUID, err := parseConnectorDefinitionUID(pathParams)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	limit, err := h.service.UpdateConnectorDefinitionRateLimitAdmin(ctx, defUID, req.Rate, req.Burst)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return map[string]interface{}{"rate_limit": convertRateLimit(fmt.Sprintf("connector-definitions/%s", defUID), limit)}, http.StatusOK, nil
+}
+
+func (h *HTTPHandler) DeleteConnectorDefinitionRateLimitAdmin(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	defUID, err := parseConnectorDefinitionUID(pathParams)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := h.service.DeleteConnectorDefinitionRateLimitAdmin(ctx, defUID); err != nil {
+		return nil, 0, err
+	}
+
+	return nil, http.StatusNoContent, nil
+}
@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/connector-backend/pkg/service"
+	"github.com/instill-ai/x/sterr"
+
+	custom_otel "github.com/instill-ai/connector-backend/pkg/logger/otel"
+	codepb "google.golang.org/genproto/googleapis/rpc/code"
+)
+
+// replayItemResource is the result of the replay of one input, Execution names the new execution of the input
+type replayItemResource struct {
+	Execution string `json:"execution"`
+	itemStatus
+}
+
+// replayResource holds the results of the replay of a failed execution aligned with its inputs
+type replayResource struct {
+	Execution string                `json:"execution"`
+	Results   []*replayItemResource `json:"results"`
+}
+
+type replayFailedRequest struct {
+	Window string `json:"window"`
+}
+
+type replayFailedResponse struct {
+	Replays []*replayResource `json:"replays"`
+}
+
+func convertReplay(connName string, replay *service.ReplayResult) *replayResource {
+	resp := &replayResource{
+		Execution: fmt.Sprintf("%s/executions/%s", connName, replay.ExecuteUID),
+		Results:   []*replayItemResource{},
+	}
+	for _, item := range replay.Items {
+		resp.Results = append(resp.Results, &replayItemResource{
+			Execution: fmt.Sprintf("%s/executions/%s", connName, item.ExecuteUID),
+			itemStatus: itemStatus{
+				Code:    codepb.Code(item.Status.Code()).String(),
+				Message: item.Status.Message(),
+			},
+		})
+	}
+	return resp
+}
+
+// ReplayUserConnectorExecution runs the inputs of a failed execution again with the current configuration
+func (h *HTTPHandler) ReplayUserConnectorExecution(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "ReplayUserConnectorExecution"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	name := pathParams["name"]
+	connName := name[:strings.LastIndex(name, "/executions/")]
+
+	uid, err := uuid.FromString(name[strings.LastIndex(name, "/")+1:])
+	if err != nil {
+		st, _ := sterr.CreateErrorBadRequest(
+			"[handler] invalid execution name",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "name",
+					Description: err.Error(),
+				},
+			},
+		)
+		span.SetStatus(1, st.Err().Error())
+		return nil, 0, st.Err()
+	}
+
+	ns, connID, err := h.service.GetRscNamespaceAndNameID(connName)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	replay, err := h.service.ReplayUserConnectorExecution(ctx, ns, userUid, connID, uid)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+		custom_otel.SetEventMessage(fmt.Sprintf("%d inputs of %s replayed", len(replay.Items), name)),
+	)))
+
+	return convertReplay(connName, replay), http.StatusOK, nil
+}
+
+// ReplayUserConnectorFailedExecutions runs the inputs of the failed executions within the window again
+func (h *HTTPHandler) ReplayUserConnectorFailedExecutions(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "ReplayUserConnectorFailedExecutions"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	req := &replayFailedRequest{}
+	if err := decodeHTTPBody(r, req); err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	window, err := time.ParseDuration(req.Window)
+	if err != nil {
+		st, _ := sterr.CreateErrorBadRequest(
+			"[handler] invalid replay window",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "window",
+					Description: err.Error(),
+				},
+			},
+		)
+		span.SetStatus(1, st.Err().Error())
+		return nil, 0, st.Err()
+	}
+
+	ns, connID, err := h.service.GetRscNamespaceAndNameID(pathParams["name"])
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	replays, err := h.service.ReplayUserConnectorFailedExecutions(ctx, ns, userUid, connID, window)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	resp := &replayFailedResponse{Replays: []*replayResource{}}
+	for _, replay := range replays {
+		resp.Replays = append(resp.Replays, convertReplay(pathParams["name"], replay))
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+		custom_otel.SetEventMessage(fmt.Sprintf("%d failed executions replayed", len(replays))),
+	)))
+
+	return resp, http.StatusOK, nil
+}
@@ -0,0 +1,305 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/x/sterr"
+
+	custom_otel "github.com/instill-ai/connector-backend/pkg/logger/otel"
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+// revisionResource is the API representation of a connector revision
+type revisionResource struct {
+	Name          string                  `json:"name"`
+	UID           string                  `json:"uid"`
+	Revision      int32                   `json:"revision"`
+	Configuration json.RawMessage         `json:"configuration"`
+	Changes       []datamodel.FieldChange `json:"changes"`
+	Description   string                  `json:"description"`
+	Visibility    string                  `json:"visibility"`
+	Creator       string                  `json:"creator"`
+	CreateTime    time.Time               `json:"create_time"`
+}
+
+type listRevisionsResponse struct {
+	Revisions     []*revisionResource `json:"revisions"`
+	NextPageToken string              `json:"next_page_token"`
+	TotalSize     int32               `json:"total_size"`
+}
+
+type diffRevisionsResponse struct {
+	Changes []datamodel.FieldChange `json:"changes"`
+}
+
+type rollbackRequest struct {
+	Revision int32 `json:"revision"`
+}
+
+func (h *HTTPHandler) convertRevision(connName string, revision *datamodel.ConnectorRevision) (*revisionResource, error) {
+	changes := []datamodel.FieldChange{}
+	if err := json.Unmarshal(revision.Diff, &changes); err != nil {
+		return nil, err
+	}
+	// The acting user may not exist anymore
+	creator, err := h.service.ConvertOwnerPermalinkToName(revision.Creator)
+	if err != nil {
+		creator = revision.Creator
+	}
+	return &revisionResource{
+		Name:          fmt.Sprintf("%s/revisions/%d", connName, revision.Revision),
+		UID:           revision.UID.String(),
+		Revision:      revision.Revision,
+		Configuration: json.RawMessage(revision.Configuration),
+		Changes:       changes,
+		Description:   revision.Description.String,
+		Visibility:    connectorPB.ConnectorResource_Visibility(revision.Visibility).String(),
+		Creator:       creator,
+		CreateTime:    revision.CreateTime,
+	}, nil
+}
+
+// parseRevision parses a revision number given in field
+func parseRevision(field string, value string) (int32, error) {
+	revision, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || revision < 1 {
+		st, _ := sterr.CreateErrorBadRequest(
+			"[handler] invalid revision",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       field,
+					Description: "the revision must be a positive integer",
+				},
+			},
+		)
+		return 0, st.Err()
+	}
+	return int32(revision), nil
+}
+
+func (h *HTTPHandler) ListUserConnectorRevisions(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "ListUserConnectorRevisions"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	pageSize, pageToken, err := parsePageQuery(r)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	ns, connID, err := h.service.GetRscNamespaceAndNameID(pathParams["name"])
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	revisions, totalSize, nextPageToken, err := h.service.ListUserConnectorRevisions(ctx, ns, userUid, connID, pageSize, pageToken)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	resp := &listRevisionsResponse{
+		Revisions:     []*revisionResource{},
+		NextPageToken: nextPageToken,
+		TotalSize:     int32(totalSize),
+	}
+	for _, revision := range revisions {
+		rev, err := h.convertRevision(pathParams["name"], revision)
+		if err != nil {
+			span.SetStatus(1, err.Error())
+			return nil, 0, err
+		}
+		resp.Revisions = append(resp.Revisions, rev)
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+	)))
+
+	return resp, http.StatusOK, nil
+}
+
+func (h *HTTPHandler) GetUserConnectorRevision(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "GetUserConnectorRevision"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	name := pathParams["name"]
+	connName := name[:strings.LastIndex(name, "/revisions/")]
+
+	revision, err := parseRevision("name", name[strings.LastIndex(name, "/")+1:])
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	ns, connID, err := h.service.GetRscNamespaceAndNameID(connName)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	connRevision, err := h.service.GetUserConnectorRevision(ctx, ns, userUid, connID, revision)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	resp, err := h.convertRevision(connName, connRevision)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+		custom_otel.SetEventResource(resp),
+	)))
+
+	return map[string]interface{}{"revision": resp}, http.StatusOK, nil
+}
+
+func (h *HTTPHandler) DiffUserConnectorRevisions(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "DiffUserConnectorRevisions"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	from, err := parseRevision("from", r.URL.Query().Get("from"))
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	to, err := parseRevision("to", r.URL.Query().Get("to"))
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	ns, connID, err := h.service.GetRscNamespaceAndNameID(pathParams["name"])
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	changes, err := h.service.DiffUserConnectorRevisions(ctx, ns, userUid, connID, from, to)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+	)))
+
+	return &diffRevisionsResponse{Changes: changes}, http.StatusOK, nil
+}
+
+func (h *HTTPHandler) RollbackUserConnectorResource(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "UpdateUserConnectorResourceRollback"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	req := &rollbackRequest{}
+	if err := decodeHTTPBody(r, req); err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	if _, err := parseRevision("revision", strconv.Itoa(int(req.Revision))); err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	ns, connID, err := h.service.GetRscNamespaceAndNameID(pathParams["name"])
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	connectorResource, err := h.service.RollbackUserConnectorResource(ctx, ns, userUid, connID, req.Revision)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+		custom_otel.SetEventResource(connectorResource),
+		custom_otel.SetEventMessage(fmt.Sprintf("rolled back to revision %d", req.Revision)),
+	)))
+
+	return map[string]interface{}{"connector_resource": connectorResource}, http.StatusOK, nil
+}
@@ -0,0 +1,401 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+
+	"github.com/instill-ai/connector-backend/internal/resource"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/connector-backend/pkg/utils"
+	"github.com/instill-ai/x/checkfield"
+	"github.com/instill-ai/x/sterr"
+
+	custom_otel "github.com/instill-ai/connector-backend/pkg/logger/otel"
+)
+
+// secretResource is the API representation of a secret, the secret value is write-only
+type secretResource struct {
+	Name        string    `json:"name"`
+	UID         string    `json:"uid"`
+	ID          string    `json:"id"`
+	Description string    `json:"description"`
+	Reference   string    `json:"reference"`
+	CreateTime  time.Time `json:"create_time"`
+	UpdateTime  time.Time `json:"update_time"`
+}
+
+type createSecretRequest struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	Value       string `json:"value"`
+}
+
+type updateSecretRequest struct {
+	Description *string `json:"description"`
+	Value       *string `json:"value"`
+}
+
+type listSecretsResponse struct {
+	Secrets       []*secretResource `json:"secrets"`
+	NextPageToken string            `json:"next_page_token"`
+	TotalSize     int32             `json:"total_size"`
+}
+
+func (h *HTTPHandler) convertSecret(secret *datamodel.Secret) (*secretResource, error) {
+	owner, err := h.service.ConvertOwnerPermalinkToName(secret.Owner)
+	if err != nil {
+		return nil, err
+	}
+	return &secretResource{
+		Name:        fmt.Sprintf("%s/secrets/%s", owner, secret.ID),
+		UID:         secret.UID.String(),
+		ID:          secret.ID,
+		Description: secret.Description.String,
+		Reference:   utils.SecretReference(secret.ID),
+		CreateTime:  secret.CreateTime,
+		UpdateTime:  secret.UpdateTime,
+	}, nil
+}
+
+// checkSecretNamespace only lets users manage the secrets of their own namespace
+func checkSecretNamespace(ns resource.Namespace, userUid uuid.UUID) error {
+	if ns.String() != resource.UserUidToUserPermalink(userUid) {
+		st, _ := sterr.CreateErrorBadRequest(
+			"[handler] secret error",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Description: "can not access secrets in other user's namespace",
+				},
+			},
+		)
+		return st.Err()
+	}
+	return nil
+}
+
+func (h *HTTPHandler) CreateUserSecret(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "CreateUserSecret"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	ns, _, err := h.service.GetRscNamespaceAndNameID(pathParams["parent"])
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	if err := checkSecretNamespace(ns, userUid); err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	req := &createSecretRequest{}
+	if err := decodeHTTPBody(r, req); err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	// Return error if resource ID does not follow RFC-1034
+	if err := checkfield.CheckResourceID(req.ID); err != nil {
+		st, err := sterr.CreateErrorBadRequest(
+			"[handler] create secret error",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "id",
+					Description: err.Error(),
+				},
+			},
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		span.SetStatus(1, st.Err().Error())
+		return nil, 0, st.Err()
+	}
+
+	if err := checkSecretValue(req.Value); err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	secret, err := h.service.CreateUserSecret(ctx, ns, userUid, req.ID, req.Description, req.Value)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	resp, err := h.convertSecret(secret)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+		custom_otel.SetEventResource(resp),
+	)))
+
+	return map[string]interface{}{"secret": resp}, http.StatusCreated, nil
+}
+
+func (h *HTTPHandler) ListUserSecrets(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "ListUserSecrets"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	pageSize, pageToken, err := parsePageQuery(r)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	ns, _, err := h.service.GetRscNamespaceAndNameID(pathParams["parent"])
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	if err := checkSecretNamespace(ns, userUid); err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	secrets, totalSize, nextPageToken, err := h.service.ListUserSecrets(ctx, ns, userUid, pageSize, pageToken)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	resp := &listSecretsResponse{
+		Secrets:       []*secretResource{},
+		NextPageToken: nextPageToken,
+		TotalSize:     int32(totalSize),
+	}
+	for _, secret := range secrets {
+		s, err := h.convertSecret(secret)
+		if err != nil {
+			span.SetStatus(1, err.Error())
+			return nil, 0, err
+		}
+		resp.Secrets = append(resp.Secrets, s)
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+	)))
+
+	return resp, http.StatusOK, nil
+}
+
+func (h *HTTPHandler) GetUserSecret(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "GetUserSecret"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	ns, secretID, err := h.service.GetRscNamespaceAndNameID(pathParams["name"])
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	if err := checkSecretNamespace(ns, userUid); err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	secret, err := h.service.GetUserSecretByID(ctx, ns, userUid, secretID)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	resp, err := h.convertSecret(secret)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+		custom_otel.SetEventResource(resp),
+	)))
+
+	return map[string]interface{}{"secret": resp}, http.StatusOK, nil
+}
+
+func (h *HTTPHandler) UpdateUserSecret(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "UpdateUserSecret"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	ns, secretID, err := h.service.GetRscNamespaceAndNameID(pathParams["name"])
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	if err := checkSecretNamespace(ns, userUid); err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	req := &updateSecretRequest{}
+	if err := decodeHTTPBody(r, req); err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	if req.Value != nil {
+		if err := checkSecretValue(*req.Value); err != nil {
+			span.SetStatus(1, err.Error())
+			return nil, 0, err
+		}
+	}
+
+	secret, err := h.service.UpdateUserSecretByID(ctx, ns, userUid, secretID, req.Description, req.Value)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	resp, err := h.convertSecret(secret)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+		custom_otel.SetEventResource(resp),
+	)))
+
+	return map[string]interface{}{"secret": resp}, http.StatusOK, nil
+}
+
+func (h *HTTPHandler) DeleteUserSecret(ctx context.Context, w http.ResponseWriter, r *http.Request, pathParams map[string]string) (interface{}, int, error) {
+
+	eventName := "DeleteUserSecret"
+
+	ctx, span := tracer.Start(ctx, eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	ns, secretID, err := h.service.GetRscNamespaceAndNameID(pathParams["name"])
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+	if err := checkSecretNamespace(ns, userUid); err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	if err := h.service.DeleteUserSecretByID(ctx, ns, userUid, secretID); err != nil {
+		span.SetStatus(1, err.Error())
+		return nil, 0, err
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+		custom_otel.SetEventMessage(fmt.Sprintf("secret %s deleted", secretID)),
+	)))
+
+	return nil, http.StatusNoContent, nil
+}
+
+// checkSecretValue rejects empty values and values that would make the secret a reference to another secret
+func checkSecretValue(value string) error {
+	description := ""
+	if value == "" {
+		description = "the secret value can not be empty"
+	} else if _, ok := utils.GetSecretReference(value); ok {
+		description = "the secret value can not be a secret reference"
+	}
+	if description == "" {
+		return nil
+	}
+	st, _ := sterr.CreateErrorBadRequest(
+		"[handler] secret error",
+		[]*errdetails.BadRequest_FieldViolation{
+			{
+				Field:       "value",
+				Description: description,
+			},
+		},
+	)
+	return st.Err()
+}
@@ -0,0 +1,301 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/instill-ai/connector-backend/config"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/connector-backend/pkg/service"
+	"github.com/instill-ai/connector-backend/pkg/utils"
+	"github.com/instill-ai/x/sterr"
+
+	custom_otel "github.com/instill-ai/connector-backend/pkg/logger/otel"
+	mgmtPB "github.com/instill-ai/protogen-go/core/mgmt/v1alpha"
+	codepb "google.golang.org/genproto/googleapis/rpc/code"
+)
+
+// ConnectorStreamServiceServer is the server API of the streaming endpoints, their messages are
+// google.protobuf.Struct until the connector protobuf API defines them
+type ConnectorStreamServiceServer interface {
+	ExecuteUserConnectorResourceStream(stream grpc.ServerStream) error
+}
+
+// ConnectorStreamServiceDesc describes the vdp.connector.v1alpha.ConnectorStreamService gRPC service
+var ConnectorStreamServiceDesc = grpc.ServiceDesc{
+	ServiceName: "vdp.connector.v1alpha.ConnectorStreamService",
+	HandlerType: (*ConnectorStreamServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName: "ExecuteUserConnectorResourceStream",
+			Handler: func(srv interface{}, stream grpc.ServerStream) error {
+				return srv.(ConnectorStreamServiceServer).ExecuteUserConnectorResourceStream(stream)
+			},
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+}
+
+// StreamHandler handles the streaming endpoints
+type StreamHandler struct {
+	service service.Service
+}
+
+// NewStreamHandler initiates a handler instance
+func NewStreamHandler(ctx context.Context, s service.Service) ConnectorStreamServiceServer {
+	return &StreamHandler{
+		service: s,
+	}
+}
+
+// ExecuteUserConnectorResourceStream executes the inputs streamed by the client in batches against a single
+// execution of the connector. The first message opens the execution:
+//
+//	{"name": "users/{user}/connector-resources/{id}", "task": "...", "batch_size": 100, "inputs": [...]}
+//
+// the following ones only carry inputs. A message is sent back for each batch, the outputs are aligned with
+// the inputs of the batch and are null for the inputs that have failed:
+//
+//	{"offset": 0, "outputs": [{...}, null], "errors": [{"index": 1, "code": "INVALID_ARGUMENT", "message": "..."}]}
+//
+// Each batch takes a token of the rate limits, the stream ends with RESOURCE_EXHAUSTED and a RetryInfo when
+// there is none left.
+func (h *StreamHandler) ExecuteUserConnectorResourceStream(stream grpc.ServerStream) error {
+
+	eventName := "ExecuteUserConnectorResourceStream"
+
+	ctx, span := tracer.Start(stream.Context(), eventName,
+		trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	logUUID, _ := uuid.NewV4()
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	first := &structpb.Struct{}
+	if err := stream.RecvMsg(first); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		span.SetStatus(1, err.Error())
+		return err
+	}
+
+	batchSize, err := parseBatchSize(first.GetFields()["batch_size"])
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return err
+	}
+
+	ns, connID, err := h.service.GetRscNamespaceAndNameID(first.GetFields()["name"].GetStringValue())
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return err
+	}
+	_, userUid, err := h.service.GetUser(ctx)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return err
+	}
+
+	execution, err := h.service.CreateBatchExecution(ctx, ns, userUid, connID, first.GetFields()["task"].GetStringValue())
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return err
+	}
+	if execution.ConnectorResource.Tombstone {
+		st, _ := sterr.CreateErrorPreconditionFailure(
+			"ExecuteConnector",
+			[]*errdetails.PreconditionFailure_Violation{
+				{
+					Type:        "STATE",
+					Subject:     fmt.Sprintf("id %s", connID),
+					Description: "the connector definition is deprecated, you can not use it anymore",
+				},
+			})
+		span.SetStatus(1, st.Err().Error())
+		return st.Err()
+	}
+
+	pipelineVal := pipelineMetadataFromContext(ctx)
+
+	offset := 0
+	batches := 0
+	execute := func(inputs []*structpb.Struct) error {
+		startTime := time.Now()
+		executeUID := uuid.Must(uuid.NewV4())
+		outputs, itemErrors, err := execution.Execute(ctx, executeUID, inputs)
+		if err != nil {
+			return err
+		}
+
+		dataPoint := utils.UsageMetricData{
+			OwnerUID:               userUid.String(),
+			ConnectorID:            execution.ConnectorResource.ID,
+			ConnectorUID:           execution.ConnectorResource.UID.String(),
+			ConnectorExecuteUID:    executeUID.String(),
+			ConnectorDefinitionUid: execution.ConnectorResource.ConnectorDefinitionUID.String(),
+			ExecuteTime:            startTime.Format(time.RFC3339Nano),
+			ComputeTimeDuration:    time.Since(startTime).Seconds(),
+			Status:                 mgmtPB.Status_STATUS_COMPLETED,
+			SucceededCount:         len(inputs) - len(itemErrors),
+			FailedCount:            len(itemErrors),
+			Task:                   first.GetFields()["task"].GetStringValue(),
+			InputSize:              utils.PayloadSize(inputs),
+			OutputSize:             utils.PayloadSize(outputs),
+		}
+		if len(itemErrors) > 0 {
+			dataPoint.Status = mgmtPB.Status_STATUS_ERRORED
+			dataPoint.ErrorMessage = itemErrors[0].Status.Message()
+		}
+		if err := h.service.WriteNewDataPoint(ctx, dataPoint, pipelineVal); err != nil {
+			logger.Warn("usage and metric data write fail")
+		}
+
+		resp := batchResponse(offset, outputs, itemErrors)
+		offset += len(inputs)
+		batches++
+		return stream.SendMsg(resp)
+	}
+
+	buffered, err := streamedInputs(first)
+	if err != nil {
+		span.SetStatus(1, err.Error())
+		return err
+	}
+	for {
+		for len(buffered) >= batchSize {
+			if err := execute(buffered[:batchSize]); err != nil {
+				span.SetStatus(1, err.Error())
+				return err
+			}
+			buffered = buffered[batchSize:]
+		}
+
+		msg := &structpb.Struct{}
+		if err := stream.RecvMsg(msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			span.SetStatus(1, err.Error())
+			return err
+		}
+		inputs, err := streamedInputs(msg)
+		if err != nil {
+			span.SetStatus(1, err.Error())
+			return err
+		}
+		buffered = append(buffered, inputs...)
+	}
+	if len(buffered) > 0 {
+		if err := execute(buffered); err != nil {
+			span.SetStatus(1, err.Error())
+			return err
+		}
+	}
+
+	logger.Info(string(custom_otel.NewLogMessage(
+		span,
+		logUUID.String(),
+		userUid,
+		eventName,
+		custom_otel.SetEventMessage(fmt.Sprintf("%d inputs executed in %d batches", offset, batches)),
+	)))
+
+	return nil
+}
+
+// defaultBatchSize is the batch size of a streaming execution when it is neither requested nor configured
+const defaultBatchSize = 100
+
+// parseBatchSize returns the batch size requested by the client, the configured one if it is not set
+func parseBatchSize(v *structpb.Value) (int, error) {
+	if v == nil {
+		if config.Config.Execution.BatchSize > 0 {
+			return config.Config.Execution.BatchSize, nil
+		}
+		return defaultBatchSize, nil
+	}
+	batchSize := int(v.GetNumberValue())
+	if float64(batchSize) != v.GetNumberValue() || batchSize < 1 || (config.Config.Execution.MaxBatchSize > 0 && batchSize > config.Config.Execution.MaxBatchSize) {
+		description := "the batch size must be an integer of at least 1"
+		if config.Config.Execution.MaxBatchSize > 0 {
+			description = fmt.Sprintf("the batch size must be an integer between 1 and %d", config.Config.Execution.MaxBatchSize)
+		}
+		st, _ := sterr.CreateErrorBadRequest(
+			"[handler] invalid batch size",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "batch_size",
+					Description: description,
+				},
+			},
+		)
+		return 0, st.Err()
+	}
+	return batchSize, nil
+}
+
+// streamedInputs returns the inputs of a streamed message
+func streamedInputs(msg *structpb.Struct) ([]*structpb.Struct, error) {
+	inputs := []*structpb.Struct{}
+	for idx, v := range msg.GetFields()["inputs"].GetListValue().GetValues() {
+		input := v.GetStructValue()
+		if input == nil {
+			st, _ := sterr.CreateErrorBadRequest(
+				"[handler] invalid inputs",
+				[]*errdetails.BadRequest_FieldViolation{
+					{
+						Field:       fmt.Sprintf("inputs[%d]", idx),
+						Description: "an input must be an object",
+					},
+				},
+			)
+			return nil, st.Err()
+		}
+		inputs = append(inputs, input)
+	}
+	return inputs, nil
+}
+
+// batchResponse returns the message streamed back for a batch, the error indexes are relative to the stream
+func batchResponse(offset int, outputs []*structpb.Struct, itemErrors []service.ExecutionItemError) *structpb.Struct {
+	outputValues := make([]*structpb.Value, len(outputs))
+	for i, output := range outputs {
+		if output == nil {
+			outputValues[i] = structpb.NewNullValue()
+		} else {
+			outputValues[i] = structpb.NewStructValue(output)
+		}
+	}
+
+	errorValues := make([]*structpb.Value, len(itemErrors))
+	for i, itemError := range itemErrors {
+		errorValues[i] = structpb.NewStructValue(&structpb.Struct{
+			Fields: map[string]*structpb.Value{
+				"index":   structpb.NewNumberValue(float64(offset + itemError.Index)),
+				"code":    structpb.NewStringValue(codepb.Code(itemError.Status.Code()).String()),
+				"message": structpb.NewStringValue(itemError.Status.Message()),
+			},
+		})
+	}
+
+	return &structpb.Struct{
+		Fields: map[string]*structpb.Value{
+			"offset":  structpb.NewNumberValue(float64(offset)),
+			"outputs": structpb.NewListValue(&structpb.ListValue{Values: outputValues}),
+			"errors":  structpb.NewListValue(&structpb.ListValue{Values: errorValues}),
+		},
+	}
+}
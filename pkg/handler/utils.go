@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"go.einride.tech/aip/filtering"
+
 	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
 )
 
@@ -11,3 +13,14 @@ func parseView(view connectorPB.View) connectorPB.View {
 	}
 	return parsedView
 }
+
+// connectorResourceFilterDeclarations are the fields a connector resource list can be filtered on, e.g.
+// `connector_type = CONNECTOR_TYPE_AI AND labels.env = "prod"` or `labels:team`
+func connectorResourceFilterDeclarations() (*filtering.Declarations, error) {
+	var connType connectorPB.ConnectorType
+	return filtering.NewDeclarations([]filtering.DeclarationOption{
+		filtering.DeclareStandardFunctions(),
+		filtering.DeclareEnumIdent("connector_type", connType.Type()),
+		filtering.DeclareIdent("labels", filtering.TypeMap(filtering.TypeString, filtering.TypeString)),
+	}...)
+}
@@ -0,0 +1,308 @@
+// Package manifest converts connector resources to and from the YAML manifests used to keep them in git
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/instill-ai/connector-backend/pkg/utils"
+
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+// CredentialPlaceholder replaces the value of the credential fields in the exported manifests. It is to be
+// replaced by the credential or by a secret reference, e.g. ${secrets/openai-key}, before a connector is created.
+// Applied to an existing connector, it keeps the live value of the field.
+const CredentialPlaceholder = "${credential}"
+
+// Manifest is the declarative form of a connector resource
+type Manifest struct {
+	ID                      string                 `json:"id"`
+	ConnectorDefinitionName string                 `json:"connector_definition_name"`
+	Description             string                 `json:"description,omitempty"`
+	Visibility              string                 `json:"visibility,omitempty"`
+	Configuration           map[string]interface{} `json:"configuration,omitempty"`
+}
+
+// documentSeparator splits a YAML stream into its documents
+var documentSeparator = regexp.MustCompile(`(?m)^---[ \t]*$`)
+
+// replaceStrings replaces in place the string values of a configuration equal to from
+func replaceStrings(configuration map[string]interface{}, from string, to string) {
+	for k, v := range configuration {
+		switch v := v.(type) {
+		case string:
+			if v == from {
+				configuration[k] = to
+			}
+		case map[string]interface{}:
+			replaceStrings(v, from, to)
+		}
+	}
+}
+
+// hasString tells whether a string value of the configuration equals value
+func hasString(configuration map[string]interface{}, value string) bool {
+	for _, v := range configuration {
+		switch v := v.(type) {
+		case string:
+			if v == value {
+				return true
+			}
+		case map[string]interface{}:
+			if hasString(v, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FromConnectorResource returns the manifest of a connector resource whose credential fields are masked
+func FromConnectorResource(connectorResource *connectorPB.ConnectorResource) *Manifest {
+	m := &Manifest{
+		ID:                      connectorResource.GetId(),
+		ConnectorDefinitionName: connectorResource.GetConnectorDefinitionName(),
+		Description:             connectorResource.GetDescription(),
+		Visibility:              connectorResource.GetVisibility().String(),
+	}
+	if connectorResource.GetVisibility() == connectorPB.ConnectorResource_VISIBILITY_UNSPECIFIED {
+		m.Visibility = ""
+	}
+	if connectorResource.GetConfiguration() != nil {
+		m.Configuration = connectorResource.GetConfiguration().AsMap()
+		replaceStrings(m.Configuration, utils.CredentialMaskString, CredentialPlaceholder)
+	}
+	return m
+}
+
+// HasCredentialPlaceholders tells whether credential placeholders are left in the manifest
+func (m *Manifest) HasCredentialPlaceholders() bool {
+	return hasString(m.Configuration, CredentialPlaceholder)
+}
+
+// ToConnectorResource returns the connector resource of the manifest, the credential placeholders are turned
+// into masked values which keep the live credentials on update
+func (m *Manifest) ToConnectorResource() (*connectorPB.ConnectorResource, error) {
+
+	visibility := connectorPB.ConnectorResource_VISIBILITY_UNSPECIFIED
+	if m.Visibility != "" {
+		v, ok := connectorPB.ConnectorResource_Visibility_value[m.Visibility]
+		if !ok {
+			return nil, fmt.Errorf("connector %s: unknown visibility %s", m.ID, m.Visibility)
+		}
+		visibility = connectorPB.ConnectorResource_Visibility(v)
+	}
+
+	configuration := map[string]interface{}{}
+	if m.Configuration != nil {
+		// A copy is made through the protobuf struct so that the manifest is left untouched
+		s, err := structpb.NewStruct(m.Configuration)
+		if err != nil {
+			return nil, fmt.Errorf("connector %s: %w", m.ID, err)
+		}
+		configuration = s.AsMap()
+	}
+	replaceStrings(configuration, CredentialPlaceholder, utils.CredentialMaskString)
+	pbConfiguration, err := structpb.NewStruct(configuration)
+	if err != nil {
+		return nil, fmt.Errorf("connector %s: %w", m.ID, err)
+	}
+
+	description := m.Description
+	return &connectorPB.ConnectorResource{
+		Id:                      m.ID,
+		ConnectorDefinitionName: m.ConnectorDefinitionName,
+		Description:             &description,
+		Visibility:              visibility,
+		Configuration:           pbConfiguration,
+	}, nil
+}
+
+// Marshal writes the manifests as a YAML stream, one document per connector sorted by id
+func Marshal(manifests []*Manifest) ([]byte, error) {
+	sorted := append([]*Manifest{}, manifests...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+
+	var buf bytes.Buffer
+	for idx, m := range sorted {
+		b, err := yaml.Marshal(m)
+		if err != nil {
+			return nil, err
+		}
+		if idx > 0 {
+			buf.WriteString("---\n")
+		}
+		buf.Write(b)
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal reads the manifests of a YAML stream, the empty documents are skipped
+func Unmarshal(b []byte) ([]*Manifest, error) {
+	manifests := []*Manifest{}
+	for _, doc := range documentSeparator.Split(string(b), -1) {
+		if len(bytes.TrimSpace([]byte(doc))) == 0 {
+			continue
+		}
+		m := &Manifest{}
+		if err := yaml.Unmarshal([]byte(doc), m); err != nil {
+			return nil, err
+		}
+		if m.ID == "" || m.ConnectorDefinitionName == "" {
+			return nil, fmt.Errorf("a manifest requires an id and a connector_definition_name")
+		}
+		manifests = append(manifests, m)
+	}
+	return manifests, nil
+}
+
+// Action is what apply does to a connector to converge to its manifest
+type Action string
+
+// Actions of a plan, an unverified connector is left as is
+const (
+	ActionCreate     Action = "create"
+	ActionUpdate     Action = "update"
+	ActionDelete     Action = "delete"
+	ActionUnverified Action = "unverified"
+)
+
+// Change is a step of a plan, Fields lists the fields to update and Unverified the credential fields of the
+// configuration whose desired value can not be compared with the live one
+type Change struct {
+	Action     Action
+	ID         string
+	Desired    *Manifest
+	Live       *Manifest
+	Fields     []string
+	Unverified []string
+}
+
+// Plan returns the changes converging the live connectors to the desired manifests: the connectors without
+// manifest are deleted, the ones without live connector are created and the differing ones updated. The live
+// credentials are hidden, a desired credential is only compared if it is a secret reference: a credential
+// placeholder is equal to any live credential and a credential value is reported as unverified.
+func Plan(desired []*Manifest, live []*Manifest) ([]*Change, error) {
+
+	liveByID := map[string]*Manifest{}
+	for _, m := range live {
+		liveByID[m.ID] = m
+	}
+
+	changes := []*Change{}
+	desiredIDs := map[string]bool{}
+	for _, m := range desired {
+		if desiredIDs[m.ID] {
+			return nil, fmt.Errorf("connector %s has several manifests", m.ID)
+		}
+		desiredIDs[m.ID] = true
+
+		l, ok := liveByID[m.ID]
+		if !ok {
+			changes = append(changes, &Change{Action: ActionCreate, ID: m.ID, Desired: m})
+			continue
+		}
+		if m.ConnectorDefinitionName != l.ConnectorDefinitionName {
+			return nil, fmt.Errorf("connector %s: connector_definition_name is immutable, %s is live", m.ID, l.ConnectorDefinitionName)
+		}
+
+		fields := []string{}
+		if m.Description != l.Description {
+			fields = append(fields, "description")
+		}
+		if m.Visibility != "" && m.Visibility != l.Visibility {
+			fields = append(fields, "visibility")
+		}
+		desiredConfiguration, liveConfiguration := normalize(m.Configuration), normalize(l.Configuration)
+		var unverified []string
+		for _, field := range hiddenCredentials(liveConfiguration, "") {
+			value, ok := lookUpString(desiredConfiguration, field)
+			if !ok || value == CredentialPlaceholder {
+				continue
+			}
+			if _, isReference := utils.GetSecretReference(value); isReference {
+				continue
+			}
+			setString(desiredConfiguration, field, CredentialPlaceholder)
+			unverified = append(unverified, fmt.Sprintf("configuration.%s", field))
+		}
+		if !reflect.DeepEqual(desiredConfiguration, liveConfiguration) {
+			fields = append(fields, "configuration")
+		}
+		switch {
+		case len(fields) > 0:
+			changes = append(changes, &Change{Action: ActionUpdate, ID: m.ID, Desired: m, Live: l, Fields: fields, Unverified: unverified})
+		case len(unverified) > 0:
+			changes = append(changes, &Change{Action: ActionUnverified, ID: m.ID, Desired: m, Live: l, Unverified: unverified})
+		}
+	}
+
+	for _, l := range live {
+		if !desiredIDs[l.ID] {
+			changes = append(changes, &Change{Action: ActionDelete, ID: l.ID, Live: l})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].ID < changes[j].ID })
+	return changes, nil
+}
+
+// normalize returns the configuration as decoded from JSON so that the numbers compare equal
+func normalize(configuration map[string]interface{}) map[string]interface{} {
+	if len(configuration) == 0 {
+		return map[string]interface{}{}
+	}
+	s, err := structpb.NewStruct(configuration)
+	if err != nil {
+		return configuration
+	}
+	return s.AsMap()
+}
+
+// hiddenCredentials returns the sorted dotted paths of the credential placeholders of a configuration
+func hiddenCredentials(configuration map[string]interface{}, prefix string) []string {
+	fields := []string{}
+	for k, v := range configuration {
+		switch v := v.(type) {
+		case string:
+			if v == CredentialPlaceholder {
+				fields = append(fields, prefix+k)
+			}
+		case map[string]interface{}:
+			fields = append(fields, hiddenCredentials(v, fmt.Sprintf("%s%s.", prefix, k))...)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// lookUpString returns the string value at the dotted path of the configuration
+func lookUpString(configuration map[string]interface{}, path string) (string, bool) {
+	keys := strings.Split(path, ".")
+	for _, key := range keys[:len(keys)-1] {
+		nested, ok := configuration[key].(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+		configuration = nested
+	}
+	value, ok := configuration[keys[len(keys)-1]].(string)
+	return value, ok
+}
+
+// setString sets the value at the dotted path of the configuration, the path must exist
+func setString(configuration map[string]interface{}, path string, value string) {
+	keys := strings.Split(path, ".")
+	for _, key := range keys[:len(keys)-1] {
+		configuration = configuration[key].(map[string]interface{})
+	}
+	configuration[keys[len(keys)-1]] = value
+}
@@ -0,0 +1,347 @@
+package manifest
+
+import (
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/instill-ai/connector-backend/pkg/utils"
+
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+func TestFromConnectorResource(t *testing.T) {
+	configuration, err := structpb.NewStruct(map[string]interface{}{
+		"api_key": utils.CredentialMaskString,
+		"model":   "gpt-4",
+		"nested":  map[string]interface{}{"token": utils.CredentialMaskString},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	description := "chat"
+
+	testCases := []struct {
+		name string
+		in   *connectorPB.ConnectorResource
+		want *Manifest
+	}{
+		{
+			name: "credentials masked",
+			in: &connectorPB.ConnectorResource{
+				Id:                      "openai",
+				ConnectorDefinitionName: "connector-definitions/ai-openai",
+				Description:             &description,
+				Visibility:              connectorPB.ConnectorResource_VISIBILITY_PRIVATE,
+				Configuration:           configuration,
+			},
+			want: &Manifest{
+				ID:                      "openai",
+				ConnectorDefinitionName: "connector-definitions/ai-openai",
+				Description:             "chat",
+				Visibility:              "VISIBILITY_PRIVATE",
+				Configuration: map[string]interface{}{
+					"api_key": CredentialPlaceholder,
+					"model":   "gpt-4",
+					"nested":  map[string]interface{}{"token": CredentialPlaceholder},
+				},
+			},
+		},
+		{
+			name: "unspecified visibility",
+			in: &connectorPB.ConnectorResource{
+				Id:                      "pinecone",
+				ConnectorDefinitionName: "connector-definitions/data-pinecone",
+			},
+			want: &Manifest{
+				ID:                      "pinecone",
+				ConnectorDefinitionName: "connector-definitions/data-pinecone",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := FromConnectorResource(tc.in); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestToConnectorResource(t *testing.T) {
+	m := &Manifest{
+		ID:                      "openai",
+		ConnectorDefinitionName: "connector-definitions/ai-openai",
+		Visibility:              "VISIBILITY_PUBLIC",
+		Configuration: map[string]interface{}{
+			"api_key": CredentialPlaceholder,
+			"org":     "${secrets/openai-org}",
+		},
+	}
+	if !m.HasCredentialPlaceholders() {
+		t.Fatal("the credential placeholder is not reported")
+	}
+
+	got, err := m.ToConnectorResource()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.GetVisibility() != connectorPB.ConnectorResource_VISIBILITY_PUBLIC {
+		t.Errorf("got visibility %s", got.GetVisibility())
+	}
+	want := map[string]interface{}{"api_key": utils.CredentialMaskString, "org": "${secrets/openai-org}"}
+	if !reflect.DeepEqual(got.GetConfiguration().AsMap(), want) {
+		t.Errorf("got configuration %v, want %v", got.GetConfiguration().AsMap(), want)
+	}
+	if m.Configuration["api_key"] != CredentialPlaceholder {
+		t.Error("the manifest is modified")
+	}
+
+	if _, err := (&Manifest{ID: "openai", Visibility: "VISIBILITY_UNKNOWN"}).ToConnectorResource(); err == nil {
+		t.Error("an unknown visibility is accepted")
+	}
+}
+
+func TestMarshalUnmarshal(t *testing.T) {
+	manifests := []*Manifest{
+		{ID: "b", ConnectorDefinitionName: "connector-definitions/ai-openai", Configuration: map[string]interface{}{"model": "gpt-4"}},
+		{ID: "a", ConnectorDefinitionName: "connector-definitions/data-pinecone", Description: "index"},
+	}
+
+	b, err := Marshal(manifests)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Unmarshal(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*Manifest{manifests[1], manifests[0]}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	testCases := []struct {
+		name    string
+		in      string
+		wantIDs []string
+		wantErr bool
+	}{
+		{
+			name:    "empty documents",
+			in:      "---\nid: a\nconnector_definition_name: connector-definitions/ai-openai\n---\n\n---\n",
+			wantIDs: []string{"a"},
+		},
+		{
+			name:    "several documents",
+			in:      "id: a\nconnector_definition_name: d\n---   \nid: b\nconnector_definition_name: d\n",
+			wantIDs: []string{"a", "b"},
+		},
+		{
+			name:    "missing id",
+			in:      "connector_definition_name: d\n",
+			wantErr: true,
+		},
+		{
+			name:    "missing connector definition",
+			in:      "id: a\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid yaml",
+			in:      "id: [a\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Unmarshal([]byte(tc.in))
+			if tc.wantErr {
+				if err == nil {
+					t.Error("no error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids := []string{}
+			for _, m := range got {
+				ids = append(ids, m.ID)
+			}
+			if !reflect.DeepEqual(ids, tc.wantIDs) {
+				t.Errorf("got %v, want %v", ids, tc.wantIDs)
+			}
+		})
+	}
+}
+
+func TestPlan(t *testing.T) {
+	definition := "connector-definitions/ai-openai"
+
+	testCases := []struct {
+		name    string
+		desired []*Manifest
+		live    []*Manifest
+		want    []*Change
+		wantErr bool
+	}{
+		{
+			name:    "create",
+			desired: []*Manifest{{ID: "a", ConnectorDefinitionName: definition}},
+			want:    []*Change{{Action: ActionCreate, ID: "a", Desired: &Manifest{ID: "a", ConnectorDefinitionName: definition}}},
+		},
+		{
+			name: "delete",
+			live: []*Manifest{{ID: "a", ConnectorDefinitionName: definition}},
+			want: []*Change{{Action: ActionDelete, ID: "a", Live: &Manifest{ID: "a", ConnectorDefinitionName: definition}}},
+		},
+		{
+			name: "unchanged",
+			desired: []*Manifest{{ID: "a", ConnectorDefinitionName: definition, Visibility: "VISIBILITY_PRIVATE",
+				Configuration: map[string]interface{}{"api_key": CredentialPlaceholder, "max_tokens": 100}}},
+			live: []*Manifest{{ID: "a", ConnectorDefinitionName: definition, Visibility: "VISIBILITY_PRIVATE",
+				Configuration: map[string]interface{}{"api_key": CredentialPlaceholder, "max_tokens": float64(100)}}},
+			want: []*Change{},
+		},
+		{
+			name:    "visibility left to the live value",
+			desired: []*Manifest{{ID: "a", ConnectorDefinitionName: definition}},
+			live:    []*Manifest{{ID: "a", ConnectorDefinitionName: definition, Visibility: "VISIBILITY_PUBLIC"}},
+			want:    []*Change{},
+		},
+		{
+			name: "update",
+			desired: []*Manifest{{ID: "a", ConnectorDefinitionName: definition, Description: "new", Visibility: "VISIBILITY_PUBLIC",
+				Configuration: map[string]interface{}{"model": "gpt-4"}}},
+			live: []*Manifest{{ID: "a", ConnectorDefinitionName: definition, Visibility: "VISIBILITY_PRIVATE",
+				Configuration: map[string]interface{}{"model": "gpt-3.5-turbo"}}},
+			want: []*Change{{
+				Action: ActionUpdate,
+				ID:     "a",
+				Desired: &Manifest{ID: "a", ConnectorDefinitionName: definition, Description: "new", Visibility: "VISIBILITY_PUBLIC",
+					Configuration: map[string]interface{}{"model": "gpt-4"}},
+				Live: &Manifest{ID: "a", ConnectorDefinitionName: definition, Visibility: "VISIBILITY_PRIVATE",
+					Configuration: map[string]interface{}{"model": "gpt-3.5-turbo"}},
+				Fields: []string{"description", "visibility", "configuration"},
+			}},
+		},
+		{
+			name: "credential value",
+			desired: []*Manifest{{ID: "a", ConnectorDefinitionName: definition,
+				Configuration: map[string]interface{}{"api_key": "sk-123", "auth": map[string]interface{}{"token": "t"}}}},
+			live: []*Manifest{{ID: "a", ConnectorDefinitionName: definition,
+				Configuration: map[string]interface{}{"api_key": CredentialPlaceholder, "auth": map[string]interface{}{"token": CredentialPlaceholder}}}},
+			want: []*Change{{
+				Action: ActionUnverified,
+				ID:     "a",
+				Desired: &Manifest{ID: "a", ConnectorDefinitionName: definition,
+					Configuration: map[string]interface{}{"api_key": "sk-123", "auth": map[string]interface{}{"token": "t"}}},
+				Live: &Manifest{ID: "a", ConnectorDefinitionName: definition,
+					Configuration: map[string]interface{}{"api_key": CredentialPlaceholder, "auth": map[string]interface{}{"token": CredentialPlaceholder}}},
+				Unverified: []string{"configuration.api_key", "configuration.auth.token"},
+			}},
+		},
+		{
+			name: "credential value with an update",
+			desired: []*Manifest{{ID: "a", ConnectorDefinitionName: definition, Description: "new",
+				Configuration: map[string]interface{}{"api_key": "sk-123"}}},
+			live: []*Manifest{{ID: "a", ConnectorDefinitionName: definition,
+				Configuration: map[string]interface{}{"api_key": CredentialPlaceholder}}},
+			want: []*Change{{
+				Action: ActionUpdate,
+				ID:     "a",
+				Desired: &Manifest{ID: "a", ConnectorDefinitionName: definition, Description: "new",
+					Configuration: map[string]interface{}{"api_key": "sk-123"}},
+				Live: &Manifest{ID: "a", ConnectorDefinitionName: definition,
+					Configuration: map[string]interface{}{"api_key": CredentialPlaceholder}},
+				Fields:     []string{"description"},
+				Unverified: []string{"configuration.api_key"},
+			}},
+		},
+		{
+			name: "secret reference replacing a credential value",
+			desired: []*Manifest{{ID: "a", ConnectorDefinitionName: definition,
+				Configuration: map[string]interface{}{"api_key": "${secrets/openai-key}"}}},
+			live: []*Manifest{{ID: "a", ConnectorDefinitionName: definition,
+				Configuration: map[string]interface{}{"api_key": CredentialPlaceholder}}},
+			want: []*Change{{
+				Action: ActionUpdate,
+				ID:     "a",
+				Desired: &Manifest{ID: "a", ConnectorDefinitionName: definition,
+					Configuration: map[string]interface{}{"api_key": "${secrets/openai-key}"}},
+				Live: &Manifest{ID: "a", ConnectorDefinitionName: definition,
+					Configuration: map[string]interface{}{"api_key": CredentialPlaceholder}},
+				Fields: []string{"configuration"},
+			}},
+		},
+		{
+			name: "same secret reference",
+			desired: []*Manifest{{ID: "a", ConnectorDefinitionName: definition,
+				Configuration: map[string]interface{}{"api_key": "${secrets/openai-key}"}}},
+			live: []*Manifest{{ID: "a", ConnectorDefinitionName: definition,
+				Configuration: map[string]interface{}{"api_key": "${secrets/openai-key}"}}},
+			want: []*Change{},
+		},
+		{
+			name: "credential value replacing a secret reference",
+			desired: []*Manifest{{ID: "a", ConnectorDefinitionName: definition,
+				Configuration: map[string]interface{}{"api_key": "sk-123"}}},
+			live: []*Manifest{{ID: "a", ConnectorDefinitionName: definition,
+				Configuration: map[string]interface{}{"api_key": "${secrets/openai-key}"}}},
+			want: []*Change{{
+				Action: ActionUpdate,
+				ID:     "a",
+				Desired: &Manifest{ID: "a", ConnectorDefinitionName: definition,
+					Configuration: map[string]interface{}{"api_key": "sk-123"}},
+				Live: &Manifest{ID: "a", ConnectorDefinitionName: definition,
+					Configuration: map[string]interface{}{"api_key": "${secrets/openai-key}"}},
+				Fields: []string{"configuration"},
+			}},
+		},
+		{
+			name:    "sorted by id",
+			desired: []*Manifest{{ID: "c", ConnectorDefinitionName: definition}, {ID: "a", ConnectorDefinitionName: definition}},
+			live:    []*Manifest{{ID: "b", ConnectorDefinitionName: definition}},
+			want: []*Change{
+				{Action: ActionCreate, ID: "a", Desired: &Manifest{ID: "a", ConnectorDefinitionName: definition}},
+				{Action: ActionDelete, ID: "b", Live: &Manifest{ID: "b", ConnectorDefinitionName: definition}},
+				{Action: ActionCreate, ID: "c", Desired: &Manifest{ID: "c", ConnectorDefinitionName: definition}},
+			},
+		},
+		{
+			name:    "duplicate manifests",
+			desired: []*Manifest{{ID: "a", ConnectorDefinitionName: definition}, {ID: "a", ConnectorDefinitionName: definition}},
+			wantErr: true,
+		},
+		{
+			name:    "connector definition changed",
+			desired: []*Manifest{{ID: "a", ConnectorDefinitionName: "connector-definitions/ai-stability-ai"}},
+			live:    []*Manifest{{ID: "a", ConnectorDefinitionName: definition}},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Plan(tc.desired, tc.live)
+			if tc.wantErr {
+				if err == nil {
+					t.Error("no error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
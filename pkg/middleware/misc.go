@@ -4,12 +4,14 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"net/textproto"
 	"strconv"
 	"strings"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/instill-ai/connector-backend/pkg/constant"
 	"github.com/instill-ai/connector-backend/pkg/logger"
 	"go.opentelemetry.io/otel"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
@@ -68,6 +70,15 @@ func ErrorHandler(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.
 		w.Header().Set("WWW-Authenticate", s.Message())
 	}
 
+	// A rate limited request tells the client when to retry
+	if s.Code() == codes.ResourceExhausted {
+		for _, detail := range s.Details() {
+			if v, ok := detail.(*errdetails.RetryInfo); ok {
+				w.Header().Set("Retry-After", strconv.FormatInt(int64(math.Ceil(v.GetRetryDelay().AsDuration().Seconds())), 10))
+			}
+		}
+	}
+
 	buf, err := marshaler.Marshal(pb)
 	if err != nil {
 		logger.Error(fmt.Sprintf("Failed to marshal error message %q: %v", s, err))
@@ -123,6 +134,9 @@ func ErrorHandler(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.
 	default:
 		httpStatus = runtime.HTTPStatusFromCode(s.Code())
 	}
+	if httpStatus == 0 {
+		httpStatus = runtime.HTTPStatusFromCode(s.Code())
+	}
 
 	w.WriteHeader(httpStatus)
 	if _, err := w.Write(buf); err != nil {
@@ -145,6 +159,10 @@ func CustomMatcher(key string) (string, bool) {
 		return key, true
 	}
 
+	if strings.ToLower(key) == constant.HeaderIdempotencyKey || strings.ToLower(key) == constant.HeaderValidateOnly {
+		return key, true
+	}
+
 	switch key {
 	case "request-id":
 		return key, true
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"google.golang.org/grpc/codes"
+	"gorm.io/gorm/clause"
+
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/x/sterr"
+)
+
+// UpsertConnectorRoleBinding grants the role to the subject, replacing the role it already has on the connector
+func (r *repository) UpsertConnectorRoleBinding(ctx context.Context, binding *datamodel.ConnectorRoleBinding) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	binding.UpdateTime = time.Now()
+	if result := r.db.Model(&datamodel.ConnectorRoleBinding{}).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "connector_uid"}, {Name: "subject"}},
+			DoUpdates: clause.AssignmentColumns([]string{"role", "update_time"}),
+		}).
+		Create(binding); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] upsert connector role binding error: %s", result.Error.Error()),
+			"connector_role_binding",
+			fmt.Sprintf("subject %s", binding.Subject),
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	}
+	return nil
+}
+
+func (r *repository) ListConnectorRoleBindings(ctx context.Context, connUID uuid.UUID) ([]*datamodel.ConnectorRoleBinding, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	var bindings []*datamodel.ConnectorRoleBinding
+	if result := r.db.Model(&datamodel.ConnectorRoleBinding{}).
+		Where("connector_uid = ?", connUID).
+		Order("create_time ASC").
+		Find(&bindings); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] list connector role binding error: %s", result.Error.Error()),
+			"connector_role_binding",
+			fmt.Sprintf("connector uid %s", connUID),
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, st.Err()
+	}
+	return bindings, nil
+}
+
+func (r *repository) GetConnectorRoleBinding(ctx context.Context, connUID uuid.UUID, subject string) (*datamodel.ConnectorRoleBinding, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	var binding datamodel.ConnectorRoleBinding
+	if result := r.db.Model(&datamodel.ConnectorRoleBinding{}).
+		Where("(connector_uid = ? AND subject = ?)", connUID, subject).
+		First(&binding); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.NotFound,
+			fmt.Sprintf("[db] get connector role binding error: %s", result.Error.Error()),
+			"connector_role_binding",
+			fmt.Sprintf("subject %s", subject),
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, st.Err()
+	}
+	return &binding, nil
+}
+
+func (r *repository) DeleteConnectorRoleBinding(ctx context.Context, connUID uuid.UUID, subject string) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	result := r.db.Model(&datamodel.ConnectorRoleBinding{}).
+		Where("(connector_uid = ? AND subject = ?)", connUID, subject).
+		Delete(&datamodel.ConnectorRoleBinding{})
+
+	if result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] delete connector role binding error: %s", result.Error.Error()),
+			"connector_role_binding",
+			fmt.Sprintf("subject %s", subject),
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	}
+
+	if result.RowsAffected == 0 {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.NotFound,
+			fmt.Sprintf("[db] delete connector role binding error: %s", "Not found"),
+			"connector_role_binding",
+			fmt.Sprintf("subject %s", subject),
+			"",
+			"Not found",
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"google.golang.org/grpc/codes"
+
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/x/sterr"
+)
+
+func (r *repository) CreateConnectorCapture(ctx context.Context, capture *datamodel.ConnectorCapture) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	if result := r.db.Model(&datamodel.ConnectorCapture{}).Create(capture); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] create connector capture error: %s", result.Error.Error()),
+			"connector_capture",
+			fmt.Sprintf("uid %s", capture.UID),
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	}
+	return nil
+}
+
+// GetConnectorCapture returns the capture of an execution of the connector unless it has expired
+func (r *repository) GetConnectorCapture(ctx context.Context, connUID uuid.UUID, uid uuid.UUID) (*datamodel.ConnectorCapture, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	var capture datamodel.ConnectorCapture
+	if result := r.db.Model(&datamodel.ConnectorCapture{}).
+		Where("(uid = ? AND connector_uid = ? AND expire_time > ?)", uid, connUID, time.Now()).
+		First(&capture); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.NotFound,
+			fmt.Sprintf("[db] get connector capture error: %s", result.Error.Error()),
+			"connector_capture",
+			fmt.Sprintf("uid %s", uid),
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, st.Err()
+	}
+	return &capture, nil
+}
+
+// PurgeConnectorCaptures deletes the captures expired before {before}
+func (r *repository) PurgeConnectorCaptures(ctx context.Context, before time.Time) (int64, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	result := r.db.Where("expire_time < ?", before).Delete(&datamodel.ConnectorCapture{})
+	if result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] purge connector capture error: %s", result.Error.Error()),
+			"connector_capture",
+			"",
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return 0, st.Err()
+	}
+	return result.RowsAffected, nil
+}
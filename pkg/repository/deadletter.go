@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"google.golang.org/grpc/codes"
+
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/x/sterr"
+)
+
+func (r *repository) CreateConnectorDeadLetter(ctx context.Context, deadLetter *datamodel.ConnectorDeadLetter) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	if result := r.db.Model(&datamodel.ConnectorDeadLetter{}).Create(deadLetter); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] create connector dead letter error: %s", result.Error.Error()),
+			"connector_dead_letter",
+			fmt.Sprintf("uid %s", deadLetter.UID),
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	}
+	return nil
+}
+
+// GetConnectorDeadLetter returns the dead letter of a failed execution of the connector unless it has expired
+func (r *repository) GetConnectorDeadLetter(ctx context.Context, connUID uuid.UUID, uid uuid.UUID) (*datamodel.ConnectorDeadLetter, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	var deadLetter datamodel.ConnectorDeadLetter
+	if result := r.db.Model(&datamodel.ConnectorDeadLetter{}).
+		Where("(uid = ? AND connector_uid = ? AND expire_time > ?)", uid, connUID, time.Now()).
+		First(&deadLetter); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.NotFound,
+			fmt.Sprintf("[db] get connector dead letter error: %s", result.Error.Error()),
+			"connector_dead_letter",
+			fmt.Sprintf("uid %s", uid),
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, st.Err()
+	}
+	return &deadLetter, nil
+}
+
+// ListConnectorDeadLetters returns the oldest {limit} unexpired dead letters of the connector created after {since}
+func (r *repository) ListConnectorDeadLetters(ctx context.Context, connUID uuid.UUID, since time.Time, limit int) (deadLetters []*datamodel.ConnectorDeadLetter, err error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	if result := r.db.Model(&datamodel.ConnectorDeadLetter{}).
+		Where("(connector_uid = ? AND create_time > ? AND expire_time > ?)", connUID, since, time.Now()).
+		Order("create_time ASC, uid ASC").
+		Limit(limit).
+		Find(&deadLetters); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] list connector dead letter error: %s", result.Error.Error()),
+			"connector_dead_letter",
+			fmt.Sprintf("connector uid %s", connUID),
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, st.Err()
+	}
+	return deadLetters, nil
+}
+
+func (r *repository) DeleteConnectorDeadLetter(ctx context.Context, uid uuid.UUID) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	if result := r.db.Where("uid = ?", uid).Delete(&datamodel.ConnectorDeadLetter{}); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] delete connector dead letter error: %s", result.Error.Error()),
+			"connector_dead_letter",
+			fmt.Sprintf("uid %s", uid),
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	}
+	return nil
+}
+
+// PurgeConnectorDeadLetters deletes the dead letters expired before {before}
+func (r *repository) PurgeConnectorDeadLetters(ctx context.Context, before time.Time) (int64, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	result := r.db.Where("expire_time < ?", before).Delete(&datamodel.ConnectorDeadLetter{})
+	if result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] purge connector dead letter error: %s", result.Error.Error()),
+			"connector_dead_letter",
+			"",
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return 0, st.Err()
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"go.einride.tech/aip/filtering"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/x/paginate"
+	"github.com/instill-ai/x/sterr"
+)
+
+func (r *repository) CreateConnectorExecution(ctx context.Context, execution *datamodel.ConnectorExecution) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	if result := r.db.Model(&datamodel.ConnectorExecution{}).Create(execution); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] create connector execution error: %s", result.Error.Error()),
+			"connector_execution",
+			fmt.Sprintf("uid %s", execution.UID),
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	}
+	return nil
+}
+
+func (r *repository) ListConnectorExecutions(ctx context.Context, connUID uuid.UUID, pageSize int64, pageToken string, filter filtering.Filter) (executions []*datamodel.ConnectorExecution, totalSize int64, nextPageToken string, err error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	where := "connector_uid = ?"
+	whereArgs := []interface{}{connUID}
+
+	expr, err := r.transpileFilter(filter)
+	if err != nil {
+		return nil, 0, "", status.Errorf(codes.Internal, err.Error())
+	}
+	if expr != nil {
+		where = fmt.Sprintf("((%s) AND ?)", where)
+		whereArgs = append(whereArgs, expr)
+	}
+
+	r.db.Model(&datamodel.ConnectorExecution{}).Where(where, whereArgs...).Count(&totalSize)
+
+	queryBuilder := r.db.Model(&datamodel.ConnectorExecution{}).Order("create_time DESC, uid DESC").Where(where, whereArgs...)
+
+	if pageSize == 0 {
+		pageSize = DefaultPageSize
+	} else if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+
+	queryBuilder = queryBuilder.Limit(int(pageSize))
+
+	if pageToken != "" {
+		createdAt, uid, err := paginate.DecodeToken(pageToken)
+		if err != nil {
+			st, err := sterr.CreateErrorBadRequest(
+				fmt.Sprintf("[db] list connector execution error: %s", err.Error()),
+				[]*errdetails.BadRequest_FieldViolation{
+					{
+						Field:       "page_token",
+						Description: fmt.Sprintf("Invalid page token: %s", err.Error()),
+					},
+				},
+			)
+			if err != nil {
+				logger.Error(err.Error())
+			}
+			return nil, 0, "", st.Err()
+		}
+
+		queryBuilder = queryBuilder.Where("(create_time,uid) < (?::timestamp, ?)", createdAt, uid)
+	}
+
+	if result := queryBuilder.Find(&executions); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] list connector execution error: %s", result.Error.Error()),
+			"connector_execution",
+			fmt.Sprintf("connector uid %s", connUID),
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, 0, "", st.Err()
+	}
+
+	if len(executions) > 0 {
+		last := executions[len(executions)-1]
+		oldest := &datamodel.ConnectorExecution{}
+		if result := r.db.Model(&datamodel.ConnectorExecution{}).
+			Where(where, whereArgs...).
+			Order("create_time ASC, uid ASC").Limit(1).Find(oldest); result.Error != nil {
+			st, err := sterr.CreateErrorResourceInfo(
+				codes.Internal,
+				fmt.Sprintf("[db] list connector execution error: %s", result.Error.Error()),
+				"connector_execution",
+				fmt.Sprintf("connector uid %s", connUID),
+				"",
+				result.Error.Error(),
+			)
+			if err != nil {
+				logger.Error(err.Error())
+			}
+			return nil, 0, "", st.Err()
+		}
+		if oldest.UID != last.UID {
+			nextPageToken = paginate.EncodeToken(last.CreateTime, last.UID.String())
+		}
+	}
+
+	return executions, totalSize, nextPageToken, nil
+}
+
+func (r *repository) GetConnectorExecution(ctx context.Context, connUID uuid.UUID, uid uuid.UUID) (*datamodel.ConnectorExecution, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	var execution datamodel.ConnectorExecution
+	if result := r.db.Model(&datamodel.ConnectorExecution{}).
+		Where("(uid = ? AND connector_uid = ?)", uid, connUID).
+		First(&execution); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.NotFound,
+			fmt.Sprintf("[db] get connector execution error: %s", result.Error.Error()),
+			"connector_execution",
+			fmt.Sprintf("uid %s", uid),
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, st.Err()
+	}
+	return &execution, nil
+}
+
+// PurgeConnectorExecutions deletes the executions recorded before {before}
+func (r *repository) PurgeConnectorExecutions(ctx context.Context, before time.Time) (int64, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	result := r.db.Where("create_time < ?", before).Delete(&datamodel.ConnectorExecution{})
+	if result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] purge connector execution error: %s", result.Error.Error()),
+			"connector_execution",
+			"",
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return 0, st.Err()
+	}
+	return result.RowsAffected, nil
+}
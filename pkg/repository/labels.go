@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"gorm.io/gorm"
+
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/x/sterr"
+)
+
+// UpdateUserConnectorResourceLabelsByID sets and removes labels of the connector in a single statement so that
+// concurrent updates of different labels are all kept. If replace is set, the labels are replaced by set.
+func (r *repository) UpdateUserConnectorResourceLabelsByID(ctx context.Context, ownerPermalink string, userPermalink string, id string, set map[string]string, remove []string, replace bool) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	if set == nil {
+		set = map[string]string{}
+	}
+	b, err := json.Marshal(set)
+	if err != nil {
+		return err
+	}
+	if remove == nil {
+		remove = []string{}
+	}
+
+	labels := gorm.Expr("(labels - ?::text[]) || ?::jsonb", remove, string(b))
+	if replace {
+		labels = gorm.Expr("?::jsonb", string(b))
+	}
+
+	if result := r.db.Model(&datamodel.ConnectorResource{}).
+		Where("(id = ? AND owner = ? AND ? = ?)", id, ownerPermalink, ownerPermalink, userPermalink).
+		Update("labels", labels); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] update connector labels by id error: %s", result.Error.Error()),
+			"connector",
+			"",
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	} else if result.RowsAffected == 0 {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.NotFound,
+			fmt.Sprintf("[db] update connector labels by id error: %s", "Not found"),
+			"connector",
+			"",
+			"",
+			"Not found",
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gofrs/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/x/paginate"
+	"github.com/instill-ai/x/sterr"
+)
+
+func (r *repository) CreateConnectorOperation(ctx context.Context, operation *datamodel.ConnectorOperation) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	if result := r.db.Model(&datamodel.ConnectorOperation{}).Create(operation); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] create connector operation error: %s", result.Error.Error()),
+			"connector_operation",
+			fmt.Sprintf("connector uid %s", operation.ConnectorUID),
+			operation.Owner,
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	}
+	return nil
+}
+
+func (r *repository) ListConnectorOperations(ctx context.Context, owner string, pageSize int64, pageToken string) (operations []*datamodel.ConnectorOperation, totalSize int64, nextPageToken string, err error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	r.db.Model(&datamodel.ConnectorOperation{}).Where("owner = ?", owner).Count(&totalSize)
+
+	queryBuilder := r.db.Model(&datamodel.ConnectorOperation{}).Order("create_time DESC, uid DESC").Where("owner = ?", owner)
+
+	if pageSize == 0 {
+		pageSize = DefaultPageSize
+	} else if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+
+	queryBuilder = queryBuilder.Limit(int(pageSize))
+
+	if pageToken != "" {
+		createdAt, uid, err := paginate.DecodeToken(pageToken)
+		if err != nil {
+			st, err := sterr.CreateErrorBadRequest(
+				fmt.Sprintf("[db] list connector operation error: %s", err.Error()),
+				[]*errdetails.BadRequest_FieldViolation{
+					{
+						Field:       "page_token",
+						Description: fmt.Sprintf("Invalid page token: %s", err.Error()),
+					},
+				},
+			)
+			if err != nil {
+				logger.Error(err.Error())
+			}
+			return nil, 0, "", st.Err()
+		}
+
+		queryBuilder = queryBuilder.Where("(create_time,uid) < (?::timestamp, ?)", createdAt, uid)
+	}
+
+	if result := queryBuilder.Find(&operations); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] list connector operation error: %s", result.Error.Error()),
+			"connector_operation",
+			"",
+			owner,
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, 0, "", st.Err()
+	}
+
+	if len(operations) > 0 {
+		last := operations[len(operations)-1]
+		oldest := &datamodel.ConnectorOperation{}
+		if result := r.db.Model(&datamodel.ConnectorOperation{}).
+			Where("owner = ?", owner).
+			Order("create_time ASC, uid ASC").Limit(1).Find(oldest); result.Error != nil {
+			st, err := sterr.CreateErrorResourceInfo(
+				codes.Internal,
+				fmt.Sprintf("[db] list connector operation error: %s", result.Error.Error()),
+				"connector_operation",
+				"",
+				owner,
+				result.Error.Error(),
+			)
+			if err != nil {
+				logger.Error(err.Error())
+			}
+			return nil, 0, "", st.Err()
+		}
+		if oldest.UID != last.UID {
+			nextPageToken = paginate.EncodeToken(last.CreateTime, last.UID.String())
+		}
+	}
+
+	return operations, totalSize, nextPageToken, nil
+}
+
+func (r *repository) GetConnectorOperation(ctx context.Context, owner string, uid uuid.UUID) (*datamodel.ConnectorOperation, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	var operation datamodel.ConnectorOperation
+	if result := r.db.Model(&datamodel.ConnectorOperation{}).
+		Where("(uid = ? AND owner = ?)", uid, owner).
+		First(&operation); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.NotFound,
+			fmt.Sprintf("[db] get connector operation error: %s", result.Error.Error()),
+			"connector_operation",
+			fmt.Sprintf("uid %s", uid),
+			owner,
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, st.Err()
+	}
+	return &operation, nil
+}
+
+// TransitConnectorOperation applies the updates if the operation is still in one of the {from} states, it
+// reports whether the operation has been updated so that concurrent transitions do not overwrite each other
+func (r *repository) TransitConnectorOperation(ctx context.Context, uid uuid.UUID, from []datamodel.ConnectorOperationState, updates map[string]interface{}) (bool, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	result := r.db.Model(&datamodel.ConnectorOperation{}).
+		Where("(uid = ? AND state IN ?)", uid, from).
+		Updates(updates)
+	if result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] update connector operation error: %s", result.Error.Error()),
+			"connector_operation",
+			fmt.Sprintf("uid %s", uid),
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return false, st.Err()
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// TransitWorkerConnectorOperations applies the updates to the operations of the worker that are still in one of
+// the {from} states
+func (r *repository) TransitWorkerConnectorOperations(ctx context.Context, worker string, from []datamodel.ConnectorOperationState, updates map[string]interface{}) (int64, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	result := r.db.Model(&datamodel.ConnectorOperation{}).
+		Where("(worker = ? AND state IN ?)", worker, from).
+		Updates(updates)
+	if result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] update connector operation error: %s", result.Error.Error()),
+			"connector_operation",
+			fmt.Sprintf("worker %s", worker),
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return 0, st.Err()
+	}
+	return result.RowsAffected, nil
+}
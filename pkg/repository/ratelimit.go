@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"gorm.io/gorm/clause"
+
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/x/sterr"
+)
+
+// UpsertRateLimit sets the rate limit of the subject, replacing the one it already has in the scope
+func (r *repository) UpsertRateLimit(ctx context.Context, limit *datamodel.RateLimit) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	limit.UpdateTime = time.Now()
+	if result := r.db.Model(&datamodel.RateLimit{}).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "scope"}, {Name: "subject"}},
+			DoUpdates: clause.AssignmentColumns([]string{"rate", "burst", "update_time"}),
+		}).
+		Create(limit); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] upsert rate limit error: %s", result.Error.Error()),
+			"rate_limit",
+			fmt.Sprintf("%s %s", limit.Scope, limit.Subject),
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	}
+	return nil
+}
+
+func (r *repository) GetRateLimit(ctx context.Context, scope datamodel.RateLimitScope, subject string) (*datamodel.RateLimit, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	var limit datamodel.RateLimit
+	if result := r.db.Model(&datamodel.RateLimit{}).
+		Where("(scope = ? AND subject = ?)", scope, subject).
+		First(&limit); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.NotFound,
+			fmt.Sprintf("[db] get rate limit error: %s", result.Error.Error()),
+			"rate_limit",
+			fmt.Sprintf("%s %s", scope, subject),
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, st.Err()
+	}
+	return &limit, nil
+}
+
+// ListRateLimits returns the rate limits set on the subject of each scope
+func (r *repository) ListRateLimits(ctx context.Context, subjects map[datamodel.RateLimitScope]string) ([]*datamodel.RateLimit, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	if len(subjects) == 0 {
+		return nil, nil
+	}
+
+	queryBuilder := r.db.Model(&datamodel.RateLimit{})
+	conditions := r.db
+	for scope, subject := range subjects {
+		conditions = conditions.Or("(scope = ? AND subject = ?)", scope, subject)
+	}
+
+	var limits []*datamodel.RateLimit
+	if result := queryBuilder.Where(conditions).Find(&limits); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] list rate limit error: %s", result.Error.Error()),
+			"rate_limit",
+			"",
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, st.Err()
+	}
+	return limits, nil
+}
+
+func (r *repository) DeleteRateLimit(ctx context.Context, scope datamodel.RateLimitScope, subject string) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	result := r.db.Model(&datamodel.RateLimit{}).
+		Where("(scope = ? AND subject = ?)", scope, subject).
+		Delete(&datamodel.RateLimit{})
+
+	if result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] delete rate limit error: %s", result.Error.Error()),
+			"rate_limit",
+			fmt.Sprintf("%s %s", scope, subject),
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	}
+
+	if result.RowsAffected == 0 {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.NotFound,
+			fmt.Sprintf("[db] delete rate limit error: %s", "Not found"),
+			"rate_limit",
+			fmt.Sprintf("%s %s", scope, subject),
+			"",
+			"Not found",
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	}
+
+	return nil
+}
@@ -12,6 +12,7 @@ import (
 	"go.einride.tech/aip/filtering"
 	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 
@@ -31,9 +32,16 @@ const MaxPageSize = 100
 
 const VisibilityPublic = datamodel.ConnectorResourceVisibility(connectorPB.ConnectorResource_VISIBILITY_PUBLIC)
 
+// roleBindingCondition matches the connectors on which a role is granted to the user
+const roleBindingCondition = "uid IN (SELECT connector_uid FROM connector_role_binding WHERE subject = ?)"
+
 // Repository interface
 type Repository interface {
 
+	// Transaction runs fn with a repository bound to a database transaction which is committed if fn returns nil,
+	// a transaction run from a transaction repository is nested in it with a savepoint
+	Transaction(ctx context.Context, fn func(repo Repository) error) error
+
 	// List all connector resources visible to the user
 	ListConnectorResources(ctx context.Context, userPermalink string, pageSize int64, pageToken string, isBasicView bool, filter filtering.Filter, showDeleted bool) ([]*datamodel.ConnectorResource, int64, string, error)
 	GetConnectorResourceByUID(ctx context.Context, userPermalink string, uid uuid.UUID, isBasicView bool) (*datamodel.ConnectorResource, error)
@@ -46,6 +54,67 @@ type Repository interface {
 	DeleteUserConnectorResourceByID(ctx context.Context, ownerPermalink string, userPermalink string, id string) error
 	UpdateUserConnectorResourceIDByID(ctx context.Context, ownerPermalink string, userPermalink string, id string, newID string) error
 	UpdateUserConnectorResourceStateByID(ctx context.Context, ownerPermalink string, userPermalink string, id string, state datamodel.ConnectorResourceState) error
+	UpdateUserConnectorResourceRetryPolicyByID(ctx context.Context, ownerPermalink string, userPermalink string, id string, retryPolicy datatypes.JSON) error
+	UpdateUserConnectorResourceMaxConcurrencyByID(ctx context.Context, ownerPermalink string, userPermalink string, id string, maxConcurrency int32) error
+	UpdateUserConnectorResourceCacheTTLByID(ctx context.Context, ownerPermalink string, userPermalink string, id string, cacheTTL int32) error
+	UpdateUserConnectorResourceDebugCaptureByID(ctx context.Context, ownerPermalink string, userPermalink string, id string, debugCapture bool) error
+	UpdateUserConnectorResourceLabelsByID(ctx context.Context, ownerPermalink string, userPermalink string, id string, set map[string]string, remove []string, replace bool) error
+
+	// Soft-deleted connector resources under {ownerPermalink} namespace
+	GetUserDeletedConnectorResourceByID(ctx context.Context, ownerPermalink string, id string) (*datamodel.ConnectorResource, error)
+	UndeleteConnectorResourceByUID(ctx context.Context, uid uuid.UUID) error
+	PurgeDeletedConnectorResources(ctx context.Context, before time.Time) (int64, error)
+
+	// Secrets under {ownerPermalink} namespace, only accessible by the owner
+	CreateUserSecret(ctx context.Context, ownerPermalink string, userPermalink string, secret *datamodel.Secret) error
+	ListUserSecrets(ctx context.Context, ownerPermalink string, userPermalink string, pageSize int64, pageToken string) ([]*datamodel.Secret, int64, string, error)
+	GetUserSecretByID(ctx context.Context, ownerPermalink string, userPermalink string, id string) (*datamodel.Secret, error)
+	UpdateUserSecretByID(ctx context.Context, ownerPermalink string, userPermalink string, id string, secret *datamodel.Secret) error
+	DeleteUserSecretByID(ctx context.Context, ownerPermalink string, userPermalink string, id string) error
+	ListUserConnectorResourceIDsBySecretReference(ctx context.Context, ownerPermalink string, reference string) ([]string, error)
+
+	// Configuration history of a connector resource
+	CreateConnectorRevision(ctx context.Context, revision *datamodel.ConnectorRevision) error
+	ListConnectorRevisions(ctx context.Context, connUID uuid.UUID, pageSize int64, pageToken string) ([]*datamodel.ConnectorRevision, int64, string, error)
+	GetConnectorRevision(ctx context.Context, connUID uuid.UUID, revision int32) (*datamodel.ConnectorRevision, error)
+	ListConnectorRevisionsBetween(ctx context.Context, connUID uuid.UUID, from int32, to int32) ([]*datamodel.ConnectorRevision, error)
+
+	// Role bindings granting roles on a connector resource
+	UpsertConnectorRoleBinding(ctx context.Context, binding *datamodel.ConnectorRoleBinding) error
+	ListConnectorRoleBindings(ctx context.Context, connUID uuid.UUID) ([]*datamodel.ConnectorRoleBinding, error)
+	GetConnectorRoleBinding(ctx context.Context, connUID uuid.UUID, subject string) (*datamodel.ConnectorRoleBinding, error)
+	DeleteConnectorRoleBinding(ctx context.Context, connUID uuid.UUID, subject string) error
+
+	// Rate limits of the connector executions
+	UpsertRateLimit(ctx context.Context, limit *datamodel.RateLimit) error
+	GetRateLimit(ctx context.Context, scope datamodel.RateLimitScope, subject string) (*datamodel.RateLimit, error)
+	ListRateLimits(ctx context.Context, subjects map[datamodel.RateLimitScope]string) ([]*datamodel.RateLimit, error)
+	DeleteRateLimit(ctx context.Context, scope datamodel.RateLimitScope, subject string) error
+
+	// Asynchronous executions requested by {owner}
+	CreateConnectorOperation(ctx context.Context, operation *datamodel.ConnectorOperation) error
+	ListConnectorOperations(ctx context.Context, owner string, pageSize int64, pageToken string) ([]*datamodel.ConnectorOperation, int64, string, error)
+	GetConnectorOperation(ctx context.Context, owner string, uid uuid.UUID) (*datamodel.ConnectorOperation, error)
+	TransitConnectorOperation(ctx context.Context, uid uuid.UUID, from []datamodel.ConnectorOperationState, updates map[string]interface{}) (bool, error)
+	TransitWorkerConnectorOperations(ctx context.Context, worker string, from []datamodel.ConnectorOperationState, updates map[string]interface{}) (int64, error)
+
+	// History of the executions of a connector resource
+	CreateConnectorExecution(ctx context.Context, execution *datamodel.ConnectorExecution) error
+	ListConnectorExecutions(ctx context.Context, connUID uuid.UUID, pageSize int64, pageToken string, filter filtering.Filter) ([]*datamodel.ConnectorExecution, int64, string, error)
+	GetConnectorExecution(ctx context.Context, connUID uuid.UUID, uid uuid.UUID) (*datamodel.ConnectorExecution, error)
+	PurgeConnectorExecutions(ctx context.Context, before time.Time) (int64, error)
+
+	// Payloads captured from the executions of a connector resource
+	CreateConnectorCapture(ctx context.Context, capture *datamodel.ConnectorCapture) error
+	GetConnectorCapture(ctx context.Context, connUID uuid.UUID, uid uuid.UUID) (*datamodel.ConnectorCapture, error)
+	PurgeConnectorCaptures(ctx context.Context, before time.Time) (int64, error)
+
+	// Inputs of the failed executions of a connector resource kept to be replayed
+	CreateConnectorDeadLetter(ctx context.Context, deadLetter *datamodel.ConnectorDeadLetter) error
+	GetConnectorDeadLetter(ctx context.Context, connUID uuid.UUID, uid uuid.UUID) (*datamodel.ConnectorDeadLetter, error)
+	ListConnectorDeadLetters(ctx context.Context, connUID uuid.UUID, since time.Time, limit int) ([]*datamodel.ConnectorDeadLetter, error)
+	DeleteConnectorDeadLetter(ctx context.Context, uid uuid.UUID) error
+	PurgeConnectorDeadLetters(ctx context.Context, before time.Time) (int64, error)
 
 	// Operations Admin
 	ListConnectorResourcesAdmin(ctx context.Context, pageSize int64, pageToken string, isBasicView bool, filter filtering.Filter, showDeleted bool) ([]*datamodel.ConnectorResource, int64, string, error)
@@ -63,6 +132,12 @@ func NewRepository(db *gorm.DB) Repository {
 	}
 }
 
+func (r *repository) Transaction(ctx context.Context, fn func(repo Repository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&repository{db: tx})
+	})
+}
+
 func (r *repository) listConnectorResources(ctx context.Context, where string, whereArgs []interface{}, pageSize int64, pageToken string, isBasicView bool, filter filtering.Filter, showDeleted bool) (connectors []*datamodel.ConnectorResource, totalSize int64, nextPageToken string, err error) {
 
 	db := r.db
@@ -199,8 +274,8 @@ func (r *repository) ListConnectorResourcesAdmin(ctx context.Context, pageSize i
 func (r *repository) ListConnectorResources(ctx context.Context, userPermalink string, pageSize int64, pageToken string, isBasicView bool, filter filtering.Filter, showDeleted bool) (connectors []*datamodel.ConnectorResource, totalSize int64, nextPageToken string, err error) {
 
 	return r.listConnectorResources(ctx,
-		"(owner = ? OR visibility = ?)",
-		[]interface{}{userPermalink, VisibilityPublic},
+		fmt.Sprintf("(owner = ? OR visibility = ? OR %s)", roleBindingCondition),
+		[]interface{}{userPermalink, VisibilityPublic, userPermalink},
 		pageSize, pageToken, isBasicView, filter, showDeleted)
 
 }
@@ -208,8 +283,8 @@ func (r *repository) ListConnectorResources(ctx context.Context, userPermalink s
 func (r *repository) ListUserConnectorResources(ctx context.Context, ownerPermalink string, userPermalink string, pageSize int64, pageToken string, isBasicView bool, filter filtering.Filter, showDeleted bool) (connectors []*datamodel.ConnectorResource, totalSize int64, nextPageToken string, err error) {
 
 	return r.listConnectorResources(ctx,
-		"(owner = ? AND (visibility = ? OR ? = ?))",
-		[]interface{}{ownerPermalink, VisibilityPublic, ownerPermalink, userPermalink},
+		fmt.Sprintf("(owner = ? AND (visibility = ? OR ? = ? OR %s))", roleBindingCondition),
+		[]interface{}{ownerPermalink, VisibilityPublic, ownerPermalink, userPermalink, userPermalink},
 		pageSize, pageToken, isBasicView, filter, showDeleted)
 
 }
@@ -271,17 +346,16 @@ func (r *repository) getUserConnectorResource(ctx context.Context, where string,
 func (r *repository) GetUserConnectorResourceByID(ctx context.Context, ownerPermalink string, userPermalink string, id string, isBasicView bool) (*datamodel.ConnectorResource, error) {
 
 	return r.getUserConnectorResource(ctx,
-		"(id = ? AND (owner = ? AND (visibility = ? OR ? = ?)))",
-		[]interface{}{id, ownerPermalink, VisibilityPublic, ownerPermalink, userPermalink},
+		fmt.Sprintf("(id = ? AND (owner = ? AND (visibility = ? OR ? = ? OR %s)))", roleBindingCondition),
+		[]interface{}{id, ownerPermalink, VisibilityPublic, ownerPermalink, userPermalink, userPermalink},
 		isBasicView)
 }
 
 func (r *repository) GetConnectorResourceByUID(ctx context.Context, userPermalink string, uid uuid.UUID, isBasicView bool) (*datamodel.ConnectorResource, error) {
 
-	// TODO: ACL
 	return r.getUserConnectorResource(ctx,
-		"(uid = ? AND (visibility = ? OR owner = ?))",
-		[]interface{}{uid, VisibilityPublic, userPermalink},
+		fmt.Sprintf("(uid = ? AND (visibility = ? OR owner = ? OR %s))", roleBindingCondition),
+		[]interface{}{uid, VisibilityPublic, userPermalink, userPermalink},
 		isBasicView)
 
 }
@@ -442,6 +516,150 @@ func (r *repository) UpdateUserConnectorResourceStateByID(ctx context.Context, o
 	return nil
 }
 
+func (r *repository) UpdateUserConnectorResourceRetryPolicyByID(ctx context.Context, ownerPermalink string, userPermalink string, id string, retryPolicy datatypes.JSON) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	if result := r.db.Model(&datamodel.ConnectorResource{}).
+		Where("(id = ? AND owner = ? AND ? = ?)", id, ownerPermalink, ownerPermalink, userPermalink).
+		Update("retry_policy", retryPolicy); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] update connector retry policy by id error: %s", result.Error.Error()),
+			"connector",
+			"",
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	} else if result.RowsAffected == 0 {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.NotFound,
+			fmt.Sprintf("[db] update connector retry policy by id error: %s", "Not found"),
+			"connector",
+			"",
+			"",
+			"Not found",
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	}
+	return nil
+}
+
+func (r *repository) UpdateUserConnectorResourceMaxConcurrencyByID(ctx context.Context, ownerPermalink string, userPermalink string, id string, maxConcurrency int32) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	if result := r.db.Model(&datamodel.ConnectorResource{}).
+		Where("(id = ? AND owner = ? AND ? = ?)", id, ownerPermalink, ownerPermalink, userPermalink).
+		Update("max_concurrency", maxConcurrency); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] update connector max concurrency by id error: %s", result.Error.Error()),
+			"connector",
+			"",
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	} else if result.RowsAffected == 0 {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.NotFound,
+			fmt.Sprintf("[db] update connector max concurrency by id error: %s", "Not found"),
+			"connector",
+			"",
+			"",
+			"Not found",
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	}
+	return nil
+}
+
+func (r *repository) UpdateUserConnectorResourceCacheTTLByID(ctx context.Context, ownerPermalink string, userPermalink string, id string, cacheTTL int32) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	if result := r.db.Model(&datamodel.ConnectorResource{}).
+		Where("(id = ? AND owner = ? AND ? = ?)", id, ownerPermalink, ownerPermalink, userPermalink).
+		Update("cache_ttl", cacheTTL); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] update connector cache ttl by id error: %s", result.Error.Error()),
+			"connector",
+			"",
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	} else if result.RowsAffected == 0 {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.NotFound,
+			fmt.Sprintf("[db] update connector cache ttl by id error: %s", "Not found"),
+			"connector",
+			"",
+			"",
+			"Not found",
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	}
+	return nil
+}
+
+func (r *repository) UpdateUserConnectorResourceDebugCaptureByID(ctx context.Context, ownerPermalink string, userPermalink string, id string, debugCapture bool) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	if result := r.db.Model(&datamodel.ConnectorResource{}).
+		Where("(id = ? AND owner = ? AND ? = ?)", id, ownerPermalink, ownerPermalink, userPermalink).
+		Update("debug_capture", debugCapture); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] update connector debug capture by id error: %s", result.Error.Error()),
+			"connector",
+			"",
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	} else if result.RowsAffected == 0 {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.NotFound,
+			fmt.Sprintf("[db] update connector debug capture by id error: %s", "Not found"),
+			"connector",
+			"",
+			"",
+			"Not found",
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	}
+	return nil
+}
+
 // TranspileFilter transpiles a parsed AIP filter expression to GORM DB clauses
 func (r *repository) transpileFilter(filter filtering.Filter) (*clause.Expr, error) {
 	return (&Transpiler{
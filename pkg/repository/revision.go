@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/x/paginate"
+	"github.com/instill-ai/x/sterr"
+)
+
+// CreateConnectorRevision appends the revision to the history of its connector, the revision number is assigned here
+func (r *repository) CreateConnectorRevision(ctx context.Context, revision *datamodel.ConnectorRevision) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Lock the connector so that concurrent updates get consecutive revision numbers
+		if result := tx.Model(&datamodel.ConnectorResource{}).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("uid = ?", revision.ConnectorUID).
+			Select("uid").
+			Find(&datamodel.ConnectorResource{}); result.Error != nil {
+			return result.Error
+		}
+
+		var latest int32
+		if result := tx.Model(&datamodel.ConnectorRevision{}).
+			Where("connector_uid = ?", revision.ConnectorUID).
+			Select("COALESCE(MAX(revision), 0)").
+			Scan(&latest); result.Error != nil {
+			return result.Error
+		}
+		revision.Revision = latest + 1
+
+		return tx.Create(revision).Error
+	})
+	if err != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] create connector revision error: %s", err.Error()),
+			"connector_revision",
+			fmt.Sprintf("connector uid %s", revision.ConnectorUID),
+			revision.Creator,
+			err.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	}
+	return nil
+}
+
+func (r *repository) ListConnectorRevisions(ctx context.Context, connUID uuid.UUID, pageSize int64, pageToken string) (revisions []*datamodel.ConnectorRevision, totalSize int64, nextPageToken string, err error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	r.db.Model(&datamodel.ConnectorRevision{}).Where("connector_uid = ?", connUID).Count(&totalSize)
+
+	queryBuilder := r.db.Model(&datamodel.ConnectorRevision{}).Where("connector_uid = ?", connUID).Order("revision DESC")
+
+	if pageSize == 0 {
+		pageSize = DefaultPageSize
+	} else if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+
+	queryBuilder = queryBuilder.Limit(int(pageSize))
+
+	if pageToken != "" {
+		_, lastRevision, err := paginate.DecodeToken(pageToken)
+		if err == nil {
+			_, err = strconv.Atoi(lastRevision)
+		}
+		if err != nil {
+			st, err := sterr.CreateErrorBadRequest(
+				fmt.Sprintf("[db] list connector revision error: %s", err.Error()),
+				[]*errdetails.BadRequest_FieldViolation{
+					{
+						Field:       "page_token",
+						Description: fmt.Sprintf("Invalid page token: %s", err.Error()),
+					},
+				},
+			)
+			if err != nil {
+				logger.Error(err.Error())
+			}
+			return nil, 0, "", st.Err()
+		}
+
+		queryBuilder = queryBuilder.Where("revision < ?", lastRevision)
+	}
+
+	if result := queryBuilder.Find(&revisions); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] list connector revision error: %s", result.Error.Error()),
+			"connector_revision",
+			fmt.Sprintf("connector uid %s", connUID),
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, 0, "", st.Err()
+	}
+
+	// Revisions start at 1, there is no next page once it is listed
+	if len(revisions) > 0 && revisions[len(revisions)-1].Revision > 1 {
+		nextPageToken = paginate.EncodeToken(time.Time{}, strconv.Itoa(int(revisions[len(revisions)-1].Revision)))
+	}
+
+	return revisions, totalSize, nextPageToken, nil
+}
+
+func (r *repository) GetConnectorRevision(ctx context.Context, connUID uuid.UUID, revision int32) (*datamodel.ConnectorRevision, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	var connRevision datamodel.ConnectorRevision
+	if result := r.db.Model(&datamodel.ConnectorRevision{}).
+		Where("(connector_uid = ? AND revision = ?)", connUID, revision).
+		First(&connRevision); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.NotFound,
+			fmt.Sprintf("[db] get connector revision error: %s", result.Error.Error()),
+			"connector_revision",
+			fmt.Sprintf("revision %d", revision),
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, st.Err()
+	}
+	return &connRevision, nil
+}
+
+// ListConnectorRevisionsBetween returns the revisions after {from} up to and including {to} in ascending order
+func (r *repository) ListConnectorRevisionsBetween(ctx context.Context, connUID uuid.UUID, from int32, to int32) ([]*datamodel.ConnectorRevision, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	var revisions []*datamodel.ConnectorRevision
+	if result := r.db.Model(&datamodel.ConnectorRevision{}).
+		Where("(connector_uid = ? AND revision > ? AND revision <= ?)", connUID, from, to).
+		Order("revision ASC").
+		Find(&revisions); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] list connector revision error: %s", result.Error.Error()),
+			"connector_revision",
+			fmt.Sprintf("connector uid %s", connUID),
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, st.Err()
+	}
+	return revisions, nil
+}
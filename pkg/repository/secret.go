@@ -0,0 +1,264 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/x/paginate"
+	"github.com/instill-ai/x/sterr"
+)
+
+func (r *repository) CreateUserSecret(ctx context.Context, ownerPermalink string, userPermalink string, secret *datamodel.Secret) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	if result := r.db.Model(&datamodel.Secret{}).Create(secret); result.Error != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(result.Error, &pgErr) && pgErr.Code == "23505" {
+			st, err := sterr.CreateErrorResourceInfo(
+				codes.AlreadyExists,
+				fmt.Sprintf("[db] create secret error: %s", pgErr.Message),
+				"secret",
+				fmt.Sprintf("id %s", secret.ID),
+				secret.Owner,
+				pgErr.Message,
+			)
+			if err != nil {
+				logger.Error(err.Error())
+			}
+			return st.Err()
+		}
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] create secret error: %s", result.Error.Error()),
+			"secret",
+			fmt.Sprintf("id %s", secret.ID),
+			secret.Owner,
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	}
+	return nil
+}
+
+func (r *repository) ListUserSecrets(ctx context.Context, ownerPermalink string, userPermalink string, pageSize int64, pageToken string) (secrets []*datamodel.Secret, totalSize int64, nextPageToken string, err error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	where := "(owner = ? AND ? = ?)"
+	whereArgs := []interface{}{ownerPermalink, ownerPermalink, userPermalink}
+
+	r.db.Model(&datamodel.Secret{}).Where(where, whereArgs...).Count(&totalSize)
+
+	queryBuilder := r.db.Model(&datamodel.Secret{}).Order("create_time DESC, uid DESC").Where(where, whereArgs...)
+
+	if pageSize == 0 {
+		pageSize = DefaultPageSize
+	} else if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+
+	queryBuilder = queryBuilder.Limit(int(pageSize))
+
+	if pageToken != "" {
+		createdAt, uid, err := paginate.DecodeToken(pageToken)
+		if err != nil {
+			st, err := sterr.CreateErrorBadRequest(
+				fmt.Sprintf("[db] list secret error: %s", err.Error()),
+				[]*errdetails.BadRequest_FieldViolation{
+					{
+						Field:       "page_token",
+						Description: fmt.Sprintf("Invalid page token: %s", err.Error()),
+					},
+				},
+			)
+			if err != nil {
+				logger.Error(err.Error())
+			}
+			return nil, 0, "", st.Err()
+		}
+
+		queryBuilder = queryBuilder.Where("(create_time,uid) < (?::timestamp, ?)", createdAt, uid)
+	}
+
+	if result := queryBuilder.Find(&secrets); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] list secret error: %s", result.Error.Error()),
+			"secret",
+			"",
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, 0, "", st.Err()
+	}
+
+	if len(secrets) > 0 {
+		last := secrets[len(secrets)-1]
+		oldest := &datamodel.Secret{}
+		if result := r.db.Model(&datamodel.Secret{}).
+			Where(where, whereArgs...).
+			Order("create_time ASC, uid ASC").Limit(1).Find(oldest); result.Error != nil {
+			st, err := sterr.CreateErrorResourceInfo(
+				codes.Internal,
+				fmt.Sprintf("[db] list secret error: %s", result.Error.Error()),
+				"secret",
+				"",
+				"",
+				result.Error.Error(),
+			)
+			if err != nil {
+				logger.Error(err.Error())
+			}
+			return nil, 0, "", st.Err()
+		}
+		if oldest.UID != last.UID {
+			nextPageToken = paginate.EncodeToken(last.CreateTime, last.UID.String())
+		}
+	}
+
+	return secrets, totalSize, nextPageToken, nil
+}
+
+func (r *repository) GetUserSecretByID(ctx context.Context, ownerPermalink string, userPermalink string, id string) (*datamodel.Secret, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	var secret datamodel.Secret
+	if result := r.db.Model(&datamodel.Secret{}).
+		Where("(id = ? AND owner = ? AND ? = ?)", id, ownerPermalink, ownerPermalink, userPermalink).
+		First(&secret); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.NotFound,
+			fmt.Sprintf("[db] get secret error: %s", result.Error.Error()),
+			"secret",
+			fmt.Sprintf("id %s", id),
+			ownerPermalink,
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, st.Err()
+	}
+	return &secret, nil
+}
+
+func (r *repository) UpdateUserSecretByID(ctx context.Context, ownerPermalink string, userPermalink string, id string, secret *datamodel.Secret) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	if result := r.db.Model(&datamodel.Secret{}).
+		Where("(id = ? AND owner = ? AND ? = ?)", id, ownerPermalink, ownerPermalink, userPermalink).
+		Updates(secret); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] update secret error: %s", result.Error.Error()),
+			"secret",
+			fmt.Sprintf("id %s", id),
+			ownerPermalink,
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	} else if result.RowsAffected == 0 {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.NotFound,
+			"[db] update secret error: Not found",
+			"secret",
+			fmt.Sprintf("id %s", id),
+			ownerPermalink,
+			"Not found",
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	}
+	return nil
+}
+
+func (r *repository) DeleteUserSecretByID(ctx context.Context, ownerPermalink string, userPermalink string, id string) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	result := r.db.Model(&datamodel.Secret{}).
+		Where("(id = ? AND owner = ? AND ? = ?)", id, ownerPermalink, ownerPermalink, userPermalink).
+		Delete(&datamodel.Secret{})
+
+	if result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] delete secret error: %s", result.Error.Error()),
+			"secret",
+			fmt.Sprintf("id %s", id),
+			ownerPermalink,
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	}
+
+	if result.RowsAffected == 0 {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.NotFound,
+			"[db] delete secret error: Not found",
+			"secret",
+			fmt.Sprintf("id %s", id),
+			ownerPermalink,
+			"Not found",
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	}
+
+	return nil
+}
+
+// ListUserConnectorResourceIDsBySecretReference returns the ids of the connectors whose configuration contains the secret reference.
+// Soft-deleted connectors are included, as they can still be undeleted until they are purged
+func (r *repository) ListUserConnectorResourceIDsBySecretReference(ctx context.Context, ownerPermalink string, reference string) ([]string, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	var ids []string
+	// A reference is a complete JSON string, matching it with the quotes avoids matching a longer secret id
+	if result := r.db.Unscoped().Model(&datamodel.ConnectorResource{}).
+		Where("(owner = ? AND configuration::text LIKE ?)", ownerPermalink, fmt.Sprintf("%%\"%s\"%%", reference)).
+		Distinct("id").
+		Order("id").
+		Pluck("id", &ids); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] list connector by secret reference error: %s", result.Error.Error()),
+			"connector",
+			"",
+			ownerPermalink,
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, st.Err()
+	}
+	return ids, nil
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -123,12 +124,30 @@ func (t *Transpiler) transpileIdentExpr(e *expr.Expr) (*clause.Expr, error) {
 	}, nil
 }
 
+// isMapSelectExpr tells whether the expr selects a key of a map-typed ident, e.g. `labels.env`
+func (t *Transpiler) isMapSelectExpr(e *expr.Expr) bool {
+	selectExpr := e.GetSelectExpr()
+	if selectExpr == nil || selectExpr.Operand.GetIdentExpr() == nil {
+		return false
+	}
+	identType, ok := t.filter.CheckedExpr.TypeMap[selectExpr.Operand.Id]
+	return ok && identType.GetMapType() != nil
+}
+
 func (t *Transpiler) transpileSelectExpr(e *expr.Expr) (*clause.Expr, error) {
 	selectExpr := e.GetSelectExpr()
 	operand, err := t.transpileExpr(selectExpr.Operand)
 	if err != nil {
 		return nil, err
 	}
+	// The key of a map is bound as it comes from the filter as is
+	if t.isMapSelectExpr(e) {
+		return &clause.Expr{
+			SQL:                fmt.Sprintf("%s ->> ?", operand.SQL),
+			Vars:               []interface{}{selectExpr.Field},
+			WithoutParentheses: true,
+		}, nil
+	}
 	return &clause.Expr{
 		SQL:                fmt.Sprintf("%s ->> '%s'", operand.SQL, selectExpr.Field),
 		Vars:               nil,
@@ -151,6 +170,7 @@ func (t *Transpiler) transpileNotCallExpr(e *expr.Expr) (*clause.Expr, error) {
 	}
 	return &clause.Expr{
 		SQL:                fmt.Sprintf("NOT %s", rhsExpr.SQL),
+		Vars:               rhsExpr.Vars,
 		WithoutParentheses: true,
 	}, nil
 }
@@ -165,18 +185,38 @@ func (t *Transpiler) transpileComparisonCallExpr(e *expr.Expr, op interface{}) (
 		)
 	}
 
-	ident, err := t.transpileExpr(callExpr.Args[0])
+	con, err := t.transpileExpr(callExpr.Args[1])
 	if err != nil {
 		return nil, err
 	}
 
-	con, err := t.transpileExpr(callExpr.Args[1])
+	// The equality on a map key is a containment which uses the GIN index of the column
+	if _, isEq := op.(clause.Eq); isEq && t.isMapSelectExpr(callExpr.Args[0]) && len(con.Vars) == 1 {
+		if value, isString := con.Vars[0].(string); isString {
+			selectExpr := callExpr.Args[0].GetSelectExpr()
+			operand, err := t.transpileExpr(selectExpr.Operand)
+			if err != nil {
+				return nil, err
+			}
+			contained, err := json.Marshal(map[string]string{selectExpr.Field: value})
+			if err != nil {
+				return nil, err
+			}
+			return &clause.Expr{
+				SQL:                fmt.Sprintf("%s @> ?::jsonb", operand.SQL),
+				Vars:               []interface{}{string(contained)},
+				WithoutParentheses: true,
+			}, nil
+		}
+	}
+
+	ident, err := t.transpileExpr(callExpr.Args[0])
 	if err != nil {
 		return nil, err
 	}
 
 	var sql string
-	var vars []interface{}
+	vars := append([]interface{}{}, ident.Vars...)
 	switch op.(type) {
 	case clause.Eq:
 		sql = fmt.Sprintf("%s = ?", ident.SQL)
@@ -233,7 +273,7 @@ func (t *Transpiler) transpileBinaryLogicalCallExpr(e *expr.Expr, op clause.Expr
 
 	return &clause.Expr{
 		SQL:                sql,
-		Vars:               append(lhsExpr.Vars, rhsExpr.Vars),
+		Vars:               append(append([]interface{}{}, lhsExpr.Vars...), rhsExpr.Vars...),
 		WithoutParentheses: true,
 	}, nil
 }
@@ -273,6 +313,22 @@ func (t *Transpiler) transpileHasCallExpr(e *expr.Expr) (*clause.Expr, error) {
 				Vars:               con.Vars,
 				WithoutParentheses: false,
 			}, nil
+		// Maps:
+		// > Maps query to see if the map contains the key.
+		case identType.GetMapType() != nil:
+			iden, err := t.transpileIdentExpr(identExpr)
+			if err != nil {
+				return nil, err
+			}
+			con, err := t.transpileConstExpr(constExpr)
+			if err != nil {
+				return nil, err
+			}
+			return &clause.Expr{
+				SQL:                fmt.Sprintf("%s ->> ? IS NOT NULL", iden.SQL),
+				Vars:               con.Vars,
+				WithoutParentheses: false,
+			}, nil
 		default:
 			return nil, fmt.Errorf("TODO: add support for transpiling `:` on other types than repeated primitives")
 		}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+
+	"go.einride.tech/aip/filtering"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+type filterRequest string
+
+func (f filterRequest) GetFilter() string {
+	return string(f)
+}
+
+// definitionDeclarations are the filter declarations of the connector definition list
+func definitionDeclarations(t *testing.T) *filtering.Declarations {
+	var connType connectorPB.ConnectorType
+	declarations, err := filtering.NewDeclarations(
+		filtering.DeclareStandardFunctions(),
+		filtering.DeclareEnumIdent("connector_type", connType.Type()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return declarations
+}
+
+// resourceDeclarations are the filter declarations of the connector resource lists
+func resourceDeclarations(t *testing.T) *filtering.Declarations {
+	var connType connectorPB.ConnectorType
+	declarations, err := filtering.NewDeclarations(
+		filtering.DeclareStandardFunctions(),
+		filtering.DeclareEnumIdent("connector_type", connType.Type()),
+		filtering.DeclareIdent("labels", filtering.TypeMap(filtering.TypeString, filtering.TypeString)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return declarations
+}
+
+func TestTranspile(t *testing.T) {
+	ai := protoreflect.Name("CONNECTOR_TYPE_AI")
+	data := protoreflect.Name("CONNECTOR_TYPE_DATA")
+	blockchain := protoreflect.Name("CONNECTOR_TYPE_BLOCKCHAIN")
+
+	testCases := []struct {
+		name         string
+		declarations func(t *testing.T) *filtering.Declarations
+		filter       string
+		wantSQL      string
+		wantVars     []interface{}
+	}{
+		{
+			name:         "definitions, equality",
+			declarations: definitionDeclarations,
+			filter:       "connector_type = CONNECTOR_TYPE_AI",
+			wantSQL:      "connector_type = ?",
+			wantVars:     []interface{}{ai},
+		},
+		{
+			name:         "definitions, or",
+			declarations: definitionDeclarations,
+			filter:       "connector_type = CONNECTOR_TYPE_AI OR connector_type = CONNECTOR_TYPE_DATA",
+			wantSQL:      "connector_type = ? OR connector_type = ?",
+			wantVars:     []interface{}{ai, data},
+		},
+		{
+			name:         "definitions, chained or",
+			declarations: definitionDeclarations,
+			filter:       "connector_type = CONNECTOR_TYPE_AI OR connector_type = CONNECTOR_TYPE_DATA OR connector_type = CONNECTOR_TYPE_BLOCKCHAIN",
+			wantSQL:      "connector_type = ? OR connector_type = ? OR connector_type = ?",
+			wantVars:     []interface{}{ai, data, blockchain},
+		},
+		{
+			name:         "definitions, and",
+			declarations: definitionDeclarations,
+			filter:       "connector_type = CONNECTOR_TYPE_AI AND connector_type != CONNECTOR_TYPE_DATA",
+			wantSQL:      "connector_type = ? AND connector_type <> ?",
+			wantVars:     []interface{}{ai, data},
+		},
+		{
+			name:         "resources, or",
+			declarations: resourceDeclarations,
+			filter:       "connector_type = CONNECTOR_TYPE_AI OR connector_type = CONNECTOR_TYPE_DATA",
+			wantSQL:      "connector_type = ? OR connector_type = ?",
+			wantVars:     []interface{}{ai, data},
+		},
+		{
+			name:         "resources, label equality",
+			declarations: resourceDeclarations,
+			filter:       `labels.env = "prod"`,
+			wantSQL:      "labels @> ?::jsonb",
+			wantVars:     []interface{}{`{"env":"prod"}`},
+		},
+		{
+			name:         "resources, and with a label",
+			declarations: resourceDeclarations,
+			filter:       `connector_type = CONNECTOR_TYPE_AI AND labels.env = "prod"`,
+			wantSQL:      "connector_type = ? AND labels @> ?::jsonb",
+			wantVars:     []interface{}{ai, `{"env":"prod"}`},
+		},
+		{
+			name:         "resources, or with a label inequality",
+			declarations: resourceDeclarations,
+			filter:       `labels.env != "dev" OR connector_type = CONNECTOR_TYPE_DATA`,
+			wantSQL:      "labels ->> ? <> ? OR connector_type = ?",
+			wantVars:     []interface{}{"env", "dev", data},
+		},
+		{
+			name:         "resources, not a label",
+			declarations: resourceDeclarations,
+			filter:       `labels.env = "prod" AND NOT labels.team = "ml"`,
+			wantSQL:      "labels @> ?::jsonb AND NOT labels @> ?::jsonb",
+			wantVars:     []interface{}{`{"env":"prod"}`, `{"team":"ml"}`},
+		},
+		{
+			name:         "resources, has a label",
+			declarations: resourceDeclarations,
+			filter:       `labels:"env"`,
+			wantSQL:      "labels ->> ? IS NOT NULL",
+			wantVars:     []interface{}{"env"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filter, err := filtering.ParseFilter(filterRequest(tc.filter), tc.declarations(t))
+			if err != nil {
+				t.Fatalf("parse %q: %v", tc.filter, err)
+			}
+			transpiler := NewTranspiler(filter)
+			expr, err := transpiler.Transpile()
+			if err != nil {
+				t.Fatalf("transpile %q: %v", tc.filter, err)
+			}
+			if expr.SQL != tc.wantSQL {
+				t.Errorf("got SQL %q, want %q", expr.SQL, tc.wantSQL)
+			}
+			if !reflect.DeepEqual(expr.Vars, tc.wantVars) {
+				t.Errorf("got vars %#v, want %#v", expr.Vars, tc.wantVars)
+			}
+		})
+	}
+}
+
+func TestTranspileEmptyFilter(t *testing.T) {
+	transpiler := NewTranspiler(filtering.Filter{})
+	expr, err := transpiler.Transpile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expr != nil {
+		t.Errorf("got %v, want no expression", expr)
+	}
+}
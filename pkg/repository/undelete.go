@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"google.golang.org/grpc/codes"
+	"gorm.io/gorm"
+
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/x/sterr"
+)
+
+// connectorDependentTables hold the rows of a connector which are purged with it
+var connectorDependentTables = []string{
+	"connector_revision",
+	"connector_operation",
+	"connector_role_binding",
+	"connector_execution",
+	"connector_capture",
+	"connector_dead_letter",
+}
+
+func (r *repository) GetUserDeletedConnectorResourceByID(ctx context.Context, ownerPermalink string, id string) (*datamodel.ConnectorResource, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	var connector datamodel.ConnectorResource
+	if result := r.db.Unscoped().Model(&datamodel.ConnectorResource{}).
+		Where("id = ? AND owner = ? AND delete_time IS NOT NULL", id, ownerPermalink).
+		Order("delete_time DESC").
+		First(&connector); result.Error != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.NotFound,
+			fmt.Sprintf("[db] get deleted connector error: %s", result.Error.Error()),
+			"connector",
+			fmt.Sprintf("id %s", id),
+			ownerPermalink,
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, st.Err()
+	}
+	return &connector, nil
+}
+
+func (r *repository) UndeleteConnectorResourceByUID(ctx context.Context, uid uuid.UUID) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	result := r.db.Unscoped().Model(&datamodel.ConnectorResource{}).
+		Where("uid = ? AND delete_time IS NOT NULL", uid).
+		Update("delete_time", nil)
+	if result.Error != nil {
+		var pgErr *pgconn.PgError
+		code := codes.Internal
+		// The id is used by another connector of the owner
+		if errors.As(result.Error, &pgErr) && pgErr.Code == "23505" {
+			code = codes.FailedPrecondition
+		}
+		st, err := sterr.CreateErrorResourceInfo(
+			code,
+			fmt.Sprintf("[db] undelete connector error: %s", result.Error.Error()),
+			"connector",
+			fmt.Sprintf("uid %s", uid),
+			"",
+			result.Error.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	} else if result.RowsAffected == 0 {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.NotFound,
+			fmt.Sprintf("[db] undelete connector error: %s", "Not found"),
+			"connector",
+			fmt.Sprintf("uid %s", uid),
+			"",
+			"Not found",
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	}
+	return nil
+}
+
+func (r *repository) PurgeDeletedConnectorResources(ctx context.Context, before time.Time) (int64, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	var purged int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+
+		var uids []uuid.UUID
+		if result := tx.Unscoped().Model(&datamodel.ConnectorResource{}).
+			Where("delete_time < ?", before).
+			Pluck("uid", &uids); result.Error != nil {
+			return result.Error
+		}
+		if len(uids) == 0 {
+			return nil
+		}
+
+		for _, table := range connectorDependentTables {
+			if result := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE connector_uid IN ?", table), uids); result.Error != nil {
+				return result.Error
+			}
+		}
+
+		result := tx.Unscoped().Where("uid IN ?", uids).Delete(&datamodel.ConnectorResource{})
+		if result.Error != nil {
+			return result.Error
+		}
+		purged = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.Internal,
+			fmt.Sprintf("[db] purge deleted connector error: %s", err.Error()),
+			"connector",
+			"",
+			"",
+			err.Error(),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return 0, st.Err()
+	}
+	return purged, nil
+}
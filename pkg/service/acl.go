@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/instill-ai/connector-backend/internal/resource"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/connector-backend/pkg/repository"
+	"github.com/instill-ai/x/sterr"
+)
+
+// connectorRole returns the role of the user on the connector: the owner is admin, a role binding grants its
+// role and anyone can view a public connector. An empty role means no access at all.
+func (s *service) connectorRole(ctx context.Context, userPermalink string, dbConnectorResource *datamodel.ConnectorResource) (datamodel.ConnectorRole, error) {
+
+	if dbConnectorResource.Owner == userPermalink {
+		return datamodel.ConnectorRoleAdmin, nil
+	}
+
+	binding, err := s.repository.GetConnectorRoleBinding(ctx, dbConnectorResource.UID, userPermalink)
+	if err != nil && status.Code(err) != codes.NotFound {
+		return "", err
+	}
+	if binding != nil {
+		return binding.Role, nil
+	}
+
+	if dbConnectorResource.Visibility == repository.VisibilityPublic {
+		return datamodel.ConnectorRoleViewer, nil
+	}
+	return "", nil
+}
+
+// checkConnectorPermission returns the connector if the user has the role on it. Connectors the user can not
+// view are not found, the others fail with PermissionDenied.
+func (s *service) checkConnectorPermission(ctx context.Context, ownerPermalink string, userPermalink string, id string, role datamodel.ConnectorRole) (*datamodel.ConnectorResource, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	dbConnectorResource, err := s.repository.GetUserConnectorResourceByID(ctx, ownerPermalink, userPermalink, id, false)
+	if err != nil {
+		return nil, err
+	}
+
+	userRole, err := s.connectorRole(ctx, userPermalink, dbConnectorResource)
+	if err != nil {
+		return nil, err
+	}
+	if !userRole.Includes(role) {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.PermissionDenied,
+			"[service] permission denied",
+			"connector",
+			fmt.Sprintf("id %s", id),
+			userPermalink,
+			fmt.Sprintf("the %s role is required", role),
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, st.Err()
+	}
+
+	return dbConnectorResource, nil
+}
+
+// convertSubjectNameToPermalink resolves the subject of a role binding, only users can be granted roles
+func (s *service) convertSubjectNameToPermalink(name string) (string, error) {
+
+	splits := strings.Split(name, "/")
+	if len(splits) != 2 || resource.NamespaceType(splits[0]) != resource.User {
+		st, _ := sterr.CreateErrorBadRequest(
+			"[service] role binding error",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "subject",
+					Description: fmt.Sprintf("%s is not a user name", name),
+				},
+			},
+		)
+		return "", st.Err()
+	}
+
+	permalink, err := s.ConvertOwnerNameToPermalink(name)
+	if err != nil {
+		st, _ := sterr.CreateErrorResourceInfo(
+			codes.NotFound,
+			"[service] role binding error",
+			"user",
+			name,
+			"",
+			err.Error(),
+		)
+		return "", st.Err()
+	}
+	return permalink, nil
+}
+
+func (s *service) ListUserConnectorRoleBindings(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string) ([]*datamodel.ConnectorRoleBinding, error) {
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	dbConnectorResource, err := s.checkConnectorPermission(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorRoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repository.ListConnectorRoleBindings(ctx, dbConnectorResource.UID)
+}
+
+func (s *service) BindUserConnectorRole(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, subject string, role datamodel.ConnectorRole) (*datamodel.ConnectorRoleBinding, error) {
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	if !role.IsValid() {
+		st, _ := sterr.CreateErrorBadRequest(
+			"[service] role binding error",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "role",
+					Description: fmt.Sprintf("unknown role %q", role),
+				},
+			},
+		)
+		return nil, st.Err()
+	}
+
+	dbConnectorResource, err := s.checkConnectorPermission(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorRoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	subjectPermalink, err := s.convertSubjectNameToPermalink(subject)
+	if err != nil {
+		return nil, err
+	}
+	if subjectPermalink == dbConnectorResource.Owner {
+		st, _ := sterr.CreateErrorBadRequest(
+			"[service] role binding error",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "subject",
+					Description: "the owner always has the admin role",
+				},
+			},
+		)
+		return nil, st.Err()
+	}
+
+	if err := s.repository.UpsertConnectorRoleBinding(ctx, &datamodel.ConnectorRoleBinding{
+		ConnectorUID: dbConnectorResource.UID,
+		Subject:      subjectPermalink,
+		Role:         role,
+	}); err != nil {
+		return nil, err
+	}
+
+	return s.repository.GetConnectorRoleBinding(ctx, dbConnectorResource.UID, subjectPermalink)
+}
+
+func (s *service) UnbindUserConnectorRole(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, subject string) error {
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	dbConnectorResource, err := s.checkConnectorPermission(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorRoleAdmin)
+	if err != nil {
+		return err
+	}
+
+	subjectPermalink, err := s.convertSubjectNameToPermalink(subject)
+	if err != nil {
+		return err
+	}
+
+	return s.repository.DeleteConnectorRoleBinding(ctx, dbConnectorResource.UID, subjectPermalink)
+}
+
+// CheckUserConnectorPermission returns a PermissionDenied error if the user does not have the role on the connector
+func (s *service) CheckUserConnectorPermission(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, role datamodel.ConnectorRole) error {
+	_, err := s.checkConnectorPermission(ctx, ns.String(), resource.UserUidToUserPermalink(userUid), id, role)
+	return err
+}
+
+// CheckConnectorPermissionAdmin reports the role of the user on the connector and whether it includes the given role
+func (s *service) CheckConnectorPermissionAdmin(ctx context.Context, connUID uuid.UUID, userUid uuid.UUID, role datamodel.ConnectorRole) (datamodel.ConnectorRole, bool, error) {
+
+	dbConnectorResource, err := s.repository.GetConnectorResourceByUIDAdmin(ctx, connUID, true)
+	if err != nil {
+		return "", false, err
+	}
+
+	userRole, err := s.connectorRole(ctx, resource.UserUidToUserPermalink(userUid), dbConnectorResource)
+	if err != nil {
+		return "", false, err
+	}
+
+	return userRole, userRole.Includes(role), nil
+}
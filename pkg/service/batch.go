@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/instill-ai/connector-backend/internal/resource"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+
+	componentBase "github.com/instill-ai/component/pkg/base"
+)
+
+// inputErrorPattern matches the validation error of one input, e.g. "inputs[3].text: missing properties"
+var inputErrorPattern = regexp.MustCompile(`^inputs\[(\d+)\]`)
+
+// BatchExecution executes successive batches of inputs against a single execution of a connector
+type BatchExecution struct {
+	ConnectorResource *datamodel.ConnectorResource
+	execution         componentBase.IExecution
+	service           *service
+}
+
+// ExecutionItemError is the error of one input of a batch
+type ExecutionItemError struct {
+	Index  int
+	Status *status.Status
+}
+
+// CreateBatchExecution creates the execution of the connector task that the batches are executed against
+func (s *service) CreateBatchExecution(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, task string) (*BatchExecution, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	dbConnectorResource, err := s.checkConnectorPermission(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorRoleExecutor)
+	if err != nil {
+		return nil, err
+	}
+
+	configuration, err := s.getExecutionConfiguration(ctx, dbConnectorResource)
+	if err != nil {
+		return nil, err
+	}
+
+	execution, err := s.connectors.CreateExecution(dbConnectorResource.ConnectorDefinitionUID, task, configuration, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BatchExecution{
+		ConnectorResource: dbConnectorResource,
+		execution:         execution,
+		service:           s,
+	}, nil
+}
+
+// Execute executes a batch, the outputs are aligned with the inputs and nil for the inputs that have failed.
+// The inputs failing the validation are reported on their own and the batch is executed again without them,
+// an error raised by the connector can not be attributed to an input and is reported for every input.
+// The batch takes a token of the rate limits like an Execute request, the error is returned if there is none left.
+func (e *BatchExecution) Execute(ctx context.Context, executeUID uuid.UUID, inputs []*structpb.Struct) ([]*structpb.Struct, []ExecutionItemError, error) {
+
+	if err := e.service.checkRateLimits(ctx, e.ConnectorResource); err != nil {
+		return nil, nil, err
+	}
+
+	outputs := make([]*structpb.Struct, len(inputs))
+	itemErrors := []ExecutionItemError{}
+
+	release, err := e.service.acquireExecutionSlot(ctx, e.ConnectorResource)
+	if err != nil {
+		return outputs, failAll(len(inputs), err), nil
+	}
+	defer release()
+
+	pending := make([]int, len(inputs))
+	for i := range pending {
+		pending[i] = i
+	}
+
+	for len(pending) > 0 {
+		batch := make([]*structpb.Struct, len(pending))
+		for j, i := range pending {
+			batch[j] = inputs[i]
+		}
+
+		results, err := e.execution.ExecuteWithValidation(batch)
+		if err == nil && len(results) != len(batch) {
+			err = fmt.Errorf("the connector returned %d outputs for %d inputs", len(results), len(batch))
+		}
+		if err == nil {
+			for j, i := range pending {
+				outputs[i] = results[j]
+			}
+			break
+		}
+
+		invalid := invalidInputs(err.Error(), pending)
+		if invalid == nil {
+			st := status.Convert(err)
+			for _, i := range pending {
+				itemErrors = append(itemErrors, ExecutionItemError{Index: i, Status: st})
+			}
+			break
+		}
+
+		remaining := []int{}
+		for j, i := range pending {
+			if messages, ok := invalid[j]; ok {
+				itemErrors = append(itemErrors, ExecutionItemError{
+					Index:  i,
+					Status: status.New(codes.InvalidArgument, strings.Join(messages, "; ")),
+				})
+			} else {
+				remaining = append(remaining, i)
+			}
+		}
+		pending = remaining
+	}
+
+	sort.Slice(itemErrors, func(a, b int) bool {
+		return itemErrors[a].Index < itemErrors[b].Index
+	})
+	e.capture(ctx, executeUID, inputs, outputs, itemErrors)
+	return outputs, itemErrors, nil
+}
+
+// ExecuteEach validates and executes every input on its own so that a failing input does not fail the others,
+// the outputs are aligned with the inputs and nil for the inputs that have failed. The inputs take a token of
+// the rate limits together, the error is returned if there is none left.
+func (e *BatchExecution) ExecuteEach(ctx context.Context, executeUID uuid.UUID, inputs []*structpb.Struct) ([]*structpb.Struct, []ExecutionItemError, error) {
+
+	if err := e.service.checkRateLimits(ctx, e.ConnectorResource); err != nil {
+		return nil, nil, err
+	}
+
+	outputs := make([]*structpb.Struct, len(inputs))
+	itemErrors := []ExecutionItemError{}
+
+	release, err := e.service.acquireExecutionSlot(ctx, e.ConnectorResource)
+	if err != nil {
+		return outputs, failAll(len(inputs), err), nil
+	}
+	defer release()
+
+	for i, input := range inputs {
+		results, err := e.execution.ExecuteWithValidation([]*structpb.Struct{input})
+		if err == nil && len(results) != 1 {
+			err = fmt.Errorf("the connector returned %d outputs for 1 input", len(results))
+		}
+		if err != nil {
+			st := status.Convert(err)
+			if invalid := invalidInputs(err.Error(), []int{i}); invalid != nil {
+				st = status.New(codes.InvalidArgument, strings.Join(invalid[0], "; "))
+			}
+			itemErrors = append(itemErrors, ExecutionItemError{Index: i, Status: st})
+			continue
+		}
+		outputs[i] = results[0]
+	}
+
+	e.capture(ctx, executeUID, inputs, outputs, itemErrors)
+	return outputs, itemErrors, nil
+}
+
+// capture stores the payloads of a batch if the debug capture of the connector is enabled, the first item error
+// stands for the errors of the batch
+func (e *BatchExecution) capture(ctx context.Context, executeUID uuid.UUID, inputs []*structpb.Struct, outputs []*structpb.Struct, itemErrors []ExecutionItemError) {
+	errorMessage := ""
+	if len(itemErrors) > 0 {
+		errorMessage = fmt.Sprintf("input %d: %s", itemErrors[0].Index, itemErrors[0].Status.Message())
+	}
+	e.service.captureExecution(ctx, e.ConnectorResource, executeUID, inputs, outputs, errorMessage)
+}
+
+// failAll reports the error for each of the inputs
+func failAll(count int, err error) []ExecutionItemError {
+	st := status.Convert(err)
+	itemErrors := make([]ExecutionItemError, count)
+	for i := range itemErrors {
+		itemErrors[i] = ExecutionItemError{Index: i, Status: st}
+	}
+	return itemErrors
+}
+
+// invalidInputs groups the validation error messages by position in the executed batch, the messages refer to
+// the index of the input in the whole batch. It returns nil if a message is not about a single input.
+func invalidInputs(message string, pending []int) map[int][]string {
+	invalid := map[int][]string{}
+	for _, part := range strings.Split(message, "; ") {
+		match := inputErrorPattern.FindStringSubmatch(part)
+		if match == nil {
+			return nil
+		}
+		j, err := strconv.Atoi(match[1])
+		if err != nil || j >= len(pending) {
+			return nil
+		}
+		invalid[j] = append(invalid[j], fmt.Sprintf("inputs[%d]%s", pending[j], strings.TrimPrefix(part, match[0])))
+	}
+	return invalid
+}
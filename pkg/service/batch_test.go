@@ -0,0 +1,87 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+)
+
+func TestInvalidInputs(t *testing.T) {
+	testCases := []struct {
+		name    string
+		message string
+		pending []int
+		want    map[int][]string
+	}{
+		{
+			name:    "single input",
+			message: "inputs[0].text: missing properties",
+			pending: []int{0, 1, 2},
+			want:    map[int][]string{0: {"inputs[0].text: missing properties"}},
+		},
+		{
+			name:    "index in the whole batch",
+			message: "inputs[1].text: missing properties",
+			pending: []int{0, 2, 5},
+			want:    map[int][]string{1: {"inputs[2].text: missing properties"}},
+		},
+		{
+			name:    "several inputs",
+			message: "inputs[0].text: missing properties; inputs[2].images: expected array",
+			pending: []int{1, 3, 4},
+			want: map[int][]string{
+				0: {"inputs[1].text: missing properties"},
+				2: {"inputs[4].images: expected array"},
+			},
+		},
+		{
+			name:    "several errors of an input",
+			message: "inputs[1].text: missing properties; inputs[1].model: invalid value",
+			pending: []int{0, 1},
+			want:    map[int][]string{1: {"inputs[1].text: missing properties", "inputs[1].model: invalid value"}},
+		},
+		{
+			name:    "not about an input",
+			message: "inputs[0].text: missing properties; connection refused",
+			pending: []int{0, 1},
+			want:    nil,
+		},
+		{
+			name:    "index out of the batch",
+			message: "inputs[2].text: missing properties",
+			pending: []int{0, 1},
+			want:    nil,
+		},
+		{
+			name:    "connector error",
+			message: "non-200 status code: 500",
+			pending: []int{0},
+			want:    nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := invalidInputs(tc.message, tc.pending); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFailAll(t *testing.T) {
+	itemErrors := failAll(3, errors.New("connection refused"))
+	if len(itemErrors) != 3 {
+		t.Fatalf("got %d errors, want 3", len(itemErrors))
+	}
+	for i, itemError := range itemErrors {
+		if itemError.Index != i {
+			t.Errorf("got index %d, want %d", itemError.Index, i)
+		}
+		if itemError.Status.Code() != codes.Unknown || itemError.Status.Message() != "connection refused" {
+			t.Errorf("got status %v", itemError.Status)
+		}
+	}
+}
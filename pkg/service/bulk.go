@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gofrs/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/instill-ai/connector-backend/internal/resource"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/repository"
+	"github.com/instill-ai/x/sterr"
+
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+// MaxBulkConnectorResources is the maximum number of connector resources in a bulk request
+const MaxBulkConnectorResources = 100
+
+// BulkItemResult is the result of an item of a bulk request, ConnectorResource is nil if the item failed or
+// was deleted
+type BulkItemResult struct {
+	ConnectorResource *connectorPB.ConnectorResource
+	Status            *status.Status
+}
+
+// errBulkAborted rolls back the transaction of an all-or-nothing bulk request with a failed item
+var errBulkAborted = errors.New("bulk request aborted")
+
+// CheckBulkSize rejects the bulk requests with more than MaxBulkConnectorResources items
+func CheckBulkSize(size int) error {
+	if size <= MaxBulkConnectorResources {
+		return nil
+	}
+	st, _ := sterr.CreateErrorBadRequest(
+		"[service] bulk request too large",
+		[]*errdetails.BadRequest_FieldViolation{
+			{
+				Field:       "body",
+				Description: fmt.Sprintf("at most %d connector resources can be handled in a bulk request", MaxBulkConnectorResources),
+			},
+		},
+	)
+	return st.Err()
+}
+
+// transaction runs fn with a copy of the service bound to a database transaction. The state updates fn makes on
+// the controller are reverted if it fails, and kept in the journal of the service otherwise so that an enclosing
+// transaction can revert them too.
+func (s *service) transaction(ctx context.Context, fn func(s *service) error) error {
+
+	journal := &controllerJournal{}
+	err := s.repository.Transaction(ctx, func(repo repository.Repository) error {
+		txService := *s
+		txService.repository = repo
+		txService.journal = journal
+		return fn(&txService)
+	})
+	if err != nil {
+		s.revertResourceStates(ctx, journal)
+		return err
+	}
+	if s.journal != nil {
+		s.journal.changes = append(s.journal.changes, journal.changes...)
+	}
+	return nil
+}
+
+// runBulk processes the items of a bulk request in a single database transaction. Each item runs in a nested
+// transaction with a copy of the service bound to it, a failed item is rolled back alone along with the state
+// updates it made on the controller. If allOrNothing is set, the first failure rolls back every item and the
+// items which are not failed are reported as aborted.
+func (s *service) runBulk(ctx context.Context, size int, allOrNothing bool, process func(s *service, idx int) (*connectorPB.ConnectorResource, error)) ([]*BulkItemResult, error) {
+
+	if err := CheckBulkSize(size); err != nil {
+		return nil, err
+	}
+
+	results := make([]*BulkItemResult, size)
+	journal := &controllerJournal{}
+
+	err := s.repository.Transaction(ctx, func(repo repository.Repository) error {
+		failed := false
+		for idx := 0; idx < size; idx++ {
+			itemJournal := &controllerJournal{}
+			var connectorResource *connectorPB.ConnectorResource
+
+			err := repo.Transaction(ctx, func(itemRepo repository.Repository) error {
+				itemService := *s
+				itemService.repository = itemRepo
+				itemService.journal = itemJournal
+
+				var err error
+				connectorResource, err = process(&itemService, idx)
+				return err
+			})
+			if err != nil {
+				s.revertResourceStates(ctx, itemJournal)
+				results[idx] = &BulkItemResult{Status: status.Convert(err)}
+				failed = true
+				if allOrNothing {
+					break
+				}
+				continue
+			}
+
+			journal.changes = append(journal.changes, itemJournal.changes...)
+			results[idx] = &BulkItemResult{
+				ConnectorResource: connectorResource,
+				Status:            status.New(codes.OK, ""),
+			}
+		}
+		if failed && allOrNothing {
+			return errBulkAborted
+		}
+		return nil
+	})
+
+	if err != nil {
+		s.revertResourceStates(ctx, journal)
+		if !errors.Is(err, errBulkAborted) {
+			return nil, err
+		}
+		for idx, result := range results {
+			if result == nil || result.Status.Code() == codes.OK {
+				results[idx] = &BulkItemResult{
+					Status: status.New(codes.Aborted, "[service] rolled back, another item of the bulk request failed"),
+				}
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// BulkCreateUserConnectorResources creates the connector resources in a single transaction
+func (s *service) BulkCreateUserConnectorResources(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, connectorResources []*connectorPB.ConnectorResource, allOrNothing bool) ([]*BulkItemResult, error) {
+	return s.runBulk(ctx, len(connectorResources), allOrNothing, func(s *service, idx int) (*connectorPB.ConnectorResource, error) {
+		return s.CreateUserConnectorResource(ctx, ns, userUid, connectorResources[idx])
+	})
+}
+
+// BulkUpdateUserConnectorResources updates the connector resources, identified by their id, in a single transaction
+func (s *service) BulkUpdateUserConnectorResources(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, connectorResources []*connectorPB.ConnectorResource, allOrNothing bool) ([]*BulkItemResult, error) {
+	return s.runBulk(ctx, len(connectorResources), allOrNothing, func(s *service, idx int) (*connectorPB.ConnectorResource, error) {
+		return s.UpdateUserConnectorResourceByID(ctx, ns, userUid, connectorResources[idx].GetId(), connectorResources[idx])
+	})
+}
+
+// BulkDeleteUserConnectorResources deletes the connector resources in a single transaction, the pipelines using
+// them are listed once for the whole request
+func (s *service) BulkDeleteUserConnectorResources(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, ids []string, allOrNothing bool) ([]*BulkItemResult, error) {
+
+	if err := CheckBulkSize(len(ids)); err != nil {
+		return nil, err
+	}
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	dbConnectors := make([]*datamodel.ConnectorResource, len(ids))
+	permissionErrs := make([]error, len(ids))
+	connUIDs := []uuid.UUID{}
+	for idx, id := range ids {
+		dbConnectors[idx], permissionErrs[idx] = s.checkConnectorPermission(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorRoleAdmin)
+		if permissionErrs[idx] == nil {
+			connUIDs = append(connUIDs, dbConnectors[idx].UID)
+		}
+	}
+
+	pipeIDs, err := s.listConnectorPipelineIDs(ownerPermalink, connUIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.runBulk(ctx, len(ids), allOrNothing, func(s *service, idx int) (*connectorPB.ConnectorResource, error) {
+		if permissionErrs[idx] != nil {
+			return nil, permissionErrs[idx]
+		}
+		return nil, s.deleteConnectorResource(ctx, ownerPermalink, dbConnectors[idx], pipeIDs[dbConnectors[idx].UID])
+	})
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/instill-ai/connector-backend/internal/resource"
+	"github.com/instill-ai/connector-backend/pkg/repository"
+
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+	controllerPB "github.com/instill-ai/protogen-go/vdp/controller/v1alpha"
+)
+
+// fakeRepository runs the transactions without a database
+type fakeRepository struct {
+	repository.Repository
+}
+
+func (r *fakeRepository) Transaction(ctx context.Context, fn func(repo repository.Repository) error) error {
+	return fn(r)
+}
+
+// fakeControllerClient keeps the connector states in memory
+type fakeControllerClient struct {
+	controllerPB.ControllerPrivateServiceClient
+	states map[string]connectorPB.ConnectorResource_State
+}
+
+func (c *fakeControllerClient) GetResource(ctx context.Context, in *controllerPB.GetResourceRequest, opts ...grpc.CallOption) (*controllerPB.GetResourceResponse, error) {
+	state, ok := c.states[in.ResourcePermalink]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+	return &controllerPB.GetResourceResponse{
+		Resource: &controllerPB.Resource{
+			ResourcePermalink: in.ResourcePermalink,
+			State:             &controllerPB.Resource_ConnectorState{ConnectorState: state},
+		},
+	}, nil
+}
+
+func (c *fakeControllerClient) UpdateResource(ctx context.Context, in *controllerPB.UpdateResourceRequest, opts ...grpc.CallOption) (*controllerPB.UpdateResourceResponse, error) {
+	c.states[in.Resource.ResourcePermalink] = in.Resource.GetConnectorState()
+	return &controllerPB.UpdateResourceResponse{Resource: in.Resource}, nil
+}
+
+func (c *fakeControllerClient) DeleteResource(ctx context.Context, in *controllerPB.DeleteResourceRequest, opts ...grpc.CallOption) (*controllerPB.DeleteResourceResponse, error) {
+	delete(c.states, in.ResourcePermalink)
+	return &controllerPB.DeleteResourceResponse{}, nil
+}
+
+func TestRunBulkRevertsFailedItems(t *testing.T) {
+	created := uuid.Must(uuid.NewV4())
+	updated := uuid.Must(uuid.NewV4())
+	errItem := errors.New("append revision error")
+
+	testCases := []struct {
+		name         string
+		allOrNothing bool
+		process      func(s *service, idx int) (*connectorPB.ConnectorResource, error)
+		want         map[uuid.UUID]connectorPB.ConnectorResource_State
+	}{
+		{
+			name: "failed create in a nested transaction",
+			process: func(s *service, idx int) (*connectorPB.ConnectorResource, error) {
+				return nil, s.transaction(context.Background(), func(s *service) error {
+					if err := s.UpdateResourceState(created, connectorPB.ConnectorResource_STATE_DISCONNECTED, nil); err != nil {
+						return err
+					}
+					return errItem
+				})
+			},
+			want: map[uuid.UUID]connectorPB.ConnectorResource_State{
+				updated: connectorPB.ConnectorResource_STATE_CONNECTED,
+			},
+		},
+		{
+			name: "failed update",
+			process: func(s *service, idx int) (*connectorPB.ConnectorResource, error) {
+				if err := s.UpdateResourceState(updated, connectorPB.ConnectorResource_STATE_DISCONNECTED, nil); err != nil {
+					return nil, err
+				}
+				return nil, errItem
+			},
+			want: map[uuid.UUID]connectorPB.ConnectorResource_State{
+				updated: connectorPB.ConnectorResource_STATE_CONNECTED,
+			},
+		},
+		{
+			name:         "all or nothing",
+			allOrNothing: true,
+			process: func(s *service, idx int) (*connectorPB.ConnectorResource, error) {
+				if idx == 0 {
+					return &connectorPB.ConnectorResource{}, s.transaction(context.Background(), func(s *service) error {
+						return s.UpdateResourceState(created, connectorPB.ConnectorResource_STATE_CONNECTED, nil)
+					})
+				}
+				return nil, s.transaction(context.Background(), func(s *service) error {
+					if err := s.UpdateResourceState(updated, connectorPB.ConnectorResource_STATE_ERROR, nil); err != nil {
+						return err
+					}
+					return errItem
+				})
+			},
+			want: map[uuid.UUID]connectorPB.ConnectorResource_State{
+				updated: connectorPB.ConnectorResource_STATE_CONNECTED,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			controller := &fakeControllerClient{states: map[string]connectorPB.ConnectorResource_State{
+				resource.ConvertConnectorToResourceName(updated.String()): connectorPB.ConnectorResource_STATE_CONNECTED,
+			}}
+			s := &service{repository: &fakeRepository{}, controllerClient: controller}
+
+			size := 1
+			if tc.allOrNothing {
+				size = 2
+			}
+			results, err := s.runBulk(context.Background(), size, tc.allOrNothing, tc.process)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, result := range results {
+				if result.Status.Code() == codes.OK {
+					t.Errorf("got an OK item, want every item failed or aborted")
+				}
+			}
+
+			want := map[string]connectorPB.ConnectorResource_State{}
+			for connectorUID, state := range tc.want {
+				want[resource.ConvertConnectorToResourceName(connectorUID.String())] = state
+			}
+			if len(controller.states) != len(want) {
+				t.Fatalf("got states %v, want %v", controller.states, want)
+			}
+			for permalink, state := range want {
+				if controller.states[permalink] != state {
+					t.Errorf("got state %s of %s, want %s", controller.states[permalink], permalink, state)
+				}
+			}
+		})
+	}
+}
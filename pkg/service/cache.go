@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/instill-ai/connector-backend/internal/resource"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/x/sterr"
+)
+
+// MaxCacheTTL is the maximum time the execution results of a connector can be cached
+const MaxCacheTTL = 30 * 24 * time.Hour
+
+// executionCacheKey returns the Redis key of the cached outputs of an execution, it changes with the
+// configuration of the connector, the task and the inputs. The inputs are hashed in their canonical JSON form,
+// i.e. with sorted keys, so that equal inputs share the key whatever their field order.
+func executionCacheKey(dbConnectorResource *datamodel.ConnectorResource, task string, inputs []*structpb.Struct) (string, error) {
+
+	canonicalInputs := make([]map[string]interface{}, len(inputs))
+	for i, input := range inputs {
+		canonicalInputs[i] = input.AsMap()
+	}
+	b, err := json.Marshal(canonicalInputs)
+	if err != nil {
+		return "", err
+	}
+
+	configHash := sha256.Sum256(dbConnectorResource.Configuration)
+	inputHash := sha256.Sum256(b)
+	return fmt.Sprintf("connector:%s:execution_cache:%s:%s:%s",
+		dbConnectorResource.UID, hex.EncodeToString(configHash[:]), task, hex.EncodeToString(inputHash[:])), nil
+}
+
+// getCachedOutputs returns the cached outputs of the execution, nil if they are not cached
+func (s *service) getCachedOutputs(ctx context.Context, key string) ([]*structpb.Struct, error) {
+
+	b, err := s.redisClient.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	list := &structpb.ListValue{}
+	if err := protojson.Unmarshal(b, list); err != nil {
+		return nil, err
+	}
+	outputs := make([]*structpb.Struct, len(list.GetValues()))
+	for i, v := range list.GetValues() {
+		outputs[i] = v.GetStructValue()
+	}
+	return outputs, nil
+}
+
+func (s *service) setCachedOutputs(ctx context.Context, key string, outputs []*structpb.Struct, ttl time.Duration) error {
+
+	list := &structpb.ListValue{}
+	for _, output := range outputs {
+		list.Values = append(list.Values, structpb.NewStructValue(output))
+	}
+	b, err := protojson.Marshal(list)
+	if err != nil {
+		return err
+	}
+	return s.redisClient.Set(ctx, key, b, ttl).Err()
+}
+
+// executeCachedConnector returns the cached outputs of the execution if the connector caches its results,
+// otherwise it executes the connector and caches the outputs. The cache is skipped while Redis is unreachable.
+func (s *service) executeCachedConnector(ctx context.Context, dbConnectorResource *datamodel.ConnectorResource, task string, inputs []*structpb.Struct) (*ExecutionResult, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	if dbConnectorResource.CacheTTL <= 0 {
+		return s.executeConnector(ctx, dbConnectorResource, task, inputs)
+	}
+
+	key, err := executionCacheKey(dbConnectorResource, task, inputs)
+	if err != nil {
+		return &ExecutionResult{}, err
+	}
+
+	outputs, err := s.getCachedOutputs(ctx, key)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("execution cache of connector %s not read: %s", dbConnectorResource.UID, err))
+	}
+	if outputs != nil {
+		return &ExecutionResult{Outputs: outputs, CacheHit: true}, nil
+	}
+
+	result, err := s.executeConnector(ctx, dbConnectorResource, task, inputs)
+	if err != nil {
+		return result, err
+	}
+
+	if err := s.setCachedOutputs(ctx, key, result.Outputs, time.Duration(dbConnectorResource.CacheTTL)*time.Second); err != nil {
+		logger.Warn(fmt.Sprintf("execution cache of connector %s not written: %s", dbConnectorResource.UID, err))
+	}
+	return result, nil
+}
+
+func (s *service) GetUserConnectorCacheTTL(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string) (time.Duration, error) {
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	dbConnectorResource, err := s.checkConnectorPermission(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorRoleViewer)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(dbConnectorResource.CacheTTL) * time.Second, nil
+}
+
+// UpdateUserConnectorCacheTTL sets how long the execution results of the connector are cached, 0 disables the
+// cache. The connector tasks must be deterministic for their results to be cached.
+func (s *service) UpdateUserConnectorCacheTTL(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, ttl time.Duration) (time.Duration, error) {
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	if ttl < 0 || ttl > MaxCacheTTL || ttl%time.Second != 0 {
+		st, _ := sterr.CreateErrorBadRequest(
+			"[service] invalid cache ttl",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "ttl",
+					Description: fmt.Sprintf("the ttl must be a whole number of seconds up to %s, 0 disables the cache", MaxCacheTTL),
+				},
+			},
+		)
+		return 0, st.Err()
+	}
+
+	if _, err := s.checkConnectorPermission(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorRoleEditor); err != nil {
+		return 0, err
+	}
+
+	if err := s.repository.UpdateUserConnectorResourceCacheTTLByID(ctx, ownerPermalink, ownerPermalink, id, int32(ttl/time.Second)); err != nil {
+		return 0, err
+	}
+
+	return s.GetUserConnectorCacheTTL(ctx, ns, userUid, id)
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/instill-ai/connector-backend/config"
+	"github.com/instill-ai/connector-backend/internal/resource"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/connector-backend/pkg/utils"
+)
+
+// Defaults of the debug capture when they are not configured
+const (
+	defaultCaptureTTL     = 24 * time.Hour
+	defaultCaptureMaxSize = 1024 * 1024
+)
+
+// marshalCapturedPayload returns the JSON list of the payload with the credential-like fields redacted, the
+// payload itself is left untouched
+func marshalCapturedPayload(payload []*structpb.Struct) ([]byte, error) {
+	list := &structpb.ListValue{}
+	for _, item := range payload {
+		if item == nil {
+			list.Values = append(list.Values, structpb.NewNullValue())
+			continue
+		}
+		redacted := proto.Clone(item).(*structpb.Struct)
+		utils.RedactCredentialLikeFields(redacted)
+		list.Values = append(list.Values, structpb.NewStructValue(redacted))
+	}
+	return protojson.Marshal(list)
+}
+
+// captureExecution stores the payloads of the execution if the debug capture of the connector is enabled. The
+// execution is not failed if the payloads can not be captured.
+func (s *service) captureExecution(ctx context.Context, dbConnectorResource *datamodel.ConnectorResource, executeUID uuid.UUID, inputs []*structpb.Struct, outputs []*structpb.Struct, errorMessage string) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	if !dbConnectorResource.DebugCapture {
+		return
+	}
+
+	ttl := config.Config.DebugCapture.TTL
+	if ttl <= 0 {
+		ttl = defaultCaptureTTL
+	}
+	maxSize := config.Config.DebugCapture.MaxSize
+	if maxSize <= 0 {
+		maxSize = defaultCaptureMaxSize
+	}
+
+	capture := &datamodel.ConnectorCapture{
+		UID:          executeUID,
+		ConnectorUID: dbConnectorResource.UID,
+		ErrorMessage: errorMessage,
+		ExpireTime:   time.Now().Add(ttl),
+	}
+
+	var err error
+	if capture.Inputs, err = marshalCapturedPayload(inputs); err != nil {
+		logger.Warn(fmt.Sprintf("execution %s not captured: %s", executeUID, err))
+		return
+	}
+	if outputs != nil {
+		if capture.Outputs, err = marshalCapturedPayload(outputs); err != nil {
+			logger.Warn(fmt.Sprintf("execution %s not captured: %s", executeUID, err))
+			return
+		}
+	}
+	if len(capture.Inputs)+len(capture.Outputs) > maxSize {
+		capture.Inputs, capture.Outputs, capture.Truncated = nil, nil, true
+	}
+
+	if err := s.repository.CreateConnectorCapture(ctx, capture); err != nil {
+		logger.Warn(fmt.Sprintf("execution %s not captured: %s", executeUID, err))
+	}
+}
+
+func (s *service) GetUserConnectorDebugCapture(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string) (bool, error) {
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	dbConnectorResource, err := s.checkConnectorPermission(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorRoleViewer)
+	if err != nil {
+		return false, err
+	}
+	return dbConnectorResource.DebugCapture, nil
+}
+
+// UpdateUserConnectorDebugCapture enables or disables the capture of the payloads of the connector executions
+func (s *service) UpdateUserConnectorDebugCapture(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, enabled bool) (bool, error) {
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	if _, err := s.checkConnectorPermission(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorRoleEditor); err != nil {
+		return false, err
+	}
+
+	if err := s.repository.UpdateUserConnectorResourceDebugCaptureByID(ctx, ownerPermalink, ownerPermalink, id, enabled); err != nil {
+		return false, err
+	}
+
+	return s.GetUserConnectorDebugCapture(ctx, ns, userUid, id)
+}
+
+// GetUserConnectorCapture returns the captured payloads of an execution, only the editors of the connector can
+// read them as they can hold the data of any user of the connector
+func (s *service) GetUserConnectorCapture(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, uid uuid.UUID) (*datamodel.ConnectorCapture, error) {
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	dbConnectorResource, err := s.checkConnectorPermission(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repository.GetConnectorCapture(ctx, dbConnectorResource.UID, uid)
+}
+
+// PurgeConnectorCaptures deletes the expired captures
+func (s *service) PurgeConnectorCaptures(ctx context.Context) (int64, error) {
+	return s.repository.PurgeConnectorCaptures(ctx, time.Now())
+}
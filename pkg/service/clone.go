@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"github.com/gogo/status"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/instill-ai/connector-backend/internal/resource"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/utils"
+	"github.com/instill-ai/x/sterr"
+
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+// mergeConfiguration merges the override into the configuration in place, the nested objects are merged field
+// by field and the other values are replaced
+func mergeConfiguration(configuration *structpb.Struct, override *structpb.Struct) {
+	for k, v := range override.GetFields() {
+		if nested := v.GetStructValue(); nested != nil {
+			if existing := configuration.GetFields()[k].GetStructValue(); existing != nil {
+				mergeConfiguration(existing, nested)
+				continue
+			}
+		}
+		configuration.Fields[k] = v
+	}
+}
+
+// CloneUserConnectorResource copies a connector, including its stored credentials, under a new id in the target
+// namespace. The override is merged into the copied configuration, its masked credential fields are ignored.
+// A clone in another namespace is rejected if it keeps secret references. The clone is created like
+// CreateUserConnectorResource does, it starts disconnected, and gets the retry policy, the maximum concurrency and
+// the cache TTL of the connector.
+func (s *service) CloneUserConnectorResource(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, targetNs resource.Namespace, newID string, description *string, override *structpb.Struct) (*connectorPB.ConnectorResource, error) {
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	// Roles are granted per connector, only the owner of a namespace can create connectors in it
+	if targetNs.String() != userPermalink {
+		st, _ := sterr.CreateErrorBadRequest(
+			"[service] clone connector error",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "parent",
+					Description: "can not clone into other user's namespace",
+				},
+			},
+		)
+		return nil, st.Err()
+	}
+
+	// The credentials are copied, they must be writable by the user
+	dbConnectorResource, err := s.checkConnectorPermission(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	connDef, err := s.connectors.GetConnectorDefinitionByUID(dbConnectorResource.ConnectorDefinitionUID)
+	if err != nil {
+		return nil, err
+	}
+
+	configuration := &structpb.Struct{Fields: map[string]*structpb.Value{}}
+	if dbConnectorResource.Configuration != nil {
+		if err := configuration.UnmarshalJSON(dbConnectorResource.Configuration); err != nil {
+			return nil, err
+		}
+	}
+	if err := utils.DecryptCredentialFields(s.connectors, connDef.GetId(), configuration, s.envelope); err != nil {
+		return nil, status.Errorf(codes.Internal, "[service] decrypt connector configuration error: %s", err.Error())
+	}
+	if override != nil {
+		s.RemoveCredentialFieldsWithMaskString(connDef.GetId(), override)
+		mergeConfiguration(configuration, override)
+	}
+
+	// The secret references are resolved in the namespace of the connector, a clone in another namespace would
+	// resolve them to the secrets of the same ids there
+	if targetNs.String() != dbConnectorResource.Owner {
+		references := []string{}
+		if err := utils.ResolveSecretReferences(proto.Clone(configuration).(*structpb.Struct), func(id string) (string, error) {
+			references = append(references, utils.SecretReference(id))
+			return "", nil
+		}); err != nil {
+			return nil, err
+		}
+		if len(references) > 0 {
+			sort.Strings(references)
+			st, _ := sterr.CreateErrorPreconditionFailure(
+				"[service] clone connector",
+				[]*errdetails.PreconditionFailure_Violation{
+					{
+						Type:        "CLONE",
+						Subject:     fmt.Sprintf("id %s", id),
+						Description: fmt.Sprintf("the secret references %s belong to the namespace of the connector, replace them in the configuration of the clone", strings.Join(references, ", ")),
+					},
+				})
+			return nil, st.Err()
+		}
+	}
+
+	if description == nil {
+		description = &dbConnectorResource.Description.String
+	}
+
+	connectorResource := &connectorPB.ConnectorResource{
+		Id:                      newID,
+		ConnectorDefinitionName: connDef.GetName(),
+		Description:             description,
+		Visibility:              connectorPB.ConnectorResource_Visibility(dbConnectorResource.Visibility),
+		Configuration:           configuration,
+	}
+
+	// The clone is created in a transaction, the copy of the settings rolls it back if it fails
+	results, err := s.runBulk(ctx, 1, true, func(s *service, _ int) (*connectorPB.ConnectorResource, error) {
+
+		clone, err := s.CreateUserConnectorResource(ctx, targetNs, userUid, connectorResource)
+		if err != nil {
+			return nil, err
+		}
+
+		targetPermalink := targetNs.String()
+		if dbConnectorResource.RetryPolicy != nil {
+			if err := s.repository.UpdateUserConnectorResourceRetryPolicyByID(ctx, targetPermalink, userPermalink, newID, dbConnectorResource.RetryPolicy); err != nil {
+				return nil, err
+			}
+		}
+		if err := s.repository.UpdateUserConnectorResourceMaxConcurrencyByID(ctx, targetPermalink, userPermalink, newID, dbConnectorResource.MaxConcurrency); err != nil {
+			return nil, err
+		}
+		if err := s.repository.UpdateUserConnectorResourceCacheTTLByID(ctx, targetPermalink, userPermalink, newID, dbConnectorResource.CacheTTL); err != nil {
+			return nil, err
+		}
+
+		return clone, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if results[0].Status.Code() != codes.OK {
+		return nil, results[0].Status.Err()
+	}
+
+	return results[0].ConnectorResource, nil
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/instill-ai/connector-backend/config"
+	"github.com/instill-ai/connector-backend/internal/resource"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/x/sterr"
+)
+
+// MaxConcurrencyLimit is the maximum max_concurrency of a connector resource
+const MaxConcurrencyLimit = 1000
+
+// Defaults of the execution queue when they are not configured
+const (
+	defaultSlotLease         = 30 * time.Second
+	defaultQueueTimeout      = 60 * time.Second
+	defaultQueuePollInterval = 100 * time.Millisecond
+)
+
+var meter = otel.Meter("connector-backend.service.meter")
+
+// The executions waiting for a concurrency slot and how long they have waited
+var (
+	executionQueueDepth, _ = meter.Int64UpDownCounter(
+		"connector.execution.queue_depth",
+		metric.WithDescription("Executions waiting for a concurrency slot of their connector"),
+	)
+	executionQueueWait, _ = meter.Float64Histogram(
+		"connector.execution.queue_wait",
+		metric.WithDescription("Time an execution has waited for a concurrency slot of its connector"),
+		metric.WithUnit("s"),
+	)
+)
+
+// acquireSlotScript takes a concurrency slot of a connector. KEYS[1] is a sorted set of the slot holders scored
+// by the expiry of their lease and ARGV holds the current time and the lease expiry in milliseconds, the max
+// concurrency, the holder token and the lease in milliseconds. The expired leases of crashed holders are dropped
+// first. It returns 1 if the slot is taken, 0 if they are all held.
+var acquireSlotScript = redis.NewScript(`
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", ARGV[1])
+if redis.call("ZCARD", KEYS[1]) >= tonumber(ARGV[3]) then
+  return 0
+end
+redis.call("ZADD", KEYS[1], ARGV[2], ARGV[4])
+redis.call("PEXPIRE", KEYS[1], ARGV[5])
+return 1
+`)
+
+// executionSlotsKey is the Redis key of the concurrency slots of a connector
+func executionSlotsKey(connUID uuid.UUID) string {
+	return fmt.Sprintf("connector:%s:execution_slots", connUID)
+}
+
+// acquireExecutionSlot waits for a concurrency slot of a connector with a max concurrency, the slots are shared
+// across the replicas. The lease of the slot is renewed until the returned function releases it. An execution
+// still waiting after the queue timeout fails with ResourceExhausted. The max concurrency is not enforced while
+// Redis is unreachable.
+func (s *service) acquireExecutionSlot(ctx context.Context, dbConnectorResource *datamodel.ConnectorResource) (func(), error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	noop := func() {}
+	if dbConnectorResource.MaxConcurrency <= 0 {
+		return noop, nil
+	}
+
+	lease := config.Config.Execution.SlotLease
+	if lease <= 0 {
+		lease = defaultSlotLease
+	}
+	timeout := config.Config.Execution.QueueTimeout
+	if timeout <= 0 {
+		timeout = defaultQueueTimeout
+	}
+	pollInterval := config.Config.Execution.QueuePollInterval
+	if pollInterval <= 0 {
+		pollInterval = defaultQueuePollInterval
+	}
+
+	key := executionSlotsKey(dbConnectorResource.UID)
+	token := uuid.Must(uuid.NewV4()).String()
+	attrs := metric.WithAttributes(attribute.String("connector_uid", dbConnectorResource.UID.String()))
+
+	startTime := time.Now()
+	executionQueueDepth.Add(ctx, 1, attrs)
+	defer executionQueueDepth.Add(ctx, -1, attrs)
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	for {
+		now := time.Now()
+		acquired, err := acquireSlotScript.Run(ctx, s.redisClient, []string{key},
+			now.UnixMilli(), now.Add(lease).UnixMilli(), dbConnectorResource.MaxConcurrency, token, lease.Milliseconds()).Int()
+		if err != nil {
+			logger.Warn(fmt.Sprintf("max concurrency of connector %s not enforced: %s", dbConnectorResource.UID, err))
+			return noop, nil
+		}
+		if acquired == 1 {
+			executionQueueWait.Record(ctx, time.Since(startTime).Seconds(), attrs)
+			return s.holdExecutionSlot(key, token, lease), nil
+		}
+
+		poll := time.NewTimer(pollInterval)
+		select {
+		case <-ctx.Done():
+			poll.Stop()
+			executionQueueWait.Record(ctx, time.Since(startTime).Seconds(), attrs)
+			return nil, status.FromContextError(ctx.Err()).Err()
+		case <-deadline.C:
+			poll.Stop()
+			executionQueueWait.Record(ctx, time.Since(startTime).Seconds(), attrs)
+			return nil, status.Errorf(codes.ResourceExhausted,
+				"[service] the %d concurrent executions of the connector are still running after %s", dbConnectorResource.MaxConcurrency, timeout)
+		case <-poll.C:
+		}
+	}
+}
+
+// holdExecutionSlot renews the lease of a slot until the returned function releases it
+func (s *service) holdExecutionSlot(key string, token string, lease time.Duration) func() {
+
+	// The slot outlives the request context, it is released once the execution returns
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		ticker := time.NewTicker(lease / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.redisClient.ZAddXX(ctx, key, redis.Z{Score: float64(time.Now().Add(lease).UnixMilli()), Member: token})
+				s.redisClient.PExpire(ctx, key, lease)
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		s.redisClient.ZRem(context.Background(), key, token)
+	}
+}
+
+func (s *service) GetUserConnectorMaxConcurrency(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string) (int32, error) {
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	dbConnectorResource, err := s.checkConnectorPermission(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorRoleViewer)
+	if err != nil {
+		return 0, err
+	}
+	return dbConnectorResource.MaxConcurrency, nil
+}
+
+// UpdateUserConnectorMaxConcurrency sets the number of executions of the connector that can run at once, 0
+// lifts the limit
+func (s *service) UpdateUserConnectorMaxConcurrency(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, maxConcurrency int32) (int32, error) {
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	if maxConcurrency < 0 || maxConcurrency > MaxConcurrencyLimit {
+		st, _ := sterr.CreateErrorBadRequest(
+			"[service] invalid max concurrency",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "max_concurrency",
+					Description: fmt.Sprintf("the max concurrency must be between 0 and %d, 0 lifts the limit", MaxConcurrencyLimit),
+				},
+			},
+		)
+		return 0, st.Err()
+	}
+
+	if _, err := s.checkConnectorPermission(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorRoleEditor); err != nil {
+		return 0, err
+	}
+
+	if err := s.repository.UpdateUserConnectorResourceMaxConcurrencyByID(ctx, ownerPermalink, ownerPermalink, id, maxConcurrency); err != nil {
+		return 0, err
+	}
+
+	return s.GetUserConnectorMaxConcurrency(ctx, ns, userUid, id)
+}
@@ -2,10 +2,12 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/instill-ai/connector-backend/internal/resource"
+	"github.com/instill-ai/connector-backend/pkg/logger"
 	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
 	controllerPB "github.com/instill-ai/protogen-go/vdp/controller/v1alpha"
 )
@@ -28,6 +30,8 @@ func (s *service) GetResourceState(connectorUID uuid.UUID) (*connectorPB.Connect
 }
 
 func (s *service) UpdateResourceState(connectorUID uuid.UUID, state connectorPB.ConnectorResource_State, progress *int32) error {
+	s.journalResourceState(connectorUID)
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -52,6 +56,8 @@ func (s *service) UpdateResourceState(connectorUID uuid.UUID, state connectorPB.
 }
 
 func (s *service) DeleteResourceState(connectorUID uuid.UUID) error {
+	s.journalResourceState(connectorUID)
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -67,3 +73,55 @@ func (s *service) DeleteResourceState(connectorUID uuid.UUID) error {
 
 	return nil
 }
+
+// controllerChange records the state of a resource before it is changed on the controller, Previous is nil if
+// the resource had no state
+type controllerChange struct {
+	ConnectorUID uuid.UUID
+	Previous     *connectorPB.ConnectorResource_State
+}
+
+// controllerJournal records the state changes made on the controller to revert them if the database writes they
+// go with are rolled back
+type controllerJournal struct {
+	changes []controllerChange
+}
+
+// journalResourceState records the current state of a resource if the service has a journal
+func (s *service) journalResourceState(connectorUID uuid.UUID) {
+	if s.journal == nil {
+		return
+	}
+	previous, err := s.GetResourceState(connectorUID)
+	if err != nil {
+		previous = nil
+	}
+	s.journal.changes = append(s.journal.changes, controllerChange{
+		ConnectorUID: connectorUID,
+		Previous:     previous,
+	})
+}
+
+// revertResourceStates restores the states recorded in the journal, the latest change first. The reverts are not
+// journaled themselves, otherwise reverting the journal of the service would undo them.
+func (s *service) revertResourceStates(ctx context.Context, journal *controllerJournal) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	untracked := *s
+	untracked.journal = nil
+
+	for i := len(journal.changes) - 1; i >= 0; i-- {
+		change := journal.changes[i]
+		var err error
+		if change.Previous == nil {
+			err = untracked.DeleteResourceState(change.ConnectorUID)
+		} else {
+			err = untracked.UpdateResourceState(change.ConnectorUID, *change.Previous, nil)
+		}
+		if err != nil {
+			logger.Warn(fmt.Sprintf("state of connector %s not reverted: %s", change.ConnectorUID, err))
+		}
+	}
+	journal.changes = nil
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/instill-ai/connector-backend/config"
+	"github.com/instill-ai/connector-backend/internal/resource"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/connector-backend/pkg/utils"
+	"github.com/instill-ai/x/sterr"
+
+	mgmtPB "github.com/instill-ai/protogen-go/core/mgmt/v1alpha"
+)
+
+// MaxReplayedDeadLetters is the maximum number of failed executions replayed at once
+const MaxReplayedDeadLetters = 100
+
+// Defaults of the dead letters when they are not configured
+const (
+	defaultDeadLetterTTL     = 72 * time.Hour
+	defaultDeadLetterMaxSize = 1024 * 1024
+)
+
+// ReplayItemResult is the result of the replay of one input of a failed execution, ExecuteUID identifies the
+// new execution of the input
+type ReplayItemResult struct {
+	ExecuteUID uuid.UUID
+	Status     *status.Status
+}
+
+// ReplayResult holds the results of the replay of a failed execution aligned with its inputs
+type ReplayResult struct {
+	ExecuteUID uuid.UUID
+	Items      []*ReplayItemResult
+}
+
+// storeDeadLetter keeps the inputs of a failed execution to be replayed, the inputs over the size limit are not
+// kept
+func (s *service) storeDeadLetter(ctx context.Context, dbConnectorResource *datamodel.ConnectorResource, executeUID uuid.UUID, userUid uuid.UUID, task string, inputs []*structpb.Struct, errorMessage string) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	ttl := config.Config.DeadLetter.TTL
+	if ttl <= 0 {
+		ttl = defaultDeadLetterTTL
+	}
+	maxSize := config.Config.DeadLetter.MaxSize
+	if maxSize <= 0 {
+		maxSize = defaultDeadLetterMaxSize
+	}
+
+	list := &structpb.ListValue{}
+	for _, input := range inputs {
+		list.Values = append(list.Values, structpb.NewStructValue(input))
+	}
+	b, err := protojson.Marshal(list)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("inputs of the failed execution %s not kept: %s", executeUID, err))
+		return
+	}
+	if len(b) > maxSize {
+		logger.Warn(fmt.Sprintf("inputs of the failed execution %s not kept: %d bytes over the %d bytes limit", executeUID, len(b), maxSize))
+		return
+	}
+
+	if err := s.repository.CreateConnectorDeadLetter(ctx, &datamodel.ConnectorDeadLetter{
+		UID:          executeUID,
+		ConnectorUID: dbConnectorResource.UID,
+		UserUID:      userUid,
+		Task:         task,
+		Inputs:       b,
+		ErrorMessage: errorMessage,
+		ExpireTime:   time.Now().Add(ttl),
+	}); err != nil {
+		logger.Warn(fmt.Sprintf("inputs of the failed execution %s not kept: %s", executeUID, err))
+	}
+}
+
+// replayDeadLetter executes each input of the failed execution on its own with the current configuration of
+// the connector. The dead letter is consumed, the inputs still failing are kept under their new execute uid.
+func (s *service) replayDeadLetter(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, dbConnectorResource *datamodel.ConnectorResource, deadLetter *datamodel.ConnectorDeadLetter) (*ReplayResult, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	list := &structpb.ListValue{}
+	if err := protojson.Unmarshal(deadLetter.Inputs, list); err != nil {
+		return nil, err
+	}
+
+	replay := &ReplayResult{ExecuteUID: deadLetter.UID}
+	for _, v := range list.GetValues() {
+		input := v.GetStructValue()
+		executeUID := uuid.Must(uuid.NewV4())
+
+		startTime := time.Now()
+		result, err := s.Execute(ctx, ns, userUid, id, executeUID, deadLetter.Task, []*structpb.Struct{input})
+
+		dataPoint := utils.UsageMetricData{
+			OwnerUID:               userUid.String(),
+			ConnectorID:            dbConnectorResource.ID,
+			ConnectorUID:           dbConnectorResource.UID.String(),
+			ConnectorExecuteUID:    executeUID.String(),
+			ConnectorDefinitionUid: dbConnectorResource.ConnectorDefinitionUID.String(),
+			ExecuteTime:            startTime.Format(time.RFC3339Nano),
+			ComputeTimeDuration:    time.Since(startTime).Seconds(),
+			Status:                 mgmtPB.Status_STATUS_COMPLETED,
+			SucceededCount:         1,
+			Attempts:               result.Attempts,
+			CacheHit:               result.CacheHit,
+			Task:                   deadLetter.Task,
+			InputSize:              utils.PayloadSize([]*structpb.Struct{input}),
+			OutputSize:             utils.PayloadSize(result.Outputs),
+		}
+		item := &ReplayItemResult{ExecuteUID: executeUID, Status: status.New(codes.OK, "")}
+		if err != nil {
+			dataPoint.Status = mgmtPB.Status_STATUS_ERRORED
+			dataPoint.SucceededCount, dataPoint.FailedCount = 0, 1
+			dataPoint.ErrorMessage = err.Error()
+			item.Status = status.Convert(err)
+		}
+		if err := s.WriteNewDataPoint(ctx, dataPoint, &structpb.Value{}); err != nil {
+			logger.Warn("usage and metric data write fail")
+		}
+		replay.Items = append(replay.Items, item)
+	}
+
+	if err := s.repository.DeleteConnectorDeadLetter(ctx, deadLetter.UID); err != nil {
+		logger.Error(err.Error())
+	}
+	return replay, nil
+}
+
+// ReplayUserConnectorExecution replays the inputs of a failed execution of the connector
+func (s *service) ReplayUserConnectorExecution(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, uid uuid.UUID) (*ReplayResult, error) {
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	dbConnectorResource, err := s.checkConnectorPermission(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorRoleExecutor)
+	if err != nil {
+		return nil, err
+	}
+
+	deadLetter, err := s.repository.GetConnectorDeadLetter(ctx, dbConnectorResource.UID, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.replayDeadLetter(ctx, ns, userUid, id, dbConnectorResource, deadLetter)
+}
+
+// ReplayUserConnectorFailedExecutions replays the oldest failed executions of the connector within the window,
+// at most MaxReplayedDeadLetters at once
+func (s *service) ReplayUserConnectorFailedExecutions(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, window time.Duration) ([]*ReplayResult, error) {
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	if window <= 0 {
+		st, _ := sterr.CreateErrorBadRequest(
+			"[service] invalid replay window",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "window",
+					Description: "the window must be a positive duration",
+				},
+			},
+		)
+		return nil, st.Err()
+	}
+
+	dbConnectorResource, err := s.checkConnectorPermission(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorRoleExecutor)
+	if err != nil {
+		return nil, err
+	}
+
+	deadLetters, err := s.repository.ListConnectorDeadLetters(ctx, dbConnectorResource.UID, time.Now().Add(-window), MaxReplayedDeadLetters)
+	if err != nil {
+		return nil, err
+	}
+
+	replays := []*ReplayResult{}
+	for _, deadLetter := range deadLetters {
+		replay, err := s.replayDeadLetter(ctx, ns, userUid, id, dbConnectorResource, deadLetter)
+		if err != nil {
+			return nil, err
+		}
+		replays = append(replays, replay)
+	}
+	return replays, nil
+}
+
+// PurgeConnectorDeadLetters deletes the expired dead letters
+func (s *service) PurgeConnectorDeadLetters(ctx context.Context) (int64, error) {
+	return s.repository.PurgeConnectorDeadLetters(ctx, time.Now())
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/instill-ai/connector-backend/internal/resource"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+// escapePointerToken escapes a property name to be used in a JSON pointer (RFC 6901)
+func escapePointerToken(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// applyConfigurationDefaults fills the missing properties of the configuration with the default values of the
+// schema, the nested objects present in the configuration are filled too. It returns the JSON pointers of the
+// filled properties, e.g. "/configuration/model".
+func applyConfigurationDefaults(schema map[string]interface{}, configuration *structpb.Struct, pointer string) []string {
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	defaulted := []string{}
+	for _, key := range keys {
+		property, ok := properties[key].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		fieldPointer := pointer + "/" + escapePointerToken(key)
+
+		value, exists := configuration.GetFields()[key]
+		if !exists {
+			defaultValue, hasDefault := property["default"]
+			if !hasDefault {
+				continue
+			}
+			v, err := structpb.NewValue(defaultValue)
+			if err != nil {
+				continue
+			}
+			if configuration.Fields == nil {
+				configuration.Fields = map[string]*structpb.Value{}
+			}
+			configuration.Fields[key] = v
+			defaulted = append(defaulted, fieldPointer)
+			continue
+		}
+		if nested := value.GetStructValue(); nested != nil {
+			defaulted = append(defaulted, applyConfigurationDefaults(property, nested, fieldPointer)...)
+		}
+	}
+
+	return defaulted
+}
+
+// defaultConfiguration fills the configuration with the defaults of the resource specification of the definition
+func defaultConfiguration(def *connectorPB.ConnectorDefinition, configuration *structpb.Struct) []string {
+	if def.GetSpec().GetResourceSpecification() == nil {
+		return []string{}
+	}
+	return applyConfigurationDefaults(def.GetSpec().GetResourceSpecification().AsMap(), configuration, "/configuration")
+}
+
+// pointerTokens splits a JSON pointer built by applyConfigurationDefaults into its unescaped property names
+func pointerTokens(pointer string) []string {
+	tokens := strings.Split(strings.TrimPrefix(pointer, "/configuration/"), "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens
+}
+
+// stillDefaulted keeps the defaulted fields whose value in the updated configuration is still the default one
+func stillDefaulted(def *connectorPB.ConnectorDefinition, defaultedFields []string, configuration *structpb.Struct) []string {
+
+	kept := []string{}
+	if def.GetSpec().GetResourceSpecification() == nil {
+		return kept
+	}
+	spec := def.GetSpec().GetResourceSpecification().AsMap()
+
+	for _, pointer := range defaultedFields {
+		schema := spec
+		value := structpb.NewStructValue(configuration)
+		for _, token := range pointerTokens(pointer) {
+			properties, _ := schema["properties"].(map[string]interface{})
+			schema, _ = properties[token].(map[string]interface{})
+			value = value.GetStructValue().GetFields()[token]
+		}
+		if value == nil || schema == nil {
+			continue
+		}
+		defaultValue, err := structpb.NewValue(schema["default"])
+		if err == nil && proto.Equal(value, defaultValue) {
+			kept = append(kept, pointer)
+		}
+	}
+	return kept
+}
+
+// unmarshalDefaultedFields returns the defaulted fields stored with a connector
+func unmarshalDefaultedFields(dbConnectorResource *datamodel.ConnectorResource) []string {
+	defaultedFields := []string{}
+	if len(dbConnectorResource.DefaultedFields) > 0 {
+		_ = json.Unmarshal(dbConnectorResource.DefaultedFields, &defaultedFields)
+	}
+	return defaultedFields
+}
+
+// GetUserConnectorDefaultedFields returns the JSON pointers of the configuration fields filled from the defaults
+// of the connector definition when the connector was created
+func (s *service) GetUserConnectorDefaultedFields(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string) ([]string, error) {
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	dbConnectorResource, err := s.checkConnectorPermission(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalDefaultedFields(dbConnectorResource), nil
+}
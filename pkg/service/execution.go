@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"go.einride.tech/aip/filtering"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/instill-ai/connector-backend/config"
+	"github.com/instill-ai/connector-backend/internal/resource"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/utils"
+
+	mgmtPB "github.com/instill-ai/protogen-go/core/mgmt/v1alpha"
+)
+
+// defaultExecutionHistoryRetention is how long an execution is kept in the history when it is not configured
+const defaultExecutionHistoryRetention = 30 * 24 * time.Hour
+
+// recordExecution appends the execution of the usage data point to the history of its connector
+func (s *service) recordExecution(ctx context.Context, data utils.UsageMetricData, pipelineMetadata *structpb.Value) error {
+
+	uid, err := uuid.FromString(data.ConnectorExecuteUID)
+	if err != nil {
+		return err
+	}
+	connUID, err := uuid.FromString(data.ConnectorUID)
+	if err != nil {
+		return err
+	}
+	userUID, err := uuid.FromString(data.OwnerUID)
+	if err != nil {
+		return err
+	}
+
+	execution := &datamodel.ConnectorExecution{
+		UID:          uid,
+		ConnectorUID: connUID,
+		UserUID:      userUID,
+		Task:         data.Task,
+		Status:       datamodel.ExecutionStatusCompleted,
+		Duration:     data.ComputeTimeDuration,
+		ErrorMessage: data.ErrorMessage,
+		InputSize:    int64(data.InputSize),
+		OutputSize:   int64(data.OutputSize),
+	}
+	if data.Status == mgmtPB.Status_STATUS_ERRORED {
+		execution.Status = datamodel.ExecutionStatusErrored
+	}
+	// The execution has not been triggered by a pipeline if the metadata has no field
+	if len(pipelineMetadata.GetStructValue().GetFields()) > 0 {
+		if execution.Pipeline, err = protojson.Marshal(pipelineMetadata.GetStructValue()); err != nil {
+			return err
+		}
+	}
+
+	return s.repository.CreateConnectorExecution(ctx, execution)
+}
+
+func (s *service) ListUserConnectorExecutions(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, pageSize int64, pageToken string, filter filtering.Filter) ([]*datamodel.ConnectorExecution, int64, string, error) {
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	dbConnectorResource, err := s.checkConnectorPermission(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorRoleViewer)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	return s.repository.ListConnectorExecutions(ctx, dbConnectorResource.UID, pageSize, pageToken, filter)
+}
+
+func (s *service) GetUserConnectorExecution(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, uid uuid.UUID) (*datamodel.ConnectorExecution, error) {
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	dbConnectorResource, err := s.checkConnectorPermission(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repository.GetConnectorExecution(ctx, dbConnectorResource.UID, uid)
+}
+
+// PurgeConnectorExecutions deletes the executions older than the history retention
+func (s *service) PurgeConnectorExecutions(ctx context.Context) (int64, error) {
+
+	retention := config.Config.Execution.HistoryRetention
+	if retention <= 0 {
+		retention = defaultExecutionHistoryRetention
+	}
+
+	return s.repository.PurgeConnectorExecutions(ctx, time.Now().Add(-retention))
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+
+	"github.com/gofrs/uuid"
+	"go.einride.tech/aip/filtering"
+
+	"github.com/instill-ai/connector-backend/internal/resource"
+	"github.com/instill-ai/connector-backend/pkg/manifest"
+	"github.com/instill-ai/connector-backend/pkg/repository"
+
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+// ExportUserConnectorResources returns the manifests of the connectors owned by the namespace and visible to the
+// user, the credential fields hold placeholders
+func (s *service) ExportUserConnectorResources(ctx context.Context, ns resource.Namespace, userUid uuid.UUID) ([]*manifest.Manifest, error) {
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	manifests := []*manifest.Manifest{}
+	pageToken := ""
+	for {
+		dbConnectorResources, _, nextPageToken, err := s.repository.ListUserConnectorResources(ctx, ownerPermalink, userPermalink, repository.MaxPageSize, pageToken, false, filtering.Filter{}, false)
+		if err != nil {
+			return nil, err
+		}
+		for _, dbConnectorResource := range dbConnectorResources {
+			if dbConnectorResource.Owner != ownerPermalink {
+				continue
+			}
+			pbConnectorResource, err := s.convertDatamodelToProto(ctx, dbConnectorResource, connectorPB.View_VIEW_FULL, true)
+			if err != nil {
+				return nil, err
+			}
+			manifests = append(manifests, manifest.FromConnectorResource(pbConnectorResource))
+		}
+
+		if nextPageToken == "" {
+			return manifests, nil
+		}
+		pageToken = nextPageToken
+	}
+}
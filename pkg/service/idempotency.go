@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/instill-ai/connector-backend/config"
+)
+
+// defaultIdempotencyWindow is how long the response of a request is replayed when it is not configured
+const defaultIdempotencyWindow = 24 * time.Hour
+
+// IdempotentRequest identifies a request made with an idempotency key, the key is unique in the namespace
+type IdempotentRequest struct {
+	Namespace   string
+	Key         string
+	Method      string
+	Caller      string
+	RequestHash string
+}
+
+// idempotencyRecord is the Redis value of an idempotency key, Response is empty while the first request is
+// being processed
+type idempotencyRecord struct {
+	Method      string `json:"method"`
+	Caller      string `json:"caller"`
+	RequestHash string `json:"request_hash"`
+	Response    []byte `json:"response,omitempty"`
+}
+
+func idempotencyKey(req *IdempotentRequest) string {
+	return fmt.Sprintf("idempotency:%s:%s", req.Namespace, req.Key)
+}
+
+func idempotencyWindow() time.Duration {
+	if config.Config.Idempotency.Window > 0 {
+		return config.Config.Idempotency.Window
+	}
+	return defaultIdempotencyWindow
+}
+
+// BeginIdempotentRequest reserves the idempotency key for the request. It returns nil if the request is the first
+// one with the key and must be processed, otherwise the stored response of the first request. A key can only
+// be reused by the same caller for the same request, and not while the first request is being processed.
+func (s *service) BeginIdempotentRequest(ctx context.Context, req *IdempotentRequest) ([]byte, error) {
+
+	record, err := json.Marshal(&idempotencyRecord{
+		Method:      req.Method,
+		Caller:      req.Caller,
+		RequestHash: req.RequestHash,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reserved, err := s.redisClient.SetNX(ctx, idempotencyKey(req), record, idempotencyWindow()).Result()
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "[service] idempotency key store error: %s", err.Error())
+	}
+	if reserved {
+		return nil, nil
+	}
+
+	b, err := s.redisClient.Get(ctx, idempotencyKey(req)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		// The key has just expired, the request is processed as a first one
+		return s.BeginIdempotentRequest(ctx, req)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "[service] idempotency key store error: %s", err.Error())
+	}
+
+	stored := &idempotencyRecord{}
+	if err := json.Unmarshal(b, stored); err != nil {
+		return nil, status.Errorf(codes.Internal, "[service] invalid idempotency record: %s", err.Error())
+	}
+	if stored.Method != req.Method || stored.Caller != req.Caller || stored.RequestHash != req.RequestHash {
+		return nil, status.Errorf(codes.InvalidArgument, "[service] the idempotency key %s is already used by another request", req.Key)
+	}
+	if len(stored.Response) == 0 {
+		return nil, status.Errorf(codes.Aborted, "[service] the request with the idempotency key %s is still in progress", req.Key)
+	}
+	return stored.Response, nil
+}
+
+// CompleteIdempotentRequest stores the response replayed for the idempotency key until the window is over
+func (s *service) CompleteIdempotentRequest(ctx context.Context, req *IdempotentRequest, response []byte) error {
+
+	record, err := json.Marshal(&idempotencyRecord{
+		Method:      req.Method,
+		Caller:      req.Caller,
+		RequestHash: req.RequestHash,
+		Response:    response,
+	})
+	if err != nil {
+		return err
+	}
+	return s.redisClient.Set(ctx, idempotencyKey(req), record, idempotencyWindow()).Err()
+}
+
+// AbandonIdempotentRequest releases the idempotency key of a failed request, it can be retried with the same key
+func (s *service) AbandonIdempotentRequest(ctx context.Context, req *IdempotentRequest) error {
+	return s.redisClient.Del(ctx, idempotencyKey(req)).Err()
+}
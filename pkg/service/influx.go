@@ -8,11 +8,14 @@ import (
 	"google.golang.org/protobuf/types/known/structpb"
 
 	"github.com/instill-ai/connector-backend/config"
+	"github.com/instill-ai/connector-backend/pkg/logger"
 	"github.com/instill-ai/connector-backend/pkg/utils"
 )
 
 func (s *service) WriteNewDataPoint(ctx context.Context, data utils.UsageMetricData, pipelineMetadata *structpb.Value) error {
 
+	logger, _ := logger.GetZapLogger(ctx)
+
 	if config.Config.Server.Usage.Enabled {
 
 		bData, err := json.Marshal(data)
@@ -25,5 +28,10 @@ func (s *service) WriteNewDataPoint(ctx context.Context, data utils.UsageMetricD
 
 	s.influxDBWriteClient.WritePoint(utils.NewDataPoint(data, pipelineMetadata))
 
+	// The usage is reported even if the execution history is unavailable
+	if err := s.recordExecution(ctx, data, pipelineMetadata); err != nil {
+		logger.Warn(fmt.Sprintf("execution %s not recorded in the history: %s", data.ConnectorExecuteUID, err))
+	}
+
 	return nil
 }
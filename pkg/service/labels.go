@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+
+	"github.com/instill-ai/connector-backend/internal/resource"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/x/sterr"
+)
+
+// MaxConnectorLabels is the maximum number of labels of a connector
+const MaxConnectorLabels = 64
+
+// labelsPath is the update mask path replacing all the labels, labels.<key> updates a single label
+const labelsPath = "labels"
+
+var (
+	labelKeyPattern   = regexp.MustCompile(`^[a-z][a-z0-9_]{0,62}$`)
+	labelValuePattern = regexp.MustCompile(`^[a-z0-9_-]{0,63}$`)
+)
+
+func labelsBadRequest(field string, description string) error {
+	st, _ := sterr.CreateErrorBadRequest(
+		"[service] update connector labels error",
+		[]*errdetails.BadRequest_FieldViolation{
+			{
+				Field:       field,
+				Description: description,
+			},
+		},
+	)
+	return st.Err()
+}
+
+// validateLabel checks that a label can be referenced in the filters, e.g. `labels.env = "prod"`: the keys start
+// with a lowercase letter and have up to 63 lowercase letters, digits or `_`, the values can also have `-`
+func validateLabel(key string, value string) error {
+	if !labelKeyPattern.MatchString(key) {
+		return labelsBadRequest(fmt.Sprintf("labels.%s", key), "the key must start with a lowercase letter and have up to 63 lowercase letters, digits or `_`")
+	}
+	if !labelValuePattern.MatchString(value) {
+		return labelsBadRequest(fmt.Sprintf("labels.%s", key), "the value must have up to 63 lowercase letters, digits, `_` or `-`")
+	}
+	return nil
+}
+
+func unmarshalLabels(dbConnectorResource *datamodel.ConnectorResource) map[string]string {
+	labels := map[string]string{}
+	if len(dbConnectorResource.Labels) > 0 {
+		_ = json.Unmarshal(dbConnectorResource.Labels, &labels)
+	}
+	return labels
+}
+
+func (s *service) GetUserConnectorLabels(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string) (map[string]string, error) {
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	dbConnectorResource, err := s.checkConnectorPermission(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalLabels(dbConnectorResource), nil
+}
+
+// UpdateUserConnectorLabels updates the labels of the connector following the update mask: the `labels` path, or
+// an empty mask, replaces all the labels, a `labels.<key>` path sets the label or removes it if it is not given
+func (s *service) UpdateUserConnectorLabels(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, labels map[string]string, paths []string) (map[string]string, error) {
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	dbConnectorResource, err := s.checkConnectorPermission(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	replace := len(paths) == 0
+	set := map[string]string{}
+	remove := []string{}
+	for _, path := range paths {
+		switch {
+		case path == labelsPath:
+			replace = true
+		case strings.HasPrefix(path, labelsPath+"."):
+			key := strings.TrimPrefix(path, labelsPath+".")
+			if value, ok := labels[key]; ok {
+				set[key] = value
+			} else {
+				remove = append(remove, key)
+			}
+		default:
+			return nil, labelsBadRequest("update_mask", fmt.Sprintf("invalid path %s, only labels and labels.<key> can be updated", path))
+		}
+	}
+	if replace {
+		set = labels
+		remove = nil
+	}
+
+	for key, value := range set {
+		if err := validateLabel(key, value); err != nil {
+			return nil, err
+		}
+	}
+
+	// The count is checked against the labels read with the permission, an update racing with it can go over
+	updated := unmarshalLabels(dbConnectorResource)
+	if replace {
+		updated = map[string]string{}
+	}
+	for _, key := range remove {
+		delete(updated, key)
+	}
+	for key, value := range set {
+		updated[key] = value
+	}
+	if len(updated) > MaxConnectorLabels {
+		return nil, labelsBadRequest("labels", fmt.Sprintf("a connector can have at most %d labels", MaxConnectorLabels))
+	}
+
+	if err := s.repository.UpdateUserConnectorResourceLabelsByID(ctx, ownerPermalink, ownerPermalink, id, set, remove, replace); err != nil {
+		return nil, err
+	}
+
+	return s.GetUserConnectorLabels(ctx, ns, userUid, id)
+}
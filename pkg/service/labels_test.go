@@ -0,0 +1,73 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/datatypes"
+
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+)
+
+func TestValidateLabel(t *testing.T) {
+	testCases := []struct {
+		name    string
+		key     string
+		value   string
+		wantErr bool
+	}{
+		{name: "valid", key: "env", value: "prod"},
+		{name: "empty value", key: "env", value: ""},
+		{name: "key with digits and underscore", key: "team_2", value: "ml-ops"},
+		{name: "longest key", key: "k" + strings.Repeat("a", 62), value: "v"},
+		{name: "longest value", key: "env", value: strings.Repeat("a", 63)},
+		{name: "empty key", key: "", value: "prod", wantErr: true},
+		{name: "key starting with a digit", key: "2env", value: "prod", wantErr: true},
+		{name: "key starting with underscore", key: "_env", value: "prod", wantErr: true},
+		{name: "key with dash", key: "cost-center", value: "prod", wantErr: true},
+		{name: "uppercase key", key: "Env", value: "prod", wantErr: true},
+		{name: "key too long", key: "k" + strings.Repeat("a", 63), value: "v", wantErr: true},
+		{name: "uppercase value", key: "env", value: "Prod", wantErr: true},
+		{name: "value with space", key: "env", value: "pr od", wantErr: true},
+		{name: "value too long", key: "env", value: strings.Repeat("a", 64), wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateLabel(tc.key, tc.value)
+			if !tc.wantErr {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("got %v, want an invalid argument error", err)
+			}
+		})
+	}
+}
+
+func TestUnmarshalLabels(t *testing.T) {
+	testCases := []struct {
+		name   string
+		labels datatypes.JSON
+		want   map[string]string
+	}{
+		{name: "unset", labels: nil, want: map[string]string{}},
+		{name: "empty", labels: datatypes.JSON("{}"), want: map[string]string{}},
+		{name: "labels", labels: datatypes.JSON(`{"env":"prod","team":"ml"}`), want: map[string]string{"env": "prod", "team": "ml"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := unmarshalLabels(&datamodel.ConnectorResource{Labels: tc.labels})
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+	"gorm.io/datatypes"
+
+	"github.com/instill-ai/connector-backend/internal/resource"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/connector-backend/pkg/utils"
+
+	mgmtPB "github.com/instill-ai/protogen-go/core/mgmt/v1alpha"
+)
+
+// unfinishedOperationStates are the states of the operations that have not reached a final state
+var unfinishedOperationStates = []datamodel.ConnectorOperationState{
+	datamodel.OperationStatePending,
+	datamodel.OperationStateRunning,
+}
+
+// ExecuteAsync records a pending operation and queues the execution in the execution pool, the operation is
+// returned right away and holds the outputs once the execution is done
+func (s *service) ExecuteAsync(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, task string, inputs []*structpb.Struct, pipelineMetadata *structpb.Value) (*datamodel.ConnectorOperation, error) {
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	dbConnectorResource, err := s.checkConnectorPermission(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorRoleExecutor)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkRateLimits(ctx, dbConnectorResource); err != nil {
+		return nil, err
+	}
+
+	operation := &datamodel.ConnectorOperation{
+		Owner:        userPermalink,
+		ConnectorUID: dbConnectorResource.UID,
+		Task:         task,
+		State:        datamodel.OperationStatePending,
+		Worker:       s.worker,
+	}
+	if err := s.repository.CreateConnectorOperation(ctx, operation); err != nil {
+		return nil, err
+	}
+
+	// The execution outlives the request
+	if !s.executionPool.submit(context.Background(), operation.UID, func(ctx context.Context) {
+		s.runOperation(ctx, operation, userUid, dbConnectorResource, inputs, pipelineMetadata)
+	}) {
+		st := status.New(codes.ResourceExhausted, "too many executions in progress, try again later")
+		if _, err := s.finishOperation(ctx, operation.UID, unfinishedOperationStates, datamodel.OperationStateFailed, nil, st); err != nil {
+			return nil, err
+		}
+		return nil, st.Err()
+	}
+
+	return operation, nil
+}
+
+// runOperation executes the connector of a pending operation and records its result, the usage data point
+// is written once the execution is done
+func (s *service) runOperation(ctx context.Context, operation *datamodel.ConnectorOperation, userUid uuid.UUID, dbConnectorResource *datamodel.ConnectorResource, inputs []*structpb.Struct, pipelineMetadata *structpb.Value) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	startTime := time.Now()
+	started, err := s.repository.TransitConnectorOperation(ctx, operation.UID, []datamodel.ConnectorOperationState{datamodel.OperationStatePending}, map[string]interface{}{
+		"state":      datamodel.OperationStateRunning,
+		"start_time": startTime,
+	})
+	if err != nil {
+		logger.Error(err.Error())
+		return
+	}
+	// The operation has been cancelled while it was queued
+	if !started {
+		return
+	}
+
+	result, err := s.executeCachedConnector(ctx, dbConnectorResource, operation.Task, inputs)
+
+	dataPoint := utils.UsageMetricData{
+		OwnerUID:               userUid.String(),
+		ConnectorID:            dbConnectorResource.ID,
+		ConnectorUID:           dbConnectorResource.UID.String(),
+		ConnectorExecuteUID:    operation.UID.String(),
+		ConnectorDefinitionUid: dbConnectorResource.ConnectorDefinitionUID.String(),
+		ExecuteTime:            startTime.Format(time.RFC3339Nano),
+		ComputeTimeDuration:    time.Since(startTime).Seconds(),
+		Status:                 mgmtPB.Status_STATUS_COMPLETED,
+		SucceededCount:         len(inputs),
+		Attempts:               result.Attempts,
+		CacheHit:               result.CacheHit,
+		Task:                   operation.Task,
+		InputSize:              utils.PayloadSize(inputs),
+	}
+
+	// A cancelled operation keeps its state, the result of the execution is discarded
+	running := []datamodel.ConnectorOperationState{datamodel.OperationStateRunning}
+	if err != nil {
+		s.captureExecution(ctx, dbConnectorResource, operation.UID, inputs, nil, err.Error())
+		dataPoint.Status = mgmtPB.Status_STATUS_ERRORED
+		dataPoint.SucceededCount, dataPoint.FailedCount = 0, len(inputs)
+		dataPoint.ErrorMessage = err.Error()
+		errorMessage := err.Error()
+		var failed bool
+		failed, err = s.finishOperation(ctx, operation.UID, running, datamodel.OperationStateFailed, nil, status.Convert(err))
+		// The inputs of a cancelled operation are not kept to be replayed
+		if failed {
+			s.storeDeadLetter(ctx, dbConnectorResource, operation.UID, userUid, operation.Task, inputs, errorMessage)
+		}
+	} else {
+		s.captureExecution(ctx, dbConnectorResource, operation.UID, inputs, result.Outputs, "")
+		dataPoint.OutputSize = utils.PayloadSize(result.Outputs)
+		_, err = s.finishOperation(ctx, operation.UID, running, datamodel.OperationStateSucceeded, result.Outputs, nil)
+	}
+	if err != nil {
+		logger.Error(err.Error())
+	}
+
+	if err := s.WriteNewDataPoint(ctx, dataPoint, pipelineMetadata); err != nil {
+		logger.Warn("usage and metric data write fail")
+	}
+}
+
+// finishOperation moves the operation to a final state unless it has left the {from} states meanwhile
+func (s *service) finishOperation(ctx context.Context, uid uuid.UUID, from []datamodel.ConnectorOperationState, state datamodel.ConnectorOperationState, outputs []*structpb.Struct, st *status.Status) (bool, error) {
+
+	updates := map[string]interface{}{
+		"state":    state,
+		"end_time": time.Now(),
+	}
+	if outputs != nil {
+		list := &structpb.ListValue{}
+		for _, output := range outputs {
+			list.Values = append(list.Values, structpb.NewStructValue(output))
+		}
+		b, err := protojson.Marshal(list)
+		if err != nil {
+			return false, err
+		}
+		updates["outputs"] = datatypes.JSON(b)
+	}
+	if st != nil {
+		b, err := protojson.Marshal(st.Proto())
+		if err != nil {
+			return false, err
+		}
+		updates["error"] = datatypes.JSON(b)
+	}
+
+	return s.repository.TransitConnectorOperation(ctx, uid, from, updates)
+}
+
+func (s *service) ListUserConnectorOperations(ctx context.Context, userUid uuid.UUID, pageSize int64, pageToken string) ([]*datamodel.ConnectorOperation, int64, string, error) {
+	return s.repository.ListConnectorOperations(ctx, resource.UserUidToUserPermalink(userUid), pageSize, pageToken)
+}
+
+func (s *service) GetUserConnectorOperation(ctx context.Context, userUid uuid.UUID, uid uuid.UUID) (*datamodel.ConnectorOperation, error) {
+	return s.repository.GetConnectorOperation(ctx, resource.UserUidToUserPermalink(userUid), uid)
+}
+
+// CancelUserConnectorOperation cancels an unfinished operation, cancelling a finished one has no effect. A running
+// execution can not be interrupted, its result is discarded once it returns.
+func (s *service) CancelUserConnectorOperation(ctx context.Context, userUid uuid.UUID, uid uuid.UUID) (*datamodel.ConnectorOperation, error) {
+
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	operation, err := s.repository.GetConnectorOperation(ctx, userPermalink, uid)
+	if err != nil {
+		return nil, err
+	}
+	if operation.State.IsDone() {
+		return operation, nil
+	}
+
+	if _, err := s.finishOperation(ctx, uid, unfinishedOperationStates, datamodel.OperationStateCancelled, nil, status.New(codes.Canceled, "the operation has been cancelled")); err != nil {
+		return nil, err
+	}
+	s.executionPool.cancel(uid)
+
+	return s.repository.GetConnectorOperation(ctx, userPermalink, uid)
+}
+
+// FailInterruptedConnectorOperations fails the unfinished operations of this host, their executions have been
+// lost with the execution pool of the previous process
+func (s *service) FailInterruptedConnectorOperations(ctx context.Context) (int64, error) {
+
+	b, err := protojson.Marshal(status.New(codes.Aborted, "the execution has been interrupted by a restart").Proto())
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := s.repository.TransitWorkerConnectorOperations(ctx, s.worker, unfinishedOperationStates, map[string]interface{}{
+		"state":    datamodel.OperationStateFailed,
+		"error":    datatypes.JSON(b),
+		"end_time": time.Now(),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("fail interrupted operations: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/influxdata/influxdb-client-go/v2/api"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/repository"
+
+	componentBase "github.com/instill-ai/component/pkg/base"
+)
+
+// operationRepository moves the operations as told by transits in turn and keeps the dead letters, the
+// executions are dropped
+type operationRepository struct {
+	repository.Repository
+	transits    []bool
+	deadLetters []*datamodel.ConnectorDeadLetter
+}
+
+func (r *operationRepository) TransitConnectorOperation(ctx context.Context, uid uuid.UUID, from []datamodel.ConnectorOperationState, updates map[string]interface{}) (bool, error) {
+	transit := r.transits[0]
+	r.transits = r.transits[1:]
+	return transit, nil
+}
+
+func (r *operationRepository) CreateConnectorExecution(ctx context.Context, execution *datamodel.ConnectorExecution) error {
+	return nil
+}
+
+func (r *operationRepository) CreateConnectorDeadLetter(ctx context.Context, deadLetter *datamodel.ConnectorDeadLetter) error {
+	r.deadLetters = append(r.deadLetters, deadLetter)
+	return nil
+}
+
+// failingConnectors fails to create the executions
+type failingConnectors struct {
+	componentBase.IConnector
+}
+
+func (c *failingConnectors) CreateExecution(defUID uuid.UUID, task string, config *structpb.Struct, logger *zap.Logger) (componentBase.IExecution, error) {
+	return nil, errors.New("non-200 status code: 400")
+}
+
+type fakeWriteAPI struct {
+	api.WriteAPI
+}
+
+func (w *fakeWriteAPI) WritePoint(point *write.Point) {}
+
+func TestRunOperationStoresDeadLetter(t *testing.T) {
+	testCases := []struct {
+		name            string
+		transits        []bool
+		wantDeadLetters int
+	}{
+		{name: "failed", transits: []bool{true, true}, wantDeadLetters: 1},
+		{name: "cancelled while running", transits: []bool{true, false}, wantDeadLetters: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &operationRepository{transits: tc.transits}
+			s := &service{repository: repo, connectors: &failingConnectors{}, influxDBWriteClient: &fakeWriteAPI{}}
+
+			operation := &datamodel.ConnectorOperation{Task: "TASK_TEXT_GENERATION"}
+			operation.UID = uuid.Must(uuid.NewV4())
+			dbConnectorResource := &datamodel.ConnectorResource{}
+			dbConnectorResource.UID = uuid.Must(uuid.NewV4())
+			userUid := uuid.Must(uuid.NewV4())
+			inputs := []*structpb.Struct{{Fields: map[string]*structpb.Value{"prompt": structpb.NewStringValue("hello")}}}
+
+			s.runOperation(context.Background(), operation, userUid, dbConnectorResource, inputs, nil)
+
+			if len(repo.deadLetters) != tc.wantDeadLetters {
+				t.Fatalf("got %d dead letters, want %d", len(repo.deadLetters), tc.wantDeadLetters)
+			}
+			if tc.wantDeadLetters == 0 {
+				return
+			}
+			deadLetter := repo.deadLetters[0]
+			if deadLetter.UID != operation.UID || deadLetter.ConnectorUID != dbConnectorResource.UID || deadLetter.UserUID != userUid {
+				t.Errorf("got dead letter %+v", deadLetter)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gofrs/uuid"
+)
+
+// Defaults of the execution pool when they are not configured
+const (
+	defaultExecutionWorkers   = 8
+	defaultExecutionQueueSize = 100
+)
+
+// executionJob is an asynchronous execution waiting for a worker
+type executionJob struct {
+	uid uuid.UUID
+	ctx context.Context
+	run func(ctx context.Context)
+}
+
+// executionPool runs the asynchronous executions on a bounded number of workers. The jobs that do not fit in
+// the queue are rejected rather than piling up in memory.
+type executionPool struct {
+	jobs    chan *executionJob
+	mu      sync.Mutex
+	cancels map[uuid.UUID]context.CancelFunc
+}
+
+// newExecutionPool starts the workers, they stop once ctx is done
+func newExecutionPool(ctx context.Context, workers int, queueSize int) *executionPool {
+	if workers <= 0 {
+		workers = defaultExecutionWorkers
+	}
+	if queueSize <= 0 {
+		queueSize = defaultExecutionQueueSize
+	}
+
+	p := &executionPool{
+		jobs:    make(chan *executionJob, queueSize),
+		cancels: map[uuid.UUID]context.CancelFunc{},
+	}
+	for i := 0; i < workers; i++ {
+		go p.work(ctx)
+	}
+	return p
+}
+
+func (p *executionPool) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-p.jobs:
+			if job.ctx.Err() == nil {
+				job.run(job.ctx)
+			}
+			p.release(job.uid)
+		}
+	}
+}
+
+// submit queues the job, it returns false if the queue is full
+func (p *executionPool) submit(ctx context.Context, uid uuid.UUID, run func(ctx context.Context)) bool {
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	p.mu.Lock()
+	p.cancels[uid] = cancel
+	p.mu.Unlock()
+
+	select {
+	case p.jobs <- &executionJob{uid: uid, ctx: ctx, run: run}:
+		return true
+	default:
+		p.release(uid)
+		return false
+	}
+}
+
+// cancel cancels the context of a queued or running job
+func (p *executionPool) cancel(uid uuid.UUID) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if cancel, ok := p.cancels[uid]; ok {
+		cancel()
+	}
+}
+
+func (p *executionPool) release(uid uuid.UUID) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if cancel, ok := p.cancels[uid]; ok {
+		cancel()
+		delete(p.cancels, uid)
+	}
+}
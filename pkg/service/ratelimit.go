@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/instill-ai/connector-backend/internal/resource"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/x/sterr"
+)
+
+// MaxRateLimitBurst is the maximum burst a rate limit can set
+const MaxRateLimitBurst = 100000
+
+// takeTokensScript takes a token from each bucket if none of them is empty. KEYS are the buckets and ARGV holds
+// the current time in milliseconds followed by the rate and the burst of each bucket. It returns {1} if the
+// tokens are taken, otherwise {0, wait, i} where wait is the number of milliseconds until the i-th bucket, the
+// slowest to refill, holds a token again.
+var takeTokensScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local tokens = {}
+local wait, limiting = 0, 0
+for i, key in ipairs(KEYS) do
+  local rate, burst = tonumber(ARGV[2 * i]), tonumber(ARGV[2 * i + 1])
+  local bucket = redis.call("HMGET", key, "tokens", "time")
+  local available = tonumber(bucket[1]) or burst
+  local last = tonumber(bucket[2]) or now
+  available = math.min(burst, available + math.max(0, now - last) * rate / 1000)
+  tokens[i] = available
+  if available < 1 then
+    local refill = math.ceil((1 - available) * 1000 / rate)
+    if refill > wait then
+      wait, limiting = refill, i
+    end
+  end
+end
+if limiting > 0 then
+  return {0, wait, limiting}
+end
+for i, key in ipairs(KEYS) do
+  local rate, burst = tonumber(ARGV[2 * i]), tonumber(ARGV[2 * i + 1])
+  redis.call("HSET", key, "tokens", tostring(tokens[i] - 1), "time", now)
+  redis.call("PEXPIRE", key, math.ceil(burst * 1000 / rate) + 1000)
+end
+return {1}
+`)
+
+// rateLimitKey is the Redis key of the token bucket of a rate limit
+func rateLimitKey(limit *datamodel.RateLimit) string {
+	return fmt.Sprintf("rate_limit:%s:%s", limit.Scope, limit.Subject)
+}
+
+// checkRateLimits takes a token from the buckets of the connector, its namespace and its definition. An
+// execution over one of the limits fails with ResourceExhausted and the delay after which it can be retried.
+// The limits are not enforced while Redis is unreachable.
+func (s *service) checkRateLimits(ctx context.Context, dbConnectorResource *datamodel.ConnectorResource) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	limits, err := s.repository.ListRateLimits(ctx, map[datamodel.RateLimitScope]string{
+		datamodel.RateLimitScopeConnector:           dbConnectorResource.UID.String(),
+		datamodel.RateLimitScopeNamespace:           dbConnectorResource.Owner,
+		datamodel.RateLimitScopeConnectorDefinition: dbConnectorResource.ConnectorDefinitionUID.String(),
+	})
+	if err != nil {
+		return err
+	}
+	if len(limits) == 0 {
+		return nil
+	}
+
+	keys := make([]string, len(limits))
+	args := []interface{}{time.Now().UnixMilli()}
+	for i, limit := range limits {
+		keys[i] = rateLimitKey(limit)
+		args = append(args, limit.Rate, limit.Burst)
+	}
+
+	result, err := takeTokensScript.Run(ctx, s.redisClient, keys, args...).Int64Slice()
+	if err != nil {
+		logger.Warn(fmt.Sprintf("rate limits of connector %s not enforced: %s", dbConnectorResource.UID, err))
+		return nil
+	}
+	if result[0] == 1 {
+		return nil
+	}
+
+	limit := limits[result[2]-1]
+	st, err := status.New(
+		codes.ResourceExhausted,
+		fmt.Sprintf("[service] the %s rate limit of %g executions per second is exceeded", limit.Scope, limit.Rate),
+	).WithDetails(
+		&errdetails.RetryInfo{
+			RetryDelay: durationpb.New(time.Duration(result[1]) * time.Millisecond),
+		},
+		&errdetails.QuotaFailure{
+			Violations: []*errdetails.QuotaFailure_Violation{
+				{
+					Subject:     fmt.Sprintf("%s:%s", limit.Scope, limit.Subject),
+					Description: fmt.Sprintf("%g executions per second with a burst of %d", limit.Rate, limit.Burst),
+				},
+			},
+		},
+	)
+	if err != nil {
+		return status.Errorf(codes.ResourceExhausted, "[service] the %s rate limit is exceeded", limit.Scope)
+	}
+	return st.Err()
+}
+
+// validateRateLimit checks the token bucket parameters of a rate limit
+func validateRateLimit(rate float64, burst int32) error {
+
+	violations := []*errdetails.BadRequest_FieldViolation{}
+	if rate <= 0 {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       "rate",
+			Description: "the rate must be a positive number of executions per second",
+		})
+	}
+	if burst < 1 || burst > MaxRateLimitBurst {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       "burst",
+			Description: fmt.Sprintf("the burst must be between 1 and %d", MaxRateLimitBurst),
+		})
+	}
+
+	if len(violations) > 0 {
+		st, _ := sterr.CreateErrorBadRequest("[service] invalid rate limit", violations)
+		return st.Err()
+	}
+	return nil
+}
+
+func (s *service) setRateLimit(ctx context.Context, scope datamodel.RateLimitScope, subject string, rate float64, burst int32) (*datamodel.RateLimit, error) {
+
+	if err := validateRateLimit(rate, burst); err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.UpsertRateLimit(ctx, &datamodel.RateLimit{
+		Scope:   scope,
+		Subject: subject,
+		Rate:    rate,
+		Burst:   burst,
+	}); err != nil {
+		return nil, err
+	}
+
+	return s.repository.GetRateLimit(ctx, scope, subject)
+}
+
+// checkNamespaceOwner only lets the owner of a namespace manage its rate limit
+func checkNamespaceOwner(ctx context.Context, ownerPermalink string, userPermalink string) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	if ownerPermalink != userPermalink {
+		st, err := sterr.CreateErrorResourceInfo(
+			codes.PermissionDenied,
+			"[service] permission denied",
+			"namespace",
+			ownerPermalink,
+			userPermalink,
+			"only the owner of the namespace can manage its rate limit",
+		)
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	}
+	return nil
+}
+
+func (s *service) GetUserConnectorRateLimit(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string) (*datamodel.RateLimit, error) {
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	dbConnectorResource, err := s.checkConnectorPermission(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repository.GetRateLimit(ctx, datamodel.RateLimitScopeConnector, dbConnectorResource.UID.String())
+}
+
+func (s *service) UpdateUserConnectorRateLimit(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, rate float64, burst int32) (*datamodel.RateLimit, error) {
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	dbConnectorResource, err := s.checkConnectorPermission(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.setRateLimit(ctx, datamodel.RateLimitScopeConnector, dbConnectorResource.UID.String(), rate, burst)
+}
+
+func (s *service) DeleteUserConnectorRateLimit(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string) error {
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	dbConnectorResource, err := s.checkConnectorPermission(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorRoleEditor)
+	if err != nil {
+		return err
+	}
+
+	return s.repository.DeleteRateLimit(ctx, datamodel.RateLimitScopeConnector, dbConnectorResource.UID.String())
+}
+
+func (s *service) GetNamespaceRateLimit(ctx context.Context, ns resource.Namespace, userUid uuid.UUID) (*datamodel.RateLimit, error) {
+
+	ownerPermalink := ns.String()
+	if err := checkNamespaceOwner(ctx, ownerPermalink, resource.UserUidToUserPermalink(userUid)); err != nil {
+		return nil, err
+	}
+
+	return s.repository.GetRateLimit(ctx, datamodel.RateLimitScopeNamespace, ownerPermalink)
+}
+
+func (s *service) UpdateNamespaceRateLimit(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, rate float64, burst int32) (*datamodel.RateLimit, error) {
+
+	ownerPermalink := ns.String()
+	if err := checkNamespaceOwner(ctx, ownerPermalink, resource.UserUidToUserPermalink(userUid)); err != nil {
+		return nil, err
+	}
+
+	return s.setRateLimit(ctx, datamodel.RateLimitScopeNamespace, ownerPermalink, rate, burst)
+}
+
+func (s *service) DeleteNamespaceRateLimit(ctx context.Context, ns resource.Namespace, userUid uuid.UUID) error {
+
+	ownerPermalink := ns.String()
+	if err := checkNamespaceOwner(ctx, ownerPermalink, resource.UserUidToUserPermalink(userUid)); err != nil {
+		return err
+	}
+
+	return s.repository.DeleteRateLimit(ctx, datamodel.RateLimitScopeNamespace, ownerPermalink)
+}
+
+func (s *service) GetConnectorDefinitionRateLimitAdmin(ctx context.Context, defUID uuid.UUID) (*datamodel.RateLimit, error) {
+	return s.repository.GetRateLimit(ctx, datamodel.RateLimitScopeConnectorDefinition, defUID.String())
+}
+
+// UpdateConnectorDefinitionRateLimitAdmin limits the executions of every connector of the definition
+func (s *service) UpdateConnectorDefinitionRateLimitAdmin(ctx context.Context, defUID uuid.UUID, rate float64, burst int32) (*datamodel.RateLimit, error) {
+
+	if _, err := s.connectors.GetConnectorDefinitionByUID(defUID); err != nil {
+		return nil, status.Errorf(codes.NotFound, "[service] connector definition %s not found", defUID)
+	}
+
+	return s.setRateLimit(ctx, datamodel.RateLimitScopeConnectorDefinition, defUID.String(), rate, burst)
+}
+
+func (s *service) DeleteConnectorDefinitionRateLimitAdmin(ctx context.Context, defUID uuid.UUID) error {
+	return s.repository.DeleteRateLimit(ctx, datamodel.RateLimitScopeConnectorDefinition, defUID.String())
+}
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/datatypes"
+
+	"github.com/instill-ai/connector-backend/config"
+	"github.com/instill-ai/connector-backend/internal/resource"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/x/sterr"
+)
+
+// MaxRetryAttempts is the maximum number of attempts a retry policy can set
+const MaxRetryAttempts = 10
+
+// httpStatusPattern matches the HTTP status code reported by the connectors, e.g. "non-200 status code: 503"
+var httpStatusPattern = regexp.MustCompile(`status code: (\d{3})`)
+
+// retryPolicy is the resolved retry policy of a connector resource
+type retryPolicy struct {
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	multiplier     float64
+	jitter         float64
+	retryable      map[datamodel.RetryErrorClass]bool
+}
+
+// resolveRetryPolicy returns the retry policy of the connector resource, the global policy fills the fields
+// the resource does not set
+func resolveRetryPolicy(ctx context.Context, dbConnectorResource *datamodel.ConnectorResource) retryPolicy {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	global := config.Config.Connector.Retry
+	policy := retryPolicy{
+		maxAttempts:    global.MaxAttempts,
+		initialBackoff: global.InitialBackoff,
+		maxBackoff:     global.MaxBackoff,
+		multiplier:     global.Multiplier,
+		jitter:         global.Jitter,
+		retryable:      map[datamodel.RetryErrorClass]bool{},
+	}
+	for _, class := range global.RetryableErrors {
+		policy.retryable[datamodel.RetryErrorClass(class)] = true
+	}
+
+	if dbConnectorResource.RetryPolicy != nil {
+		override := &datamodel.RetryPolicy{}
+		// The policy is validated when it is set, a broken one falls back to the global policy
+		if err := json.Unmarshal(dbConnectorResource.RetryPolicy, override); err != nil {
+			logger.Error(fmt.Sprintf("invalid retry policy of connector %s: %s", dbConnectorResource.UID, err))
+		} else {
+			if override.MaxAttempts > 0 {
+				policy.maxAttempts = override.MaxAttempts
+			}
+			if d, err := time.ParseDuration(override.InitialBackoff); err == nil {
+				policy.initialBackoff = d
+			}
+			if d, err := time.ParseDuration(override.MaxBackoff); err == nil {
+				policy.maxBackoff = d
+			}
+			if override.Multiplier > 0 {
+				policy.multiplier = override.Multiplier
+			}
+			if override.Jitter != nil {
+				policy.jitter = *override.Jitter
+			}
+			if override.RetryableErrors != nil {
+				policy.retryable = map[datamodel.RetryErrorClass]bool{}
+				for _, class := range override.RetryableErrors {
+					policy.retryable[class] = true
+				}
+			}
+		}
+	}
+
+	if policy.maxAttempts < 1 {
+		policy.maxAttempts = 1
+	}
+	if policy.multiplier < 1 {
+		policy.multiplier = 1
+	}
+	return policy
+}
+
+// backoff returns the delay before the attempt following the {attempt}th one
+func (p retryPolicy) backoff(attempt int) time.Duration {
+	delay := float64(p.initialBackoff) * math.Pow(p.multiplier, float64(attempt-1))
+	if p.maxBackoff > 0 && delay > float64(p.maxBackoff) {
+		delay = float64(p.maxBackoff)
+	}
+	if p.jitter > 0 {
+		delay += delay * p.jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(delay)
+}
+
+// classifyRetryError returns the retry class of an execution error, false if the error is not transient
+func classifyRetryError(err error) (datamodel.RetryErrorClass, bool) {
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return datamodel.RetryErrorTimeout, true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return datamodel.RetryErrorTimeout, true
+		}
+		return datamodel.RetryErrorNetwork, true
+	}
+
+	if st, ok := status.FromError(err); ok {
+		switch st.Code() {
+		case codes.DeadlineExceeded:
+			return datamodel.RetryErrorTimeout, true
+		case codes.ResourceExhausted:
+			return datamodel.RetryErrorRateLimited, true
+		case codes.Unavailable:
+			return datamodel.RetryErrorUnavailable, true
+		}
+	}
+
+	if match := httpStatusPattern.FindStringSubmatch(err.Error()); match != nil {
+		code, _ := strconv.Atoi(match[1])
+		switch code {
+		case 408, 504:
+			return datamodel.RetryErrorTimeout, true
+		case 429:
+			return datamodel.RetryErrorRateLimited, true
+		case 502, 503:
+			return datamodel.RetryErrorUnavailable, true
+		}
+	}
+	return "", false
+}
+
+// retry calls fn until it succeeds, fails with an error the policy does not retry or the attempts are exhausted.
+// It returns the number of attempts, which is also recorded in the span of the context.
+func (p retryPolicy) retry(ctx context.Context, fn func() error) (int, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	attempt := 0
+	var err error
+	for {
+		attempt++
+		if err = fn(); err == nil {
+			break
+		}
+		class, ok := classifyRetryError(err)
+		if !ok || !p.retryable[class] || attempt >= p.maxAttempts {
+			break
+		}
+
+		delay := p.backoff(attempt)
+		logger.Info(fmt.Sprintf("attempt %d failed with a %s error, retrying in %s: %s", attempt, class, delay, err))
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			trace.SpanFromContext(ctx).SetAttributes(attribute.Int("connector.attempts", attempt))
+			return attempt, err
+		case <-timer.C:
+		}
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("connector.attempts", attempt))
+	return attempt, err
+}
+
+// validateRetryPolicy checks the fields of a retry policy set on a connector resource
+func validateRetryPolicy(policy *datamodel.RetryPolicy) error {
+
+	violations := []*errdetails.BadRequest_FieldViolation{}
+	if policy.MaxAttempts < 0 || policy.MaxAttempts > MaxRetryAttempts {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       "max_attempts",
+			Description: fmt.Sprintf("the max attempts must be between 1 and %d", MaxRetryAttempts),
+		})
+	}
+	for field, value := range map[string]string{"initial_backoff": policy.InitialBackoff, "max_backoff": policy.MaxBackoff} {
+		if value == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(value); err != nil || d < 0 {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{
+				Field:       field,
+				Description: "the backoff must be a non-negative duration, e.g. \"200ms\"",
+			})
+		}
+	}
+	if policy.Multiplier != 0 && policy.Multiplier < 1 {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       "multiplier",
+			Description: "the multiplier must be at least 1",
+		})
+	}
+	if policy.Jitter != nil && (*policy.Jitter < 0 || *policy.Jitter > 1) {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       "jitter",
+			Description: "the jitter must be between 0 and 1",
+		})
+	}
+	for idx, class := range policy.RetryableErrors {
+		if !class.IsValid() {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{
+				Field:       fmt.Sprintf("retryable_errors[%d]", idx),
+				Description: fmt.Sprintf("unknown error class %q", class),
+			})
+		}
+	}
+
+	if len(violations) > 0 {
+		st, _ := sterr.CreateErrorBadRequest("[service] invalid retry policy", violations)
+		return st.Err()
+	}
+	return nil
+}
+
+// GetUserConnectorRetryPolicy returns the retry policy set on the connector resource, it is empty if the
+// connector follows the global policy
+func (s *service) GetUserConnectorRetryPolicy(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string) (*datamodel.RetryPolicy, error) {
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	dbConnectorResource, err := s.checkConnectorPermission(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &datamodel.RetryPolicy{}
+	if dbConnectorResource.RetryPolicy != nil {
+		if err := json.Unmarshal(dbConnectorResource.RetryPolicy, policy); err != nil {
+			return nil, status.Errorf(codes.Internal, "[service] invalid retry policy: %s", err.Error())
+		}
+	}
+	return policy, nil
+}
+
+// UpdateUserConnectorRetryPolicy sets the retry policy of the connector resource, a nil policy restores the
+// global policy
+func (s *service) UpdateUserConnectorRetryPolicy(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, policy *datamodel.RetryPolicy) (*datamodel.RetryPolicy, error) {
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	if _, err := s.checkConnectorPermission(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorRoleEditor); err != nil {
+		return nil, err
+	}
+
+	var value datatypes.JSON
+	if policy != nil {
+		if err := validateRetryPolicy(policy); err != nil {
+			return nil, err
+		}
+		b, err := json.Marshal(policy)
+		if err != nil {
+			return nil, err
+		}
+		value = b
+	}
+
+	if err := s.repository.UpdateUserConnectorResourceRetryPolicyByID(ctx, ownerPermalink, ownerPermalink, id, value); err != nil {
+		return nil, err
+	}
+
+	return s.GetUserConnectorRetryPolicy(ctx, ns, userUid, id)
+}
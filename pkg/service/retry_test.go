@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+)
+
+type netError struct {
+	timeout bool
+}
+
+func (e netError) Error() string   { return "net error" }
+func (e netError) Timeout() bool   { return e.timeout }
+func (e netError) Temporary() bool { return false }
+
+func TestClassifyRetryError(t *testing.T) {
+	testCases := []struct {
+		name      string
+		err       error
+		wantClass datamodel.RetryErrorClass
+		wantOK    bool
+	}{
+		{
+			name:      "deadline exceeded",
+			err:       context.DeadlineExceeded,
+			wantClass: datamodel.RetryErrorTimeout,
+			wantOK:    true,
+		},
+		{
+			name:      "wrapped deadline exceeded",
+			err:       fmt.Errorf("execute: %w", context.DeadlineExceeded),
+			wantClass: datamodel.RetryErrorTimeout,
+			wantOK:    true,
+		},
+		{
+			name:      "network timeout",
+			err:       netError{timeout: true},
+			wantClass: datamodel.RetryErrorTimeout,
+			wantOK:    true,
+		},
+		{
+			name:      "network error",
+			err:       fmt.Errorf("dial: %w", netError{}),
+			wantClass: datamodel.RetryErrorNetwork,
+			wantOK:    true,
+		},
+		{
+			name:      "grpc deadline exceeded",
+			err:       status.Error(codes.DeadlineExceeded, "deadline"),
+			wantClass: datamodel.RetryErrorTimeout,
+			wantOK:    true,
+		},
+		{
+			name:      "grpc resource exhausted",
+			err:       status.Error(codes.ResourceExhausted, "quota"),
+			wantClass: datamodel.RetryErrorRateLimited,
+			wantOK:    true,
+		},
+		{
+			name:      "grpc unavailable",
+			err:       status.Error(codes.Unavailable, "unavailable"),
+			wantClass: datamodel.RetryErrorUnavailable,
+			wantOK:    true,
+		},
+		{
+			name:   "grpc invalid argument",
+			err:    status.Error(codes.InvalidArgument, "invalid"),
+			wantOK: false,
+		},
+		{
+			name:      "http 408",
+			err:       errors.New("non-200 status code: 408"),
+			wantClass: datamodel.RetryErrorTimeout,
+			wantOK:    true,
+		},
+		{
+			name:      "http 504",
+			err:       errors.New("non-200 status code: 504"),
+			wantClass: datamodel.RetryErrorTimeout,
+			wantOK:    true,
+		},
+		{
+			name:      "http 429",
+			err:       errors.New("non-200 status code: 429"),
+			wantClass: datamodel.RetryErrorRateLimited,
+			wantOK:    true,
+		},
+		{
+			name:      "http 502",
+			err:       errors.New("non-200 status code: 502"),
+			wantClass: datamodel.RetryErrorUnavailable,
+			wantOK:    true,
+		},
+		{
+			name:      "http 503",
+			err:       errors.New("non-200 status code: 503"),
+			wantClass: datamodel.RetryErrorUnavailable,
+			wantOK:    true,
+		},
+		{
+			name:   "http 400",
+			err:    errors.New("non-200 status code: 400"),
+			wantOK: false,
+		},
+		{
+			name:   "other error",
+			err:    errors.New("invalid input"),
+			wantOK: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			class, ok := classifyRetryError(tc.err)
+			if ok != tc.wantOK {
+				t.Fatalf("got retryable %v, want %v", ok, tc.wantOK)
+			}
+			if class != tc.wantClass {
+				t.Errorf("got class %q, want %q", class, tc.wantClass)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	testCases := []struct {
+		name    string
+		policy  retryPolicy
+		attempt int
+		want    time.Duration
+	}{
+		{
+			name:    "first attempt",
+			policy:  retryPolicy{initialBackoff: 100 * time.Millisecond, multiplier: 2},
+			attempt: 1,
+			want:    100 * time.Millisecond,
+		},
+		{
+			name:    "exponential",
+			policy:  retryPolicy{initialBackoff: 100 * time.Millisecond, multiplier: 2},
+			attempt: 4,
+			want:    800 * time.Millisecond,
+		},
+		{
+			name:    "constant",
+			policy:  retryPolicy{initialBackoff: 100 * time.Millisecond, multiplier: 1},
+			attempt: 5,
+			want:    100 * time.Millisecond,
+		},
+		{
+			name:    "capped",
+			policy:  retryPolicy{initialBackoff: 100 * time.Millisecond, maxBackoff: 300 * time.Millisecond, multiplier: 2},
+			attempt: 4,
+			want:    300 * time.Millisecond,
+		},
+		{
+			name:    "uncapped",
+			policy:  retryPolicy{initialBackoff: 100 * time.Millisecond, maxBackoff: 0, multiplier: 3},
+			attempt: 3,
+			want:    900 * time.Millisecond,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.policy.backoff(tc.attempt); got != tc.want {
+				t.Errorf("got %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBackoffJitter(t *testing.T) {
+	policy := retryPolicy{initialBackoff: time.Second, maxBackoff: 10 * time.Second, multiplier: 2, jitter: 0.5}
+	for attempt := 1; attempt <= 5; attempt++ {
+		base := policy.initialBackoff << (attempt - 1)
+		if base > policy.maxBackoff {
+			base = policy.maxBackoff
+		}
+		for i := 0; i < 100; i++ {
+			got := policy.backoff(attempt)
+			if got < base/2 || got > base+base/2 {
+				t.Fatalf("attempt %d: got %s, want between %s and %s", attempt, got, base/2, base+base/2)
+			}
+		}
+	}
+}
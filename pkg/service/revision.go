@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/instill-ai/connector-backend/internal/resource"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/connector-backend/pkg/utils"
+	"github.com/instill-ai/x/sterr"
+
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+// configurationFieldPrefix prefixes the configuration fields in a revision diff
+const configurationFieldPrefix = "configuration."
+
+// unmarshalConfiguration returns the stored configuration, an empty struct if there is none
+func unmarshalConfiguration(b []byte) (*structpb.Struct, error) {
+	configuration := &structpb.Struct{}
+	if len(b) == 0 {
+		return configuration, nil
+	}
+	if err := configuration.UnmarshalJSON(b); err != nil {
+		return nil, err
+	}
+	return configuration, nil
+}
+
+// diffConnectorResources lists the changes between two stored states of a connector. The configurations are
+// compared in plaintext so that a changed credential is detected, but the changes only hold masked values.
+func (s *service) diffConnectorResources(connDefID string, previous *datamodel.ConnectorResource, current *datamodel.ConnectorResource) ([]datamodel.FieldChange, error) {
+
+	masked := map[*datamodel.ConnectorResource]*structpb.Struct{}
+	plain := map[*datamodel.ConnectorResource]*structpb.Struct{}
+	for _, conn := range []*datamodel.ConnectorResource{previous, current} {
+		configuration, err := unmarshalConfiguration(conn.Configuration)
+		if err != nil {
+			return nil, err
+		}
+		masked[conn] = proto.Clone(configuration).(*structpb.Struct)
+		utils.MaskCredentialFields(s.connectors, connDefID, masked[conn])
+		if err := utils.DecryptCredentialFields(s.connectors, connDefID, configuration, s.envelope); err != nil {
+			return nil, err
+		}
+		plain[conn] = configuration
+	}
+
+	changes := []datamodel.FieldChange{}
+	if previous.Description.String != current.Description.String {
+		changes = append(changes, datamodel.FieldChange{
+			Field:    "description",
+			Op:       datamodel.FieldChangeReplace,
+			OldValue: previous.Description.String,
+			NewValue: current.Description.String,
+		})
+	}
+	if previous.Visibility != current.Visibility {
+		changes = append(changes, datamodel.FieldChange{
+			Field:    "visibility",
+			Op:       datamodel.FieldChangeReplace,
+			OldValue: connectorPB.ConnectorResource_Visibility(previous.Visibility).String(),
+			NewValue: connectorPB.ConnectorResource_Visibility(current.Visibility).String(),
+		})
+	}
+
+	for _, change := range utils.DiffStruct(configurationFieldPrefix, plain[previous], plain[current]) {
+		field := strings.TrimPrefix(change.Field, configurationFieldPrefix)
+		if v, ok := utils.LookUpField(masked[previous], field); ok {
+			change.OldValue = v.AsInterface()
+		}
+		if v, ok := utils.LookUpField(masked[current], field); ok {
+			change.NewValue = v.AsInterface()
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
+// appendRevision records the state of a connector after it has been created or updated, previous is nil on creation
+func (s *service) appendRevision(ctx context.Context, userPermalink string, previous *datamodel.ConnectorResource, current *datamodel.ConnectorResource) error {
+
+	connDef, err := s.connectors.GetConnectorDefinitionByUID(current.ConnectorDefinitionUID)
+	if err != nil {
+		return err
+	}
+
+	if previous == nil {
+		previous = &datamodel.ConnectorResource{
+			Visibility: current.Visibility,
+		}
+	}
+	changes, err := s.diffConnectorResources(connDef.GetId(), previous, current)
+	if err != nil {
+		return err
+	}
+	diff, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := unmarshalConfiguration(current.Configuration)
+	if err != nil {
+		return err
+	}
+	utils.MaskCredentialFields(s.connectors, connDef.GetId(), snapshot)
+	configuration, err := snapshot.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	return s.repository.CreateConnectorRevision(ctx, &datamodel.ConnectorRevision{
+		ConnectorUID:  current.UID,
+		Configuration: configuration,
+		Diff:          diff,
+		Description:   current.Description,
+		Visibility:    current.Visibility,
+		Creator:       userPermalink,
+	})
+}
+
+func (s *service) ListUserConnectorRevisions(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, pageSize int64, pageToken string) ([]*datamodel.ConnectorRevision, int64, string, error) {
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	dbConnectorResource, err := s.checkConnectorPermission(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorRoleViewer)
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	return s.repository.ListConnectorRevisions(ctx, dbConnectorResource.UID, pageSize, pageToken)
+}
+
+func (s *service) GetUserConnectorRevision(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, revision int32) (*datamodel.ConnectorRevision, error) {
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	dbConnectorResource, err := s.checkConnectorPermission(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repository.GetConnectorRevision(ctx, dbConnectorResource.UID, revision)
+}
+
+func (s *service) DiffUserConnectorRevisions(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, from int32, to int32) ([]datamodel.FieldChange, error) {
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	dbConnectorResource, err := s.checkConnectorPermission(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	connDef, err := s.connectors.GetConnectorDefinitionByUID(dbConnectorResource.ConnectorDefinitionUID)
+	if err != nil {
+		return nil, err
+	}
+
+	fromRevision, err := s.repository.GetConnectorRevision(ctx, dbConnectorResource.UID, from)
+	if err != nil {
+		return nil, err
+	}
+	toRevision, err := s.repository.GetConnectorRevision(ctx, dbConnectorResource.UID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	// The snapshots are masked, the credential changes are only known from the diffs of the revisions in between
+	changes, err := s.diffConnectorResources(connDef.GetId(),
+		&datamodel.ConnectorResource{Configuration: fromRevision.Configuration, Description: fromRevision.Description, Visibility: fromRevision.Visibility},
+		&datamodel.ConnectorResource{Configuration: toRevision.Configuration, Description: toRevision.Description, Visibility: toRevision.Visibility},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := map[string]bool{}
+	for _, change := range changes {
+		changed[change.Field] = true
+	}
+
+	lower, upper := from, to
+	if lower > upper {
+		lower, upper = upper, lower
+	}
+	between, err := s.repository.ListConnectorRevisionsBetween(ctx, dbConnectorResource.UID, lower, upper)
+	if err != nil {
+		return nil, err
+	}
+	for _, revision := range between {
+		revisionChanges := []datamodel.FieldChange{}
+		if err := json.Unmarshal(revision.Diff, &revisionChanges); err != nil {
+			return nil, err
+		}
+		for _, change := range revisionChanges {
+			field := strings.TrimPrefix(change.Field, configurationFieldPrefix)
+			if changed[change.Field] || !s.connectors.IsCredentialField(connDef.GetId(), field) {
+				continue
+			}
+			changed[change.Field] = true
+			changes = append(changes, datamodel.FieldChange{
+				Field:    change.Field,
+				Op:       datamodel.FieldChangeReplace,
+				OldValue: change.OldValue,
+				NewValue: change.NewValue,
+			})
+		}
+	}
+
+	return changes, nil
+}
+
+func (s *service) RollbackUserConnectorResource(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, revision int32) (*connectorPB.ConnectorResource, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	dbConnectorResource, err := s.checkConnectorPermission(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	existedConnectorResource, err := s.convertDatamodelToProto(ctx, dbConnectorResource, connectorPB.View_VIEW_FULL, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if existedConnectorResource.State == connectorPB.ConnectorResource_STATE_CONNECTED {
+		st, err := sterr.CreateErrorPreconditionFailure(
+			"[service] rollback connector",
+			[]*errdetails.PreconditionFailure_Violation{
+				{
+					Type:        "UPDATE",
+					Subject:     fmt.Sprintf("id %s", id),
+					Description: fmt.Sprintf("Cannot roll back a connected %s connector", id),
+				},
+			})
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return nil, st.Err()
+	}
+
+	connRevision, err := s.repository.GetConnectorRevision(ctx, uuid.FromStringOrNil(existedConnectorResource.GetUid()), revision)
+	if err != nil {
+		return nil, err
+	}
+
+	revisionConfiguration, err := unmarshalConfiguration(connRevision.Configuration)
+	if err != nil {
+		return nil, err
+	}
+
+	connDefID, err := resource.GetRscNameID(existedConnectorResource.GetConnectorDefinitionName())
+	if err != nil {
+		return nil, err
+	}
+
+	// Same credential merge as an update: the masked credentials of the revision keep their current value
+	configuration := &structpb.Struct{}
+	s.KeepCredentialFieldsWithMaskString(connDefID, existedConnectorResource.Configuration)
+	proto.Merge(configuration, existedConnectorResource.Configuration)
+	s.RemoveCredentialFieldsWithMaskString(connDefID, revisionConfiguration)
+	proto.Merge(configuration, revisionConfiguration)
+
+	pbConnectorToUpdate := existedConnectorResource
+	pbConnectorToUpdate.Configuration = configuration
+	pbConnectorToUpdate.Description = &connRevision.Description.String
+	pbConnectorToUpdate.Visibility = connectorPB.ConnectorResource_Visibility(connRevision.Visibility)
+
+	return s.UpdateUserConnectorResourceByID(ctx, ns, userUid, id, pbConnectorToUpdate)
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"github.com/gogo/status"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+
+	"github.com/instill-ai/connector-backend/internal/resource"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/connector-backend/pkg/utils"
+	"github.com/instill-ai/x/sterr"
+)
+
+// redactSecret drops the encrypted value, secrets are write-only through the API
+func redactSecret(secret *datamodel.Secret) *datamodel.Secret {
+	secret.Value = ""
+	return secret
+}
+
+func (s *service) CreateUserSecret(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, description string, value string) (*datamodel.Secret, error) {
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	encrypted, err := s.envelope.Encrypt(value)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "[service] encrypt secret error: %s", err.Error())
+	}
+
+	secret := &datamodel.Secret{
+		ID:    id,
+		Owner: ownerPermalink,
+		Description: sql.NullString{
+			String: description,
+			Valid:  len(description) > 0,
+		},
+		Value: encrypted,
+	}
+
+	if err := s.repository.CreateUserSecret(ctx, ownerPermalink, userPermalink, secret); err != nil {
+		return nil, err
+	}
+
+	return s.GetUserSecretByID(ctx, ns, userUid, id)
+}
+
+func (s *service) ListUserSecrets(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, pageSize int64, pageToken string) ([]*datamodel.Secret, int64, string, error) {
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	secrets, totalSize, nextPageToken, err := s.repository.ListUserSecrets(ctx, ownerPermalink, userPermalink, pageSize, pageToken)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	for _, secret := range secrets {
+		redactSecret(secret)
+	}
+	return secrets, totalSize, nextPageToken, nil
+}
+
+func (s *service) GetUserSecretByID(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string) (*datamodel.Secret, error) {
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	secret, err := s.repository.GetUserSecretByID(ctx, ownerPermalink, userPermalink, id)
+	if err != nil {
+		return nil, err
+	}
+	return redactSecret(secret), nil
+}
+
+func (s *service) UpdateUserSecretByID(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, description *string, value *string) (*datamodel.Secret, error) {
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	secret := &datamodel.Secret{}
+	if description != nil {
+		secret.Description = sql.NullString{
+			String: *description,
+			Valid:  true,
+		}
+	}
+	if value != nil {
+		encrypted, err := s.envelope.Encrypt(*value)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "[service] encrypt secret error: %s", err.Error())
+		}
+		secret.Value = encrypted
+	}
+
+	if err := s.repository.UpdateUserSecretByID(ctx, ownerPermalink, userPermalink, id, secret); err != nil {
+		return nil, err
+	}
+
+	return s.GetUserSecretByID(ctx, ns, userUid, id)
+}
+
+func (s *service) DeleteUserSecretByID(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	if _, err := s.repository.GetUserSecretByID(ctx, ownerPermalink, userPermalink, id); err != nil {
+		return err
+	}
+
+	connIDs, err := s.repository.ListUserConnectorResourceIDsBySecretReference(ctx, ownerPermalink, utils.SecretReference(id))
+	if err != nil {
+		return err
+	}
+
+	if len(connIDs) > 0 {
+		st, err := sterr.CreateErrorPreconditionFailure(
+			"[service] delete secret",
+			[]*errdetails.PreconditionFailure_Violation{
+				{
+					Type:        "DELETE",
+					Subject:     fmt.Sprintf("id %s", id),
+					Description: fmt.Sprintf("The secret is still in use by connector: %s", strings.Join(connIDs, " ")),
+				},
+			})
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return st.Err()
+	}
+
+	return s.repository.DeleteUserSecretByID(ctx, ownerPermalink, userPermalink, id)
+}
+
+// lookUpSecretValue returns the decrypted value of a secret referenced by a connector configuration of the owner
+func (s *service) lookUpSecretValue(ctx context.Context, ownerPermalink string, id string) (string, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	secret, err := s.repository.GetUserSecretByID(ctx, ownerPermalink, ownerPermalink, id)
+	if err != nil {
+		st, err := sterr.CreateErrorPreconditionFailure(
+			"[service] resolve secret reference",
+			[]*errdetails.PreconditionFailure_Violation{
+				{
+					Type:        "SECRET",
+					Subject:     utils.SecretReference(id),
+					Description: fmt.Sprintf("the secret %s referenced by the connector configuration does not exist", id),
+				},
+			})
+		if err != nil {
+			logger.Error(err.Error())
+		}
+		return "", st.Err()
+	}
+
+	value, err := s.envelope.Decrypt(secret.Value)
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "[service] decrypt secret error: %s", err.Error())
+	}
+	return value, nil
+}
@@ -3,7 +3,9 @@ package service
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
@@ -18,11 +20,15 @@ import (
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/types/known/structpb"
+	"gorm.io/datatypes"
 
+	"github.com/instill-ai/connector-backend/config"
 	"github.com/instill-ai/connector-backend/internal/resource"
 	"github.com/instill-ai/connector-backend/pkg/constant"
 	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/encryption"
 	"github.com/instill-ai/connector-backend/pkg/logger"
+	"github.com/instill-ai/connector-backend/pkg/manifest"
 	"github.com/instill-ai/connector-backend/pkg/repository"
 	"github.com/instill-ai/connector-backend/pkg/utils"
 	"github.com/instill-ai/x/paginate"
@@ -55,9 +61,97 @@ type Service interface {
 
 	ListConnectorResourcesAdmin(ctx context.Context, pageSize int64, pageToken string, view connectorPB.View, filter filtering.Filter, showDeleted bool) ([]*connectorPB.ConnectorResource, int64, string, error)
 	GetConnectorResourceByUIDAdmin(ctx context.Context, uid uuid.UUID, view connectorPB.View) (*connectorPB.ConnectorResource, error)
+	UpdateConnectorResourceConfigurationAdmin(ctx context.Context, uid uuid.UUID, configuration []byte, creator string) error
+
+	// Configuration history
+	ListUserConnectorRevisions(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, pageSize int64, pageToken string) ([]*datamodel.ConnectorRevision, int64, string, error)
+	GetUserConnectorRevision(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, revision int32) (*datamodel.ConnectorRevision, error)
+	DiffUserConnectorRevisions(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, from int32, to int32) ([]datamodel.FieldChange, error)
+	RollbackUserConnectorResource(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, revision int32) (*connectorPB.ConnectorResource, error)
+
+	// Access control
+	ListUserConnectorRoleBindings(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string) ([]*datamodel.ConnectorRoleBinding, error)
+	BindUserConnectorRole(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, subject string, role datamodel.ConnectorRole) (*datamodel.ConnectorRoleBinding, error)
+	UnbindUserConnectorRole(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, subject string) error
+	CheckConnectorPermissionAdmin(ctx context.Context, connUID uuid.UUID, userUid uuid.UUID, role datamodel.ConnectorRole) (datamodel.ConnectorRole, bool, error)
+	CheckUserConnectorPermission(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, role datamodel.ConnectorRole) error
+
+	// Streaming execution
+	CreateBatchExecution(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, task string) (*BatchExecution, error)
+
+	// Asynchronous execution
+	ExecuteAsync(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, task string, inputs []*structpb.Struct, pipelineMetadata *structpb.Value) (*datamodel.ConnectorOperation, error)
+	ListUserConnectorOperations(ctx context.Context, userUid uuid.UUID, pageSize int64, pageToken string) ([]*datamodel.ConnectorOperation, int64, string, error)
+	GetUserConnectorOperation(ctx context.Context, userUid uuid.UUID, uid uuid.UUID) (*datamodel.ConnectorOperation, error)
+	CancelUserConnectorOperation(ctx context.Context, userUid uuid.UUID, uid uuid.UUID) (*datamodel.ConnectorOperation, error)
+	FailInterruptedConnectorOperations(ctx context.Context) (int64, error)
+
+	// Execution history
+	ListUserConnectorExecutions(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, pageSize int64, pageToken string, filter filtering.Filter) ([]*datamodel.ConnectorExecution, int64, string, error)
+	GetUserConnectorExecution(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, uid uuid.UUID) (*datamodel.ConnectorExecution, error)
+	PurgeConnectorExecutions(ctx context.Context) (int64, error)
+
+	// Debug capture of the execution payloads
+	GetUserConnectorDebugCapture(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string) (bool, error)
+	GetUserConnectorDefaultedFields(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string) ([]string, error)
+	UpdateUserConnectorDebugCapture(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, enabled bool) (bool, error)
+	GetUserConnectorCapture(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, uid uuid.UUID) (*datamodel.ConnectorCapture, error)
+	PurgeConnectorCaptures(ctx context.Context) (int64, error)
+
+	// Labels of the connectors
+	GetUserConnectorLabels(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string) (map[string]string, error)
+	UpdateUserConnectorLabels(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, labels map[string]string, paths []string) (map[string]string, error)
+
+	// Replay of the failed executions
+	ReplayUserConnectorExecution(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, uid uuid.UUID) (*ReplayResult, error)
+	ReplayUserConnectorFailedExecutions(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, window time.Duration) ([]*ReplayResult, error)
+	BulkCreateUserConnectorResources(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, connectorResources []*connectorPB.ConnectorResource, allOrNothing bool) ([]*BulkItemResult, error)
+	BulkUpdateUserConnectorResources(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, connectorResources []*connectorPB.ConnectorResource, allOrNothing bool) ([]*BulkItemResult, error)
+	BulkDeleteUserConnectorResources(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, ids []string, allOrNothing bool) ([]*BulkItemResult, error)
+	ExportUserConnectorResources(ctx context.Context, ns resource.Namespace, userUid uuid.UUID) ([]*manifest.Manifest, error)
+	CloneUserConnectorResource(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, targetNs resource.Namespace, newID string, description *string, override *structpb.Struct) (*connectorPB.ConnectorResource, error)
+	UndeleteUserConnectorResource(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string) (*connectorPB.ConnectorResource, error)
+	PurgeDeletedConnectorResources(ctx context.Context) (int64, error)
+	PurgeConnectorDeadLetters(ctx context.Context) (int64, error)
+
+	// Idempotent requests
+	BeginIdempotentRequest(ctx context.Context, req *IdempotentRequest) ([]byte, error)
+	CompleteIdempotentRequest(ctx context.Context, req *IdempotentRequest, response []byte) error
+	AbandonIdempotentRequest(ctx context.Context, req *IdempotentRequest) error
+
+	// Secret store
+	CreateUserSecret(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, description string, value string) (*datamodel.Secret, error)
+	ListUserSecrets(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, pageSize int64, pageToken string) ([]*datamodel.Secret, int64, string, error)
+	GetUserSecretByID(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string) (*datamodel.Secret, error)
+	UpdateUserSecretByID(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, description *string, value *string) (*datamodel.Secret, error)
+	DeleteUserSecretByID(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string) error
 
 	// Execute connector
-	Execute(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, task string, inputs []*structpb.Struct) ([]*structpb.Struct, error)
+	Execute(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, executeUID uuid.UUID, task string, inputs []*structpb.Struct) (*ExecutionResult, error)
+	ValidateInputs(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, task string, inputs []*structpb.Struct) error
+
+	// Rate limits of the connector executions
+	GetUserConnectorRateLimit(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string) (*datamodel.RateLimit, error)
+	UpdateUserConnectorRateLimit(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, rate float64, burst int32) (*datamodel.RateLimit, error)
+	DeleteUserConnectorRateLimit(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string) error
+	GetNamespaceRateLimit(ctx context.Context, ns resource.Namespace, userUid uuid.UUID) (*datamodel.RateLimit, error)
+	UpdateNamespaceRateLimit(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, rate float64, burst int32) (*datamodel.RateLimit, error)
+	DeleteNamespaceRateLimit(ctx context.Context, ns resource.Namespace, userUid uuid.UUID) error
+	GetConnectorDefinitionRateLimitAdmin(ctx context.Context, defUID uuid.UUID) (*datamodel.RateLimit, error)
+	UpdateConnectorDefinitionRateLimitAdmin(ctx context.Context, defUID uuid.UUID, rate float64, burst int32) (*datamodel.RateLimit, error)
+	DeleteConnectorDefinitionRateLimitAdmin(ctx context.Context, defUID uuid.UUID) error
+
+	// Max concurrency of the connector executions
+	GetUserConnectorMaxConcurrency(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string) (int32, error)
+	UpdateUserConnectorMaxConcurrency(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, maxConcurrency int32) (int32, error)
+
+	// Result cache of the connector executions
+	GetUserConnectorCacheTTL(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string) (time.Duration, error)
+	UpdateUserConnectorCacheTTL(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, ttl time.Duration) (time.Duration, error)
+
+	// Retry policy of the connector executions
+	GetUserConnectorRetryPolicy(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string) (*datamodel.RetryPolicy, error)
+	UpdateUserConnectorRetryPolicy(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, policy *datamodel.RetryPolicy) (*datamodel.RetryPolicy, error)
 
 	// Shared public/private method for checking connector's connection
 	CheckConnectorResourceByUID(ctx context.Context, connUID uuid.UUID) (*connectorPB.ConnectorResource_State, error)
@@ -88,6 +182,10 @@ type service struct {
 	influxDBWriteClient         api.WriteAPI
 	redisClient                 *redis.Client
 	connectors                  componentBase.IConnector
+	envelope                    *encryption.Envelope
+	executionPool               *executionPool
+	worker                      string
+	journal                     *controllerJournal // set on the copies of the service running a bulk request
 }
 
 // NewService initiates a service instance
@@ -99,8 +197,13 @@ func NewService(
 	c controllerPB.ControllerPrivateServiceClient,
 	rc *redis.Client,
 	i api.WriteAPI,
+	e *encryption.Envelope,
 ) Service {
 	logger, _ := logger.GetZapLogger(t)
+	worker, err := os.Hostname()
+	if err != nil {
+		logger.Warn(fmt.Sprintf("unknown hostname, asynchronous executions will not be recovered: %s", err))
+	}
 	return &service{
 		repository:                  r,
 		mgmtPrivateServiceClient:    u,
@@ -109,6 +212,9 @@ func NewService(
 		redisClient:                 rc,
 		influxDBWriteClient:         i,
 		connectors:                  connector.Init(logger, utils.GetConnectorOptions()),
+		envelope:                    e,
+		executionPool:               newExecutionPool(t, config.Config.Execution.Workers, config.Config.Execution.QueueSize),
+		worker:                      worker,
 	}
 }
 
@@ -201,6 +307,100 @@ func (s *service) KeepCredentialFieldsWithMaskString(dbConnDefID string, config
 	utils.KeepCredentialFieldsWithMaskString(s.connectors, dbConnDefID, config)
 }
 
+// encryptConfiguration encrypts the credential fields of a configuration before it is persisted
+func (s *service) encryptConfiguration(connDefID string, configuration *structpb.Struct) error {
+	if err := utils.EncryptCredentialFields(s.connectors, connDefID, configuration, s.envelope); err != nil {
+		return status.Errorf(codes.Internal, "[service] encrypt connector configuration error: %s", err.Error())
+	}
+	return nil
+}
+
+// checkEncryptedCredentials rejects the credential fields holding an encrypted value other than the stored one:
+// such a value is not encrypted again and would fail to decrypt at execution. stored is nil on creation.
+func (s *service) checkEncryptedCredentials(connDefID string, configuration *structpb.Struct, stored *structpb.Struct) error {
+
+	violations := []*errdetails.BadRequest_FieldViolation{}
+	for _, field := range utils.EncryptedCredentialFields(s.connectors, connDefID, configuration) {
+		value, _ := utils.LookUpField(configuration, field)
+		if storedValue, ok := utils.LookUpField(stored, field); ok && storedValue.GetStringValue() == value.GetStringValue() {
+			continue
+		}
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       "/configuration/" + strings.ReplaceAll(field, ".", "/"),
+			Description: "the value of a credential field can not be an encrypted value",
+		})
+	}
+
+	if len(violations) > 0 {
+		st, _ := sterr.CreateErrorBadRequest("[service] invalid configuration", violations)
+		return st.Err()
+	}
+	return nil
+}
+
+// decryptConfiguration returns a copy of the configuration with its credential fields decrypted
+func (s *service) decryptConfiguration(connDefID string, configuration *structpb.Struct) (*structpb.Struct, error) {
+	plain := &structpb.Struct{}
+	if configuration != nil {
+		plain = proto.Clone(configuration).(*structpb.Struct)
+	}
+	if err := utils.DecryptCredentialFields(s.connectors, connDefID, plain, s.envelope); err != nil {
+		return nil, status.Errorf(codes.Internal, "[service] decrypt connector configuration error: %s", err.Error())
+	}
+	return plain, nil
+}
+
+// getExecutionConfiguration returns the stored configuration with its credential fields decrypted and its
+// secret references resolved. The result must only be handed to the connector and never be returned to the client.
+func (s *service) getExecutionConfiguration(ctx context.Context, dbConnectorResource *datamodel.ConnectorResource) (*structpb.Struct, error) {
+
+	if dbConnectorResource.Configuration == nil {
+		return nil, nil
+	}
+
+	configuration := &structpb.Struct{}
+	if err := configuration.UnmarshalJSON(dbConnectorResource.Configuration); err != nil {
+		return nil, err
+	}
+
+	connDef, err := s.connectors.GetConnectorDefinitionByUID(dbConnectorResource.ConnectorDefinitionUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := utils.DecryptCredentialFields(s.connectors, connDef.GetId(), configuration, s.envelope); err != nil {
+		return nil, status.Errorf(codes.Internal, "[service] decrypt connector configuration error: %s", err.Error())
+	}
+
+	if err := utils.ResolveSecretReferences(configuration, func(id string) (string, error) {
+		return s.lookUpSecretValue(ctx, dbConnectorResource.Owner, id)
+	}); err != nil {
+		return nil, err
+	}
+
+	return configuration, nil
+}
+
+// filterConnectorTypes returns the connector types named in the filter, e.g.
+// `connector_type = CONNECTOR_TYPE_AI OR connector_type = CONNECTOR_TYPE_DATA`
+func filterConnectorTypes(filter filtering.Filter) (map[string]bool, error) {
+	trans := repository.NewTranspiler(filter)
+	expr, err := trans.Transpile()
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "[service] invalid filter: %s", err.Error())
+	}
+	typeMap := map[string]bool{}
+	if expr == nil {
+		return typeMap, nil
+	}
+	for _, v := range expr.Vars {
+		if name, ok := v.(protoreflect.Name); ok {
+			typeMap[string(name)] = true
+		}
+	}
+	return typeMap, nil
+}
+
 func (s *service) ListConnectorDefinitions(ctx context.Context, pageSize int64, pageToken string, view connectorPB.View, filter filtering.Filter) ([]*connectorPB.ConnectorDefinition, int64, string, error) {
 
 	logger, _ := logger.GetZapLogger(ctx)
@@ -246,16 +446,9 @@ func (s *service) ListConnectorDefinitions(ctx context.Context, pageSize int64,
 
 	var defs []*connectorPB.ConnectorDefinition
 	if filter.CheckedExpr != nil {
-		trans := repository.NewTranspiler(filter)
-		expr, _ := trans.Transpile()
-		typeMap := map[string]bool{}
-		for idx := range expr.Vars {
-			if idx == 0 {
-				typeMap[string(expr.Vars[idx].(protoreflect.Name))] = true
-			} else {
-				typeMap[string(expr.Vars[idx].([]interface{})[0].(protoreflect.Name))] = true
-			}
-
+		typeMap, err := filterConnectorTypes(filter)
+		if err != nil {
+			return nil, 0, "", err
 		}
 		for idx := range unfilteredDefs {
 			if _, ok := typeMap[unfilteredDefs[idx].Type.String()]; ok {
@@ -374,7 +567,27 @@ func (s *service) CreateUserConnectorResource(ctx context.Context, ns resource.N
 		return nil, err
 	}
 
-	connConfig, err := connectorResource.GetConfiguration().MarshalJSON()
+	configuration := proto.Clone(connectorResource.GetConfiguration()).(*structpb.Struct)
+	if configuration == nil {
+		configuration = &structpb.Struct{}
+	}
+	defaultedFields, err := json.Marshal(defaultConfiguration(connDefResp, configuration))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkEncryptedCredentials(connDefResp.GetId(), configuration, nil); err != nil {
+		return nil, err
+	}
+	if err := validateConfiguration(connDefResp, configuration); err != nil {
+		return nil, err
+	}
+
+	if err := s.encryptConfiguration(connDefResp.GetId(), configuration); err != nil {
+		return nil, err
+	}
+
+	connConfig, err := configuration.MarshalJSON()
 	if err != nil {
 
 		return nil, err
@@ -394,6 +607,8 @@ func (s *service) CreateUserConnectorResource(ctx context.Context, ns resource.N
 		ConnectorType:          datamodel.ConnectorResourceType(connDefResp.GetType()),
 		Description:            connDesc,
 		Visibility:             datamodel.ConnectorResourceVisibility(connectorResource.Visibility),
+		DefaultedFields:        defaultedFields,
+		Labels:                 datatypes.JSON("{}"),
 	}
 
 	if existingConnector, _ := s.repository.GetUserConnectorResourceByID(ctx, ownerPermalink, userPermalink, dbConnectorResourceToCreate.ID, true); existingConnector != nil {
@@ -411,20 +626,29 @@ func (s *service) CreateUserConnectorResource(ctx context.Context, ns resource.N
 		return nil, st.Err()
 	}
 
-	if err := s.repository.CreateUserConnectorResource(ctx, ownerPermalink, userPermalink, dbConnectorResourceToCreate); err != nil {
-		return nil, err
-	}
+	// The connector is not created without its first revision
+	var dbConnectorResource *datamodel.ConnectorResource
+	if err := s.transaction(ctx, func(s *service) error {
+		if err := s.repository.CreateUserConnectorResource(ctx, ownerPermalink, userPermalink, dbConnectorResourceToCreate); err != nil {
+			return err
+		}
 
-	// User desire state = DISCONNECTED
-	if err := s.repository.UpdateUserConnectorResourceStateByID(ctx, ownerPermalink, userPermalink, dbConnectorResourceToCreate.ID, datamodel.ConnectorResourceState(connectorPB.ConnectorResource_STATE_DISCONNECTED)); err != nil {
-		return nil, err
-	}
-	if err := s.UpdateResourceState(dbConnectorResourceToCreate.UID, connectorPB.ConnectorResource_STATE_DISCONNECTED, nil); err != nil {
-		return nil, err
-	}
+		// User desire state = DISCONNECTED
+		if err := s.repository.UpdateUserConnectorResourceStateByID(ctx, ownerPermalink, userPermalink, dbConnectorResourceToCreate.ID, datamodel.ConnectorResourceState(connectorPB.ConnectorResource_STATE_DISCONNECTED)); err != nil {
+			return err
+		}
+		if err := s.UpdateResourceState(dbConnectorResourceToCreate.UID, connectorPB.ConnectorResource_STATE_DISCONNECTED, nil); err != nil {
+			return err
+		}
 
-	dbConnectorResource, err := s.repository.GetUserConnectorResourceByID(ctx, ownerPermalink, userPermalink, dbConnectorResourceToCreate.ID, false)
-	if err != nil {
+		var err error
+		dbConnectorResource, err = s.repository.GetUserConnectorResourceByID(ctx, ownerPermalink, userPermalink, dbConnectorResourceToCreate.ID, false)
+		if err != nil {
+			return err
+		}
+
+		return s.appendRevision(ctx, userPermalink, nil, dbConnectorResource)
+	}); err != nil {
 		return nil, err
 	}
 
@@ -480,60 +704,179 @@ func (s *service) GetConnectorResourceByUIDAdmin(ctx context.Context, uid uuid.U
 	return s.convertDatamodelToProto(ctx, dbConnectorResource, view, true)
 }
 
+// UpdateConnectorResourceConfigurationAdmin replaces the stored configuration of a connector, whose credential
+// fields are already encrypted, and records the revision on behalf of the creator
+func (s *service) UpdateConnectorResourceConfigurationAdmin(ctx context.Context, uid uuid.UUID, configuration []byte, creator string) error {
+
+	previousConnectorResource, err := s.repository.GetConnectorResourceByUIDAdmin(ctx, uid, false)
+	if err != nil {
+		return err
+	}
+
+	return s.transaction(ctx, func(s *service) error {
+		ownerPermalink := previousConnectorResource.Owner
+		if err := s.repository.UpdateUserConnectorResourceByID(ctx, ownerPermalink, ownerPermalink, previousConnectorResource.ID, &datamodel.ConnectorResource{Configuration: configuration}); err != nil {
+			return err
+		}
+
+		dbConnectorResource, err := s.repository.GetConnectorResourceByUIDAdmin(ctx, uid, false)
+		if err != nil {
+			return err
+		}
+
+		return s.appendRevision(ctx, creator, previousConnectorResource, dbConnectorResource)
+	})
+}
+
 func (s *service) UpdateUserConnectorResourceByID(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, connectorResource *connectorPB.ConnectorResource) (*connectorPB.ConnectorResource, error) {
 
 	ownerPermalink := ns.String()
 	userPermalink := resource.UserUidToUserPermalink(userUid)
 
-	dbConnectorResourceToUpdate, err := s.convertProtoToDatamodel(ctx, connectorResource)
+	previousConnectorResource, err := s.checkConnectorPermission(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorRoleEditor)
 	if err != nil {
 		return nil, err
 	}
-	dbConnectorResourceToUpdate.Owner = ownerPermalink
 
-	if err := s.repository.UpdateUserConnectorResourceByID(ctx, ownerPermalink, userPermalink, id, dbConnectorResourceToUpdate); err != nil {
+	connectorResource = proto.Clone(connectorResource).(*connectorPB.ConnectorResource)
+	connDefID, err := resource.GetRscNameID(connectorResource.GetConnectorDefinitionName())
+	if err != nil {
 		return nil, err
 	}
-
-	// Check connector state
-	if err := s.UpdateResourceState(dbConnectorResourceToUpdate.UID, connectorPB.ConnectorResource_STATE_DISCONNECTED, nil); err != nil {
+	connDef, err := s.connectors.GetConnectorDefinitionByID(connDefID)
+	if err != nil {
+		return nil, err
+	}
+	storedConfiguration, err := unmarshalConfiguration(previousConnectorResource.Configuration)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkEncryptedCredentials(connDefID, connectorResource.GetConfiguration(), storedConfiguration); err != nil {
+		return nil, err
+	}
+	// The configuration is merged with the stored one by the handler, the credentials it keeps are encrypted
+	plainConfiguration, err := s.decryptConfiguration(connDefID, connectorResource.GetConfiguration())
+	if err != nil {
+		return nil, err
+	}
+	if err := validateConfiguration(connDef, plainConfiguration); err != nil {
+		return nil, err
+	}
+	defaultedFields, err := json.Marshal(stillDefaulted(connDef, unmarshalDefaultedFields(previousConnectorResource), connectorResource.GetConfiguration()))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.encryptConfiguration(connDefID, connectorResource.GetConfiguration()); err != nil {
 		return nil, err
 	}
 
-	dbConnectorResource, err := s.repository.GetUserConnectorResourceByID(ctx, ownerPermalink, userPermalink, dbConnectorResourceToUpdate.ID, false)
+	dbConnectorResourceToUpdate, err := s.convertProtoToDatamodel(ctx, connectorResource)
 	if err != nil {
 		return nil, err
 	}
+	dbConnectorResourceToUpdate.Owner = ownerPermalink
+	dbConnectorResourceToUpdate.DefaultedFields = defaultedFields
+
+	// The update is not stored without its revision
+	var dbConnectorResource *datamodel.ConnectorResource
+	if err := s.transaction(ctx, func(s *service) error {
+		// The permission is checked, the update is made on behalf of the owner
+		if err := s.repository.UpdateUserConnectorResourceByID(ctx, ownerPermalink, ownerPermalink, id, dbConnectorResourceToUpdate); err != nil {
+			return err
+		}
+
+		// Check connector state
+		if err := s.UpdateResourceState(dbConnectorResourceToUpdate.UID, connectorPB.ConnectorResource_STATE_DISCONNECTED, nil); err != nil {
+			return err
+		}
+
+		var err error
+		dbConnectorResource, err = s.repository.GetUserConnectorResourceByID(ctx, ownerPermalink, userPermalink, dbConnectorResourceToUpdate.ID, false)
+		if err != nil {
+			return err
+		}
+
+		return s.appendRevision(ctx, userPermalink, previousConnectorResource, dbConnectorResource)
+	}); err != nil {
+		return nil, err
+	}
 
 	return s.convertDatamodelToProto(ctx, dbConnectorResource, connectorPB.View_VIEW_FULL, true)
 
 }
 
 func (s *service) DeleteUserConnectorResourceByID(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string) error {
-	logger, _ := logger.GetZapLogger(ctx)
-
 	ownerPermalink := ns.String()
 	userPermalink := resource.UserUidToUserPermalink(userUid)
 
-	dbConnector, err := s.repository.GetUserConnectorResourceByID(ctx, ownerPermalink, userPermalink, id, false)
+	dbConnector, err := s.checkConnectorPermission(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorRoleAdmin)
 	if err != nil {
 		return err
 	}
 
-	filter := fmt.Sprintf("recipe.components.resource_name:\"connector-resources/%s\"", dbConnector.UID)
-
-	pipeResp, err := s.pipelinePublicServiceClient.ListPipelines(s.injectUserToContext(context.Background(), ownerPermalink), &pipelinePB.ListPipelinesRequest{
-		Filter: &filter,
-	})
+	pipeIDs, err := s.listConnectorPipelineIDs(ownerPermalink, []uuid.UUID{dbConnector.UID})
 	if err != nil {
 		return err
 	}
 
-	if len(pipeResp.Pipelines) > 0 {
-		var pipeIDs []string
-		for _, pipe := range pipeResp.Pipelines {
-			pipeIDs = append(pipeIDs, pipe.GetId())
+	return s.deleteConnectorResource(ctx, ownerPermalink, dbConnector, pipeIDs[dbConnector.UID])
+}
+
+// listConnectorPipelineIDs returns the ids of the pipelines using each of the connectors, it makes a single
+// ListPipelines round trip per page of pipelines
+func (s *service) listConnectorPipelineIDs(ownerPermalink string, connUIDs []uuid.UUID) (map[uuid.UUID][]string, error) {
+
+	pipeIDs := map[uuid.UUID][]string{}
+	if len(connUIDs) == 0 {
+		return pipeIDs, nil
+	}
+
+	filters := make([]string, len(connUIDs))
+	for idx, connUID := range connUIDs {
+		filters[idx] = fmt.Sprintf("recipe.components.resource_name:\"connector-resources/%s\"", connUID)
+	}
+	filter := strings.Join(filters, " OR ")
+	view := pipelinePB.View_VIEW_RECIPE
+	pageToken := ""
+
+	for {
+		pipeResp, err := s.pipelinePublicServiceClient.ListPipelines(s.injectUserToContext(context.Background(), ownerPermalink), &pipelinePB.ListPipelinesRequest{
+			Filter:    &filter,
+			View:      &view,
+			PageToken: &pageToken,
+		})
+		if err != nil {
+			return nil, err
 		}
+
+		for _, pipe := range pipeResp.GetPipelines() {
+			used := map[uuid.UUID]bool{}
+			for _, component := range pipe.GetRecipe().GetComponents() {
+				name := component.GetResourceName()
+				connUID, err := uuid.FromString(name[strings.LastIndex(name, "/")+1:])
+				if err != nil || used[connUID] {
+					continue
+				}
+				used[connUID] = true
+				pipeIDs[connUID] = append(pipeIDs[connUID], pipe.GetId())
+			}
+		}
+
+		pageToken = pipeResp.GetNextPageToken()
+		if pageToken == "" {
+			return pipeIDs, nil
+		}
+	}
+}
+
+// deleteConnectorResource deletes a connector which is not in use by the pipelines
+func (s *service) deleteConnectorResource(ctx context.Context, ownerPermalink string, dbConnector *datamodel.ConnectorResource, pipeIDs []string) error {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	id := dbConnector.ID
+
+	if len(pipeIDs) > 0 {
 		st, err := sterr.CreateErrorPreconditionFailure(
 			"[service] delete connector",
 			[]*errdetails.PreconditionFailure_Violation{
@@ -553,7 +896,8 @@ func (s *service) DeleteUserConnectorResourceByID(ctx context.Context, ns resour
 		return err
 	}
 
-	return s.repository.DeleteUserConnectorResourceByID(ctx, ownerPermalink, userPermalink, id)
+	// The role bindings are kept for the connector to be undeleted, they are removed along with it on purge
+	return s.repository.DeleteUserConnectorResourceByID(ctx, ownerPermalink, ownerPermalink, id)
 }
 
 func (s *service) UpdateUserConnectorResourceStateByID(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, state connectorPB.ConnectorResource_State) (*connectorPB.ConnectorResource, error) {
@@ -562,7 +906,7 @@ func (s *service) UpdateUserConnectorResourceStateByID(ctx context.Context, ns r
 	userPermalink := resource.UserUidToUserPermalink(userUid)
 
 	// Validation: trigger and response connector cannot be disconnected
-	conn, err := s.repository.GetUserConnectorResourceByID(ctx, ownerPermalink, userPermalink, id, false)
+	conn, err := s.checkConnectorPermission(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorRoleEditor)
 	if err != nil {
 		return nil, err
 	}
@@ -584,7 +928,7 @@ func (s *service) UpdateUserConnectorResourceStateByID(ctx context.Context, ns r
 	case connectorPB.ConnectorResource_STATE_CONNECTED:
 
 		// Set connector state to user desire state
-		if err := s.repository.UpdateUserConnectorResourceStateByID(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorResourceState(connectorPB.ConnectorResource_STATE_CONNECTED)); err != nil {
+		if err := s.repository.UpdateUserConnectorResourceStateByID(ctx, ownerPermalink, ownerPermalink, id, datamodel.ConnectorResourceState(connectorPB.ConnectorResource_STATE_CONNECTED)); err != nil {
 			return nil, err
 		}
 
@@ -594,7 +938,7 @@ func (s *service) UpdateUserConnectorResourceStateByID(ctx context.Context, ns r
 
 	case connectorPB.ConnectorResource_STATE_DISCONNECTED:
 
-		if err := s.repository.UpdateUserConnectorResourceStateByID(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorResourceState(connectorPB.ConnectorResource_STATE_DISCONNECTED)); err != nil {
+		if err := s.repository.UpdateUserConnectorResourceStateByID(ctx, ownerPermalink, ownerPermalink, id, datamodel.ConnectorResourceState(connectorPB.ConnectorResource_STATE_DISCONNECTED)); err != nil {
 			return nil, err
 		}
 		if err := s.UpdateResourceState(conn.UID, connectorPB.ConnectorResource_State(state), nil); err != nil {
@@ -619,7 +963,11 @@ func (s *service) UpdateUserConnectorResourceIDByID(ctx context.Context, ns reso
 	ownerPermalink := ns.String()
 	userPermalink := resource.UserUidToUserPermalink(userUid)
 
-	if err := s.repository.UpdateUserConnectorResourceIDByID(ctx, ownerPermalink, userPermalink, id, newID); err != nil {
+	if _, err := s.checkConnectorPermission(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorRoleEditor); err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.UpdateUserConnectorResourceIDByID(ctx, ownerPermalink, ownerPermalink, id, newID); err != nil {
 		return nil, err
 	}
 
@@ -632,36 +980,74 @@ func (s *service) UpdateUserConnectorResourceIDByID(ctx context.Context, ns reso
 
 }
 
-func (s *service) Execute(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, task string, inputs []*structpb.Struct) ([]*structpb.Struct, error) {
+// ExecutionResult is the result of a connector execution, Attempts is the number of attempts made under the
+// retry policy of the connector and CacheHit tells whether the outputs come from the result cache
+type ExecutionResult struct {
+	Outputs  []*structpb.Struct
+	Attempts int
+	CacheHit bool
+}
+
+// Execute runs the task of the connector on the inputs. The result is also returned along with an execution
+// error to report the attempts. The payloads are captured under the executeUID if the debug capture is enabled
+// and the inputs of a failed execution are kept under it to be replayed.
+func (s *service) Execute(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, executeUID uuid.UUID, task string, inputs []*structpb.Struct) (*ExecutionResult, error) {
 
-	logger, _ := logger.GetZapLogger(ctx)
 	ownerPermalink := ns.String()
 	userPermalink := resource.UserUidToUserPermalink(userUid)
 
-	dbConnectorResource, err := s.repository.GetUserConnectorResourceByID(ctx, ownerPermalink, userPermalink, id, false)
+	dbConnectorResource, err := s.checkConnectorPermission(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorRoleExecutor)
 	if err != nil {
-		return nil, err
+		return &ExecutionResult{}, err
 	}
 
-	configuration := func() *structpb.Struct {
-		if dbConnectorResource.Configuration != nil {
-			str := structpb.Struct{}
-			err := str.UnmarshalJSON(dbConnectorResource.Configuration)
-			if err != nil {
-				logger.Fatal(err.Error())
-			}
-			return &str
-		}
-		return nil
-	}()
+	if err := s.checkRateLimits(ctx, dbConnectorResource); err != nil {
+		return &ExecutionResult{}, err
+	}
+
+	result, err := s.executeCachedConnector(ctx, dbConnectorResource, task, inputs)
+	if err != nil {
+		s.captureExecution(ctx, dbConnectorResource, executeUID, inputs, nil, err.Error())
+		s.storeDeadLetter(ctx, dbConnectorResource, executeUID, userUid, task, inputs, err.Error())
+	} else {
+		s.captureExecution(ctx, dbConnectorResource, executeUID, inputs, result.Outputs, "")
+	}
+	return result, err
+}
+
+// executeConnector runs the task of the connector on the inputs, the transient errors are retried
+func (s *service) executeConnector(ctx context.Context, dbConnectorResource *datamodel.ConnectorResource, task string, inputs []*structpb.Struct) (*ExecutionResult, error) {
+
+	logger, _ := logger.GetZapLogger(ctx)
+
+	configuration, err := s.getExecutionConfiguration(ctx, dbConnectorResource)
+	if err != nil {
+		return &ExecutionResult{}, err
+	}
 
 	con, err := s.connectors.CreateExecution(dbConnectorResource.ConnectorDefinitionUID, task, configuration, logger)
 
 	if err != nil {
-		return nil, err
+		return &ExecutionResult{}, err
 	}
 
-	return con.ExecuteWithValidation(inputs)
+	release, err := s.acquireExecutionSlot(ctx, dbConnectorResource)
+	if err != nil {
+		return &ExecutionResult{}, err
+	}
+	defer release()
+
+	result := &ExecutionResult{}
+	result.Attempts, err = resolveRetryPolicy(ctx, dbConnectorResource).retry(ctx, func() error {
+		var err error
+		result.Outputs, err = con.ExecuteWithValidation(inputs)
+		return err
+	})
+	if err != nil {
+		result.Outputs = nil
+		return result, err
+	}
+	return result, nil
 }
 
 func (s *service) CheckConnectorResourceByUID(ctx context.Context, connUID uuid.UUID) (*connectorPB.ConnectorResource_State, error) {
@@ -673,23 +1059,20 @@ func (s *service) CheckConnectorResourceByUID(ctx context.Context, connUID uuid.
 		return connectorPB.ConnectorResource_STATE_ERROR.Enum(), nil
 	}
 
-	configuration := func() *structpb.Struct {
-		if dbConnector.Configuration != nil {
-			str := structpb.Struct{}
-			err := str.UnmarshalJSON(dbConnector.Configuration)
-			if err != nil {
-				logger.Fatal(err.Error())
-			}
-			return &str
-		}
-		return nil
-	}()
-
-	state, err := s.connectors.Test(dbConnector.ConnectorDefinitionUID, configuration, logger)
+	configuration, err := s.getExecutionConfiguration(ctx, dbConnector)
 	if err != nil {
 		return connectorPB.ConnectorResource_STATE_ERROR.Enum(), nil
 	}
 
+	var state connectorPB.ConnectorResource_State
+	if _, err = resolveRetryPolicy(ctx, dbConnector).retry(ctx, func() error {
+		var err error
+		state, err = s.connectors.Test(dbConnector.ConnectorDefinitionUID, configuration, logger)
+		return err
+	}); err != nil {
+		return connectorPB.ConnectorResource_STATE_ERROR.Enum(), nil
+	}
+
 	switch state {
 	case connectorPB.ConnectorResource_STATE_CONNECTED:
 		if err := s.UpdateResourceState(dbConnector.UID, connectorPB.ConnectorResource_STATE_CONNECTED, nil); err != nil {
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"go.einride.tech/aip/filtering"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/instill-ai/connector-backend/pkg/encryption"
+
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+type filterRequest string
+
+func (f filterRequest) GetFilter() string {
+	return string(f)
+}
+
+func TestFilterConnectorTypes(t *testing.T) {
+	var connType connectorPB.ConnectorType
+	declarations, err := filtering.NewDeclarations(
+		filtering.DeclareStandardFunctions(),
+		filtering.DeclareEnumIdent("connector_type", connType.Type()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		filter string
+		want   map[string]bool
+	}{
+		{
+			filter: "connector_type = CONNECTOR_TYPE_AI",
+			want:   map[string]bool{"CONNECTOR_TYPE_AI": true},
+		},
+		{
+			filter: "connector_type = CONNECTOR_TYPE_AI OR connector_type = CONNECTOR_TYPE_DATA",
+			want:   map[string]bool{"CONNECTOR_TYPE_AI": true, "CONNECTOR_TYPE_DATA": true},
+		},
+		{
+			filter: "connector_type = CONNECTOR_TYPE_AI OR connector_type = CONNECTOR_TYPE_DATA OR connector_type = CONNECTOR_TYPE_BLOCKCHAIN",
+			want:   map[string]bool{"CONNECTOR_TYPE_AI": true, "CONNECTOR_TYPE_DATA": true, "CONNECTOR_TYPE_BLOCKCHAIN": true},
+		},
+		{
+			filter: "connector_type = CONNECTOR_TYPE_AI AND connector_type = CONNECTOR_TYPE_AI",
+			want:   map[string]bool{"CONNECTOR_TYPE_AI": true},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.filter, func(t *testing.T) {
+			filter, err := filtering.ParseFilter(filterRequest(tc.filter), declarations)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := filterConnectorTypes(filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCheckEncryptedCredentials(t *testing.T) {
+	provider, err := encryption.NewLocalKeyProvider(filepath.Join(t.TempDir(), "kek"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	envelope := encryption.NewEnvelope(provider)
+	s := &service{connectors: &fakeConnectors{}, envelope: envelope}
+
+	storedValue, err := envelope.Encrypt("sk-123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherValue, err := envelope.Encrypt("sk-456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err := structpb.NewStruct(map[string]interface{}{"api_key": storedValue})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name          string
+		configuration map[string]interface{}
+		stored        *structpb.Struct
+		wantErr       bool
+	}{
+		{name: "plaintext on create", configuration: map[string]interface{}{"api_key": "sk-123"}},
+		{name: "secret reference on create", configuration: map[string]interface{}{"api_key": "${secrets/openai-key}"}},
+		{name: "encrypted value on create", configuration: map[string]interface{}{"api_key": storedValue}, wantErr: true},
+		{name: "forged value on create", configuration: map[string]interface{}{"api_key": "instill-enc:v1:forged"}, wantErr: true},
+		{name: "stored value kept on update", configuration: map[string]interface{}{"api_key": storedValue}, stored: stored},
+		{name: "plaintext on update", configuration: map[string]interface{}{"api_key": "sk-456"}, stored: stored},
+		{name: "other encrypted value on update", configuration: map[string]interface{}{"api_key": otherValue}, stored: stored, wantErr: true},
+		{name: "encrypted value in a field that is not a credential", configuration: map[string]interface{}{"model": otherValue}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			configuration, err := structpb.NewStruct(tc.configuration)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = s.checkEncryptedCredentials("ai-test", configuration, tc.stored)
+			if tc.wantErr {
+				if status.Code(err) != codes.InvalidArgument {
+					t.Errorf("got %v, want an invalid argument error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
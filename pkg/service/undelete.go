@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/instill-ai/connector-backend/config"
+	"github.com/instill-ai/connector-backend/internal/resource"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/x/sterr"
+
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+// defaultDeletedConnectorRetentionDays is how many days a deleted connector can be restored when it is not configured
+const defaultDeletedConnectorRetentionDays = 30
+
+// UndeleteUserConnectorResource restores the latest deleted connector with the id along with its role bindings. It is
+// restored disconnected and fails if another connector got the id meanwhile.
+func (s *service) UndeleteUserConnectorResource(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string) (*connectorPB.ConnectorResource, error) {
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	// The roles granted on a deleted connector do not apply until it is restored, only the owner can restore it
+	if ownerPermalink != userPermalink {
+		return nil, status.Errorf(codes.PermissionDenied, "[service] undelete connector error: only the owner can restore a deleted connector")
+	}
+
+	dbConnectorResource, err := s.repository.GetUserDeletedConnectorResourceByID(ctx, ownerPermalink, id)
+	if err != nil {
+		return nil, err
+	}
+
+	idReusedErr := func() error {
+		st, _ := sterr.CreateErrorPreconditionFailure(
+			"[service] undelete connector",
+			[]*errdetails.PreconditionFailure_Violation{
+				{
+					Type:        "UNDELETE",
+					Subject:     fmt.Sprintf("id %s", id),
+					Description: "the id is used by another connector created after the deletion, delete or rename it first",
+				},
+			})
+		return st.Err()
+	}
+	if _, err := s.repository.GetUserConnectorResourceByID(ctx, ownerPermalink, userPermalink, id, true); err == nil {
+		return nil, idReusedErr()
+	} else if status.Code(err) != codes.NotFound {
+		return nil, err
+	}
+
+	results, err := s.runBulk(ctx, 1, true, func(s *service, _ int) (*connectorPB.ConnectorResource, error) {
+
+		if err := s.repository.UndeleteConnectorResourceByUID(ctx, dbConnectorResource.UID); err != nil {
+			// The id was taken between the check and the restore
+			if status.Code(err) == codes.FailedPrecondition {
+				return nil, idReusedErr()
+			}
+			return nil, err
+		}
+		if err := s.repository.UpdateUserConnectorResourceStateByID(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorResourceState(connectorPB.ConnectorResource_STATE_DISCONNECTED)); err != nil {
+			return nil, err
+		}
+		if err := s.UpdateResourceState(dbConnectorResource.UID, connectorPB.ConnectorResource_STATE_DISCONNECTED, nil); err != nil {
+			return nil, err
+		}
+
+		return s.GetUserConnectorResourceByID(ctx, ns, userUid, id, connectorPB.View_VIEW_FULL, true)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if results[0].Status.Code() != codes.OK {
+		return nil, results[0].Status.Err()
+	}
+
+	return results[0].ConnectorResource, nil
+}
+
+// PurgeDeletedConnectorResources hard-deletes the connectors deleted longer than the retention ago, along with
+// their revisions, operations, executions, captures and dead letters
+func (s *service) PurgeDeletedConnectorResources(ctx context.Context) (int64, error) {
+
+	retentionDays := config.Config.DeletedConnector.RetentionDays
+	if retentionDays <= 0 {
+		retentionDays = defaultDeletedConnectorRetentionDays
+	}
+
+	return s.repository.PurgeDeletedConnectorResources(ctx, time.Now().AddDate(0, 0, -retentionDays))
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gofrs/uuid"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/instill-ai/connector-backend/internal/resource"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/utils"
+	"github.com/instill-ai/x/sterr"
+
+	componentBase "github.com/instill-ai/component/pkg/base"
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+// inputSchemaPath is the path of the schema of one input in the OpenAPI specification of a task
+var inputSchemaPath = []string{"paths", "/execute", "post", "requestBody", "content", "application/json", "schema", "properties", "inputs", "items"}
+
+// taskInputSchema returns the JSON schema of one input of the task of the connector definition, the task can
+// be omitted if the definition has a single one
+func taskInputSchema(def *connectorPB.ConnectorDefinition, task string) (string, error) {
+
+	specs := def.GetSpec().GetOpenapiSpecifications().GetFields()
+	if task == "" && len(specs) == 1 {
+		for k := range specs {
+			task = k
+		}
+	}
+
+	spec, ok := specs[task]
+	if !ok {
+		st, _ := sterr.CreateErrorBadRequest(
+			"[service] invalid task",
+			[]*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       "task",
+					Description: fmt.Sprintf("the connector definition %s has no task %q", def.GetId(), task),
+				},
+			},
+		)
+		return "", st.Err()
+	}
+
+	walk := spec.GetStructValue()
+	for _, key := range inputSchemaPath {
+		walk = walk.GetFields()[key].GetStructValue()
+	}
+	if walk == nil {
+		return "", status.Errorf(codes.Internal, "[service] the task %s of the connector definition %s has no input schema", task, def.GetId())
+	}
+
+	b, err := protojson.Marshal(walk)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// ValidateInputs checks the inputs against the input schema of the task without executing the connector, the
+// invalid inputs are reported as field violations, e.g. "inputs[1].text"
+func (s *service) ValidateInputs(ctx context.Context, ns resource.Namespace, userUid uuid.UUID, id string, task string, inputs []*structpb.Struct) error {
+
+	ownerPermalink := ns.String()
+	userPermalink := resource.UserUidToUserPermalink(userUid)
+
+	dbConnectorResource, err := s.checkConnectorPermission(ctx, ownerPermalink, userPermalink, id, datamodel.ConnectorRoleExecutor)
+	if err != nil {
+		return err
+	}
+
+	def, err := s.connectors.GetConnectorDefinitionByUID(dbConnectorResource.ConnectorDefinitionUID)
+	if err != nil {
+		return err
+	}
+
+	schema, err := taskInputSchema(def, task)
+	if err != nil {
+		return err
+	}
+
+	// The validation does not depend on the connector configuration
+	err = (&componentBase.Execution{}).Validate(inputs, schema, "inputs")
+	if err == nil {
+		return nil
+	}
+
+	violations := []*errdetails.BadRequest_FieldViolation{}
+	for _, part := range strings.Split(err.Error(), "; ") {
+		field, description, found := strings.Cut(part, ": ")
+		if !found || !inputErrorPattern.MatchString(field) {
+			return status.Errorf(codes.Internal, "[service] input validation error: %s", err.Error())
+		}
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       field,
+			Description: description,
+		})
+	}
+
+	st, _ := sterr.CreateErrorBadRequest("[service] invalid inputs", violations)
+	return st.Err()
+}
+
+// configurationSchemas caches the compiled resource specification of each connector definition by uid
+var configurationSchemas sync.Map
+
+// configurationSchema returns the compiled resource specification of the connector definition
+func configurationSchema(def *connectorPB.ConnectorDefinition) (*jsonschema.Schema, error) {
+
+	if schema, ok := configurationSchemas.Load(def.GetUid()); ok {
+		return schema.(*jsonschema.Schema), nil
+	}
+
+	b, err := protojson.Marshal(def.GetSpec().GetResourceSpecification())
+	if err != nil {
+		return nil, err
+	}
+	schema, err := jsonschema.CompileString(fmt.Sprintf("%s.json", def.GetId()), string(b))
+	if err != nil {
+		return nil, err
+	}
+
+	configurationSchemas.Store(def.GetUid(), schema)
+	return schema, nil
+}
+
+// secretReferenceAt tells whether the value at the JSON pointer of the configuration is a secret reference
+func secretReferenceAt(configuration *structpb.Struct, location string) bool {
+	if location == "" {
+		return false
+	}
+	v := structpb.NewStructValue(configuration)
+	for _, token := range strings.Split(strings.TrimPrefix(location, "/"), "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		if list := v.GetListValue(); list != nil {
+			idx, err := strconv.Atoi(token)
+			if err != nil || idx < 0 || idx >= len(list.GetValues()) {
+				return false
+			}
+			v = list.GetValues()[idx]
+			continue
+		}
+		v = v.GetStructValue().GetFields()[token]
+	}
+	_, ok := utils.GetSecretReference(v.GetStringValue())
+	return ok
+}
+
+// leafViolations lists the innermost causes of a validation error, they point at the invalid fields. The fields
+// holding a secret reference are skipped, their value is only known at execution time.
+func leafViolations(configuration *structpb.Struct, ve *jsonschema.ValidationError, violations []*errdetails.BadRequest_FieldViolation) []*errdetails.BadRequest_FieldViolation {
+	if len(ve.Causes) == 0 {
+		if secretReferenceAt(configuration, ve.InstanceLocation) {
+			return violations
+		}
+		return append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       "/configuration" + ve.InstanceLocation,
+			Description: ve.Message,
+		})
+	}
+	for _, cause := range ve.Causes {
+		violations = leafViolations(configuration, cause, violations)
+	}
+	return violations
+}
+
+// validateConfiguration checks the configuration of a connector against the resource specification of its
+// definition, the invalid fields are reported as field violations with their JSON pointer, e.g.
+// "/configuration/api_key". It must be given the plaintext configuration, the credential fields decrypted.
+func validateConfiguration(def *connectorPB.ConnectorDefinition, configuration *structpb.Struct) error {
+
+	if def.GetSpec().GetResourceSpecification() == nil {
+		return nil
+	}
+
+	schema, err := configurationSchema(def)
+	if err != nil {
+		return status.Errorf(codes.Internal, "[service] invalid resource specification of %s: %s", def.GetId(), err.Error())
+	}
+
+	if configuration == nil {
+		configuration = &structpb.Struct{}
+	}
+	b, err := protojson.Marshal(configuration)
+	if err != nil {
+		return err
+	}
+	// The numbers are kept as json.Number for the validator to tell integers apart
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return err
+	}
+
+	err = schema.Validate(v)
+	if err == nil {
+		return nil
+	}
+	ve, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return status.Errorf(codes.Internal, "[service] configuration validation error: %s", err.Error())
+	}
+
+	violations := leafViolations(configuration, ve, []*errdetails.BadRequest_FieldViolation{})
+	if len(violations) == 0 {
+		return nil
+	}
+	st, _ := sterr.CreateErrorBadRequest(
+		fmt.Sprintf("[service] invalid configuration for %s", def.GetId()),
+		violations,
+	)
+	return st.Err()
+}
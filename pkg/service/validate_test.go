@@ -0,0 +1,168 @@
+package service
+
+import (
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/instill-ai/connector-backend/pkg/encryption"
+
+	componentBase "github.com/instill-ai/component/pkg/base"
+	connectorPB "github.com/instill-ai/protogen-go/vdp/connector/v1alpha"
+)
+
+func TestValidateConfiguration(t *testing.T) {
+	spec, err := structpb.NewStruct(map[string]interface{}{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"type":    "object",
+		"required": []interface{}{
+			"api_key",
+		},
+		"properties": map[string]interface{}{
+			"api_key":    map[string]interface{}{"type": "string", "pattern": "^sk-", "maxLength": 20},
+			"max_tokens": map[string]interface{}{"type": "integer"},
+			"headers": map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"type": "string", "pattern": "^[A-Z]"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	def := &connectorPB.ConnectorDefinition{
+		Uid:  "9fb6a2cb-bff5-4c69-bc6d-4538dd8e3362",
+		Id:   "ai-test",
+		Spec: &connectorPB.Spec{ResourceSpecification: spec},
+	}
+
+	testCases := []struct {
+		name          string
+		configuration map[string]interface{}
+		wantFields    []string
+	}{
+		{
+			name:          "valid",
+			configuration: map[string]interface{}{"api_key": "sk-123", "max_tokens": 10},
+		},
+		{
+			name:          "secret reference",
+			configuration: map[string]interface{}{"api_key": "${secrets/openai-key}"},
+		},
+		{
+			name:          "secret reference in a list",
+			configuration: map[string]interface{}{"api_key": "sk-123", "headers": []interface{}{"Accept", "${secrets/header}"}},
+		},
+		{
+			name:          "invalid value",
+			configuration: map[string]interface{}{"api_key": "123", "max_tokens": 1.5},
+			wantFields:    []string{"/configuration/api_key", "/configuration/max_tokens"},
+		},
+		{
+			name:          "invalid value next to a secret reference",
+			configuration: map[string]interface{}{"api_key": "${secrets/openai-key}", "headers": []interface{}{"accept"}},
+			wantFields:    []string{"/configuration/headers/0"},
+		},
+		{
+			name:          "missing field",
+			configuration: map[string]interface{}{},
+			wantFields:    []string{"/configuration"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			configuration, err := structpb.NewStruct(tc.configuration)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = validateConfiguration(def, configuration)
+			if len(tc.wantFields) == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			st := status.Convert(err)
+			if st.Code() != codes.InvalidArgument {
+				t.Fatalf("got %v, want an invalid argument error", err)
+			}
+			fields := map[string]bool{}
+			for _, detail := range st.Details() {
+				if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+					for _, violation := range badRequest.GetFieldViolations() {
+						fields[violation.GetField()] = true
+					}
+				}
+			}
+			if len(fields) != len(tc.wantFields) {
+				t.Fatalf("got violations %v, want %v", fields, tc.wantFields)
+			}
+			for _, field := range tc.wantFields {
+				if !fields[field] {
+					t.Errorf("got violations %v, want %v", fields, tc.wantFields)
+				}
+			}
+		})
+	}
+}
+
+// fakeConnectors marks the api_key fields as credentials
+type fakeConnectors struct {
+	componentBase.IConnector
+}
+
+func (c *fakeConnectors) IsCredentialField(defID string, target string) bool {
+	return target == "api_key"
+}
+
+func TestValidateDecryptedConfiguration(t *testing.T) {
+	spec, err := structpb.NewStruct(map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"api_key": map[string]interface{}{"type": "string", "pattern": "^sk-", "maxLength": 20},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	def := &connectorPB.ConnectorDefinition{
+		Uid:  "0f2b4c1e-7a0a-4b8e-9d43-5c5a4c1f6d2e",
+		Id:   "ai-test-credential",
+		Spec: &connectorPB.Spec{ResourceSpecification: spec},
+	}
+
+	provider, err := encryption.NewLocalKeyProvider(filepath.Join(t.TempDir(), "kek"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &service{connectors: &fakeConnectors{}, envelope: encryption.NewEnvelope(provider)}
+
+	// A configuration merged with the stored one keeps the encrypted credential
+	configuration, err := structpb.NewStruct(map[string]interface{}{"api_key": "sk-123"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.encryptConfiguration(def.GetId(), configuration); err != nil {
+		t.Fatal(err)
+	}
+	if err := validateConfiguration(def, configuration); err == nil {
+		t.Fatal("the encrypted credential is valid, the test does not cover the decryption")
+	}
+
+	plain, err := s.decryptConfiguration(def.GetId(), configuration)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := validateConfiguration(def, plain); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if !encryption.IsEncrypted(configuration.GetFields()["api_key"].GetStringValue()) {
+		t.Error("the configuration is decrypted in place")
+	}
+}
@@ -2,12 +2,17 @@ package utils
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/influxdata/influxdb-client-go/v2/api/write"
 	"github.com/instill-ai/connector-backend/config"
 	"github.com/instill-ai/connector-backend/internal/resource"
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+	"github.com/instill-ai/connector-backend/pkg/encryption"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/structpb"
 
 	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
@@ -26,7 +31,7 @@ const (
 	DisconnectEvent      string = "Disconnect"
 	RenameEvent          string = "Rename"
 	ExecuteEvent         string = "Execute"
-	credentialMaskString string = "*****MASK*****"
+	CredentialMaskString string = "*****MASK*****"
 )
 
 func IsAuditEvent(eventName string) bool {
@@ -53,6 +58,26 @@ type UsageMetricData struct {
 	ConnectorDefinitionUid string
 	ExecuteTime            string
 	ComputeTimeDuration    float64
+	SucceededCount         int
+	FailedCount            int
+	Attempts               int
+	CacheHit               bool
+	Task                   string
+	ErrorMessage           string
+	InputSize              int
+	OutputSize             int
+}
+
+// PayloadSize returns the number of bytes of the inputs or outputs of an execution in their protobuf encoding
+func PayloadSize(payload []*structpb.Struct) int {
+	size := 0
+	for _, item := range payload {
+		// The output of a failed input is nil
+		if item != nil {
+			size += proto.Size(item)
+		}
+	}
+	return size
 }
 
 func NewDataPoint(data UsageMetricData, pipelineMetadata *structpb.Value) *write.Point {
@@ -76,6 +101,10 @@ func NewDataPoint(data UsageMetricData, pipelineMetadata *structpb.Value) *write
 			"connector_execute_id":     data.ConnectorExecuteUID,
 			"execute_time":             data.ExecuteTime,
 			"compute_time_duration":    data.ComputeTimeDuration,
+			"succeeded_count":          data.SucceededCount,
+			"failed_count":             data.FailedCount,
+			"attempts":                 data.Attempts,
+			"cache_hit":                data.CacheHit,
 		},
 		time.Now(),
 	)
@@ -90,7 +119,10 @@ func maskCredentialFields(connector componentBase.IConnector, defId string, conf
 	for k, v := range config.GetFields() {
 		key := prefix + k
 		if connector.IsCredentialField(defId, key) {
-			config.GetFields()[k] = structpb.NewStringValue(credentialMaskString)
+			// A secret reference reveals which secret is used, not its value
+			if _, ok := GetSecretReference(v.GetStringValue()); !ok {
+				config.GetFields()[k] = structpb.NewStringValue(CredentialMaskString)
+			}
 		}
 		if v.GetStructValue() != nil {
 			maskCredentialFields(connector, defId, v.GetStructValue(), fmt.Sprintf("%s.", key))
@@ -99,6 +131,32 @@ func maskCredentialFields(connector componentBase.IConnector, defId string, conf
 	}
 }
 
+// credentialLikeFieldRegexp matches the payload fields whose name suggests they hold a credential
+var credentialLikeFieldRegexp = regexp.MustCompile(`(?i)(password|passwd|secret|token|api_?key|credential|authorization|private_?key)`)
+
+// RedactCredentialLikeFields masks the values of the fields of an execution payload whose name suggests they
+// hold a credential, the payloads have no schema telling the credential fields apart
+func RedactCredentialLikeFields(payload *structpb.Struct) {
+	for k, v := range payload.GetFields() {
+		if credentialLikeFieldRegexp.MatchString(k) {
+			payload.GetFields()[k] = structpb.NewStringValue(CredentialMaskString)
+			continue
+		}
+		redactCredentialLikeValue(v)
+	}
+}
+
+func redactCredentialLikeValue(v *structpb.Value) {
+	switch {
+	case v.GetStructValue() != nil:
+		RedactCredentialLikeFields(v.GetStructValue())
+	case v.GetListValue() != nil:
+		for _, item := range v.GetListValue().GetValues() {
+			redactCredentialLikeValue(item)
+		}
+	}
+}
+
 func RemoveCredentialFieldsWithMaskString(connector componentBase.IConnector, defId string, config *structpb.Struct) {
 	removeCredentialFieldsWithMaskString(connector, defId, config, "")
 }
@@ -112,7 +170,7 @@ func removeCredentialFieldsWithMaskString(connector componentBase.IConnector, de
 	for k, v := range config.GetFields() {
 		key := prefix + k
 		if connector.IsCredentialField(defId, key) {
-			if v.GetStringValue() == credentialMaskString {
+			if v.GetStringValue() == CredentialMaskString {
 				delete(config.GetFields(), k)
 			}
 		}
@@ -136,6 +194,177 @@ func keepCredentialFieldsWithMaskString(connector componentBase.IConnector, defI
 	}
 }
 
+// EncryptCredentialFields encrypts the string values of the credential fields in place
+func EncryptCredentialFields(connector componentBase.IConnector, defId string, config *structpb.Struct, envelope *encryption.Envelope) error {
+	return transformCredentialFields(connector, defId, config, "", func(value string) (string, error) {
+		// Secret references are stored as is and resolved at execution time
+		if _, ok := GetSecretReference(value); ok {
+			return value, nil
+		}
+		return envelope.Encrypt(value)
+	})
+}
+
+// DecryptCredentialFields decrypts the string values of the credential fields in place
+func DecryptCredentialFields(connector componentBase.IConnector, defId string, config *structpb.Struct, envelope *encryption.Envelope) error {
+	return transformCredentialFields(connector, defId, config, "", envelope.Decrypt)
+}
+
+// EncryptedCredentialFields returns the dotted paths of the credential fields holding an encrypted value
+func EncryptedCredentialFields(connector componentBase.IConnector, defId string, config *structpb.Struct) []string {
+	fields := []string{}
+	var walk func(config *structpb.Struct, prefix string)
+	walk = func(config *structpb.Struct, prefix string) {
+		for k, v := range config.GetFields() {
+			key := prefix + k
+			if connector.IsCredentialField(defId, key) && encryption.IsEncrypted(v.GetStringValue()) {
+				fields = append(fields, key)
+			}
+			if v.GetStructValue() != nil {
+				walk(v.GetStructValue(), fmt.Sprintf("%s.", key))
+			}
+		}
+	}
+	walk(config, "")
+	sort.Strings(fields)
+	return fields
+}
+
+func transformCredentialFields(connector componentBase.IConnector, defId string, config *structpb.Struct, prefix string, transform func(string) (string, error)) error {
+
+	for k, v := range config.GetFields() {
+		key := prefix + k
+		if connector.IsCredentialField(defId, key) {
+			if str, ok := v.GetKind().(*structpb.Value_StringValue); ok {
+				value, err := transform(str.StringValue)
+				if err != nil {
+					return fmt.Errorf("credential field %s: %w", key, err)
+				}
+				config.GetFields()[k] = structpb.NewStringValue(value)
+			}
+		}
+		if v.GetStructValue() != nil {
+			if err := transformCredentialFields(connector, defId, v.GetStructValue(), fmt.Sprintf("%s.", key), transform); err != nil {
+				return err
+			}
+		}
+
+	}
+	return nil
+}
+
+// secretReferencePattern matches a configuration value referring to a namespace secret, e.g. ${secrets/openai-api-key}
+var secretReferencePattern = regexp.MustCompile(`^\$\{secrets/([a-z0-9-]+)\}$`)
+
+// SecretReference returns the configuration value referring to the secret id
+func SecretReference(id string) string {
+	return fmt.Sprintf("${secrets/%s}", id)
+}
+
+// GetSecretReference returns the secret id if the value is a secret reference
+func GetSecretReference(value string) (string, bool) {
+	matches := secretReferencePattern.FindStringSubmatch(value)
+	if matches == nil {
+		return "", false
+	}
+	return matches[1], true
+}
+
+// ResolveSecretReferences replaces in place every secret reference in the configuration by the value returned by lookUp
+func ResolveSecretReferences(config *structpb.Struct, lookUp func(id string) (string, error)) error {
+	for k, v := range config.GetFields() {
+		resolved, err := resolveSecretReferences(v, lookUp)
+		if err != nil {
+			return err
+		}
+		config.GetFields()[k] = resolved
+	}
+	return nil
+}
+
+func resolveSecretReferences(v *structpb.Value, lookUp func(id string) (string, error)) (*structpb.Value, error) {
+	switch kind := v.GetKind().(type) {
+	case *structpb.Value_StringValue:
+		if id, ok := GetSecretReference(kind.StringValue); ok {
+			value, err := lookUp(id)
+			if err != nil {
+				return nil, err
+			}
+			return structpb.NewStringValue(value), nil
+		}
+	case *structpb.Value_StructValue:
+		if err := ResolveSecretReferences(kind.StructValue, lookUp); err != nil {
+			return nil, err
+		}
+	case *structpb.Value_ListValue:
+		for idx, item := range kind.ListValue.GetValues() {
+			resolved, err := resolveSecretReferences(item, lookUp)
+			if err != nil {
+				return nil, err
+			}
+			kind.ListValue.GetValues()[idx] = resolved
+		}
+	}
+	return v, nil
+}
+
+// DiffStruct lists the fields that differ between two structs. Nested structs are compared field by field
+// and the changed fields are named by their dotted path after the prefix, other values are compared as a whole.
+func DiffStruct(prefix string, oldStruct *structpb.Struct, newStruct *structpb.Struct) []datamodel.FieldChange {
+
+	keys := []string{}
+	for k := range oldStruct.GetFields() {
+		keys = append(keys, k)
+	}
+	for k := range newStruct.GetFields() {
+		if _, ok := oldStruct.GetFields()[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	changes := []datamodel.FieldChange{}
+	for _, k := range keys {
+		key := prefix + k
+		oldValue, hasOld := oldStruct.GetFields()[k]
+		newValue, hasNew := newStruct.GetFields()[k]
+		switch {
+		case !hasOld:
+			changes = append(changes, datamodel.FieldChange{Field: key, Op: datamodel.FieldChangeAdd, NewValue: newValue.AsInterface()})
+		case !hasNew:
+			changes = append(changes, datamodel.FieldChange{Field: key, Op: datamodel.FieldChangeRemove, OldValue: oldValue.AsInterface()})
+		case oldValue.GetStructValue() != nil && newValue.GetStructValue() != nil:
+			changes = append(changes, DiffStruct(fmt.Sprintf("%s.", key), oldValue.GetStructValue(), newValue.GetStructValue())...)
+		case !proto.Equal(oldValue, newValue):
+			changes = append(changes, datamodel.FieldChange{Field: key, Op: datamodel.FieldChangeReplace, OldValue: oldValue.AsInterface(), NewValue: newValue.AsInterface()})
+		}
+	}
+	return changes
+}
+
+// LookUpField returns the value at the dotted path of the struct
+func LookUpField(config *structpb.Struct, path string) (*structpb.Value, bool) {
+	parent, key, ok := LookUpParentField(config, path)
+	if !ok {
+		return nil, false
+	}
+	v, ok := parent.GetFields()[key]
+	return v, ok
+}
+
+// LookUpParentField returns the struct holding the last key of the dotted path, along with that key, so that the
+// value can be set. It returns false if a key before the last one does not hold a struct.
+func LookUpParentField(config *structpb.Struct, path string) (*structpb.Struct, string, bool) {
+	keys := strings.Split(path, ".")
+	for _, key := range keys[:len(keys)-1] {
+		config = config.GetFields()[key].GetStructValue()
+		if config == nil {
+			return nil, "", false
+		}
+	}
+	return config, keys[len(keys)-1], true
+}
+
 func GetConnectorOptions() connector.ConnectorOptions {
 	return connector.ConnectorOptions{
 		Airbyte: connectorAirbyte.ConnectorOptions{
@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/instill-ai/connector-backend/pkg/datamodel"
+)
+
+func TestDiffStruct(t *testing.T) {
+	testCases := []struct {
+		name     string
+		previous map[string]interface{}
+		current  map[string]interface{}
+		want     []datamodel.FieldChange
+	}{
+		{
+			name:     "equal",
+			previous: map[string]interface{}{"model": "gpt-4", "options": map[string]interface{}{"temperature": 0.5}},
+			current:  map[string]interface{}{"model": "gpt-4", "options": map[string]interface{}{"temperature": 0.5}},
+			want:     []datamodel.FieldChange{},
+		},
+		{
+			name:     "add, remove and replace",
+			previous: map[string]interface{}{"model": "gpt-3.5-turbo", "org": "instill"},
+			current:  map[string]interface{}{"model": "gpt-4", "max_tokens": 100},
+			want: []datamodel.FieldChange{
+				{Field: "configuration.max_tokens", Op: datamodel.FieldChangeAdd, NewValue: float64(100)},
+				{Field: "configuration.model", Op: datamodel.FieldChangeReplace, OldValue: "gpt-3.5-turbo", NewValue: "gpt-4"},
+				{Field: "configuration.org", Op: datamodel.FieldChangeRemove, OldValue: "instill"},
+			},
+		},
+		{
+			name:     "nested fields",
+			previous: map[string]interface{}{"options": map[string]interface{}{"temperature": 0.5, "top_p": 1}},
+			current:  map[string]interface{}{"options": map[string]interface{}{"temperature": 0.7, "top_p": 1}},
+			want: []datamodel.FieldChange{
+				{Field: "configuration.options.temperature", Op: datamodel.FieldChangeReplace, OldValue: 0.5, NewValue: 0.7},
+			},
+		},
+		{
+			name:     "lists compared as a whole",
+			previous: map[string]interface{}{"stop": []interface{}{"a", "b"}},
+			current:  map[string]interface{}{"stop": []interface{}{"a"}},
+			want: []datamodel.FieldChange{
+				{Field: "configuration.stop", Op: datamodel.FieldChangeReplace, OldValue: []interface{}{"a", "b"}, NewValue: []interface{}{"a"}},
+			},
+		},
+		{
+			name:     "struct replaced by a value",
+			previous: map[string]interface{}{"auth": map[string]interface{}{"token": "x"}},
+			current:  map[string]interface{}{"auth": "none"},
+			want: []datamodel.FieldChange{
+				{Field: "configuration.auth", Op: datamodel.FieldChangeReplace, OldValue: map[string]interface{}{"token": "x"}, NewValue: "none"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			previous, err := structpb.NewStruct(tc.previous)
+			if err != nil {
+				t.Fatal(err)
+			}
+			current, err := structpb.NewStruct(tc.current)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := DiffStruct("configuration.", previous, current); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLookUpField(t *testing.T) {
+	config, err := structpb.NewStruct(map[string]interface{}{
+		"api_key": "key",
+		"options": map[string]interface{}{"auth": map[string]interface{}{"token": "x"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		path   string
+		want   interface{}
+		wantOK bool
+	}{
+		{path: "api_key", want: "key", wantOK: true},
+		{path: "options.auth.token", want: "x", wantOK: true},
+		{path: "options.auth.missing", wantOK: false},
+		{path: "api_key.token", wantOK: false},
+		{path: "missing.token", wantOK: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			v, ok := LookUpField(config, tc.path)
+			if ok != tc.wantOK {
+				t.Fatalf("got found %v, want %v", ok, tc.wantOK)
+			}
+			if ok && !reflect.DeepEqual(v.AsInterface(), tc.want) {
+				t.Errorf("got %v, want %v", v.AsInterface(), tc.want)
+			}
+		})
+	}
+}
+
+func TestLookUpParentField(t *testing.T) {
+	config, err := structpb.NewStruct(map[string]interface{}{
+		"api_key": "key",
+		"options": map[string]interface{}{"auth": map[string]interface{}{"token": "x"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parent, key, ok := LookUpParentField(config, "options.auth.token")
+	if !ok || key != "token" {
+		t.Fatalf("got %q, %v, want the token key", key, ok)
+	}
+	parent.GetFields()[key] = structpb.NewStringValue("y")
+	if v, _ := LookUpField(config, "options.auth.token"); v.GetStringValue() != "y" {
+		t.Errorf("got %v, want the value set through the parent", v)
+	}
+
+	// A missing last key still has a parent to be set in
+	if _, key, ok := LookUpParentField(config, "options.auth.missing"); !ok || key != "missing" {
+		t.Errorf("got %q, %v, want the missing key", key, ok)
+	}
+	if _, _, ok := LookUpParentField(config, "api_key.token"); ok {
+		t.Error("got a parent under a string value")
+	}
+}